@@ -26,6 +26,32 @@ type ResourceType string
 // TypeToCategory is a map between resource type and resource categorization.
 type TypeToCategory map[ResourceType]ResourceCategory
 
+// UncategorizedCategory is the category of resource types without a known category.
+const UncategorizedCategory = "uncategorized"
+
+// resourceTypeToCategory is a map between the resource types of all supported providers and their categorization.
+var resourceTypeToCategory = mergeResourceCategories(awsResourceCategories(), googleResourceCategories(), azureResourceCategories())
+
+// PrimaryCategory returns the primary category of a Terraform resource type, such as "networking" or "storage",
+// or UncategorizedCategory when the resource type has no known category.
+func PrimaryCategory(resourceType string) string {
+	if category, ok := resourceTypeToCategory[ResourceType(resourceType)]; ok && category.primaryCat != "" {
+		return category.primaryCat
+	}
+	return UncategorizedCategory
+}
+
+// mergeResourceCategories merges the categorizations of each provider into a single map.
+func mergeResourceCategories(providerCategories ...TypeToCategory) TypeToCategory {
+	merged := TypeToCategory{}
+	for _, typeToCategory := range providerCategories {
+		for resourceType, category := range typeToCategory {
+			merged[resourceType] = category
+		}
+	}
+	return merged
+}
+
 // Workspace is the name of a Terraform Cloud workspace.
 type Workspace string
 
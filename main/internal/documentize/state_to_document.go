@@ -5,6 +5,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/Jeffail/gabs/v2"
 )
@@ -89,7 +90,8 @@ func (d *documentize) workspaceDocFromTFState(tfStateParsed *gabs.Container) (st
 	return resourceDetails, nil
 }
 
-// regexProviderName extracts the terraform provider name from a string.
+// regexProviderName extracts the terraform provider name from a string. OpenTofu registry addresses are normalized to
+// the equivalent Terraform registry address.
 func regexProviderName(rawProvider string) (string, error) {
 	r, err := regexp.Compile(`\.?(provider.*]).*$`)
 	if err != nil {
@@ -98,5 +100,5 @@ func regexProviderName(rawProvider string) (string, error) {
 
 	output := r.FindStringSubmatch(rawProvider)[1]
 
-	return output, nil
+	return strings.Replace(output, "registry.opentofu.org/", "registry.terraform.io/", 1), nil
 }
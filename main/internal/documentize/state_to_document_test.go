@@ -67,6 +67,17 @@ func TestRegexProviderName(t *testing.T) {
 	if actualOutput != expectedOutput {
 		t.Errorf("got %v, expected %v", actualOutput, expectedOutput)
 	}
+
+	inputProvider = `provider["registry.opentofu.org/hashicorp/aws"]`
+
+	actualOutput, err = regexProviderName(inputProvider)
+	if err != nil {
+		t.Errorf("Unexpected error in regexProviderName: %v", err)
+	}
+
+	if actualOutput != expectedOutput {
+		t.Errorf("got %v, expected %v", actualOutput, expectedOutput)
+	}
 }
 
 func TestWorkspaceDocFromTFState(t *testing.T) {
@@ -0,0 +1,204 @@
+package hclcreate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ProviderSchemas represents the subset of `terraform providers schema -json` output needed to identify
+// computed attributes.
+type ProviderSchemas struct {
+	ProviderSchemas map[string]ProviderSchema `json:"provider_schemas"`
+}
+
+// ProviderSchema contains the schema of each resource type for a single provider.
+type ProviderSchema struct {
+	ResourceSchemas map[string]ResourceSchema `json:"resource_schemas"`
+}
+
+// ResourceSchema is the schema of a single resource type.
+type ResourceSchema struct {
+	Block SchemaBlock `json:"block"`
+}
+
+// SchemaBlock is the schema of a resource's top level body, or of a nested block within it.
+type SchemaBlock struct {
+	Attributes map[string]SchemaAttribute `json:"attributes"`
+	BlockTypes map[string]SchemaBlockType `json:"block_types"`
+}
+
+// SchemaAttribute describes whether an attribute may be set within configuration and whether it is computed.
+type SchemaAttribute struct {
+	Required bool `json:"required"`
+	Optional bool `json:"optional"`
+	Computed bool `json:"computed"`
+}
+
+// SchemaBlockType is the schema of a nested block.
+type SchemaBlockType struct {
+	Block SchemaBlock `json:"block"`
+}
+
+// ResourceTypeToSchema is a map between resource types and the schema of that resource type.
+type ResourceTypeToSchema map[string]SchemaBlock
+
+// alwaysComputedAttributes are top level attributes which are never useful within generated configuration,
+// including attributes which legacy provider schemas mark as optional, such as "id".
+var alwaysComputedAttributes = map[string]bool{
+	"id":       true,
+	"tags_all": true,
+}
+
+// fallbackComputedAttributes are top level attributes treated as computed when no provider schema is available.
+var fallbackComputedAttributes = map[string]bool{
+	"arn":                true,
+	"create_date":        true,
+	"creation_timestamp": true,
+	"etag":               true,
+	"owner_id":           true,
+	"self_link":          true,
+	"unique_id":          true,
+}
+
+// resourceAttributeDefaults are known provider defaults for top level attributes, keyed by resource type and then
+// attribute name. Provider schemas do not include defaults, so only attributes set to a default listed here are
+// removed, as a zero value may differ from the provider's default.
+var resourceAttributeDefaults = map[string]map[string]string{
+	"aws_dynamodb_table":        {"billing_mode": "PROVISIONED"},
+	"aws_iam_policy":            {"path": "/"},
+	"aws_iam_role":              {"path": "/", "max_session_duration": "3600", "force_detach_policies": "false"},
+	"aws_iam_user":              {"path": "/", "force_destroy": "false"},
+	"aws_instance":              {"source_dest_check": "true", "monitoring": "false", "get_password_data": "false"},
+	"aws_lambda_function":       {"memory_size": "128", "timeout": "3", "package_type": "Zip", "publish": "false"},
+	"aws_lb":                    {"enable_http2": "true", "idle_timeout": "60", "ip_address_type": "ipv4", "enable_deletion_protection": "false"},
+	"aws_s3_bucket":             {"force_destroy": "false"},
+	"aws_security_group":        {"revoke_rules_on_delete": "false"},
+	"aws_sqs_queue":             {"max_message_size": "262144", "message_retention_seconds": "345600", "visibility_timeout_seconds": "30", "delay_seconds": "0", "fifo_queue": "false"},
+	"azurerm_storage_account":   {"account_kind": "StorageV2", "enable_https_traffic_only": "true"},
+	"google_compute_instance":   {"can_ip_forward": "false"},
+	"google_storage_bucket":     {"storage_class": "STANDARD"},
+	"google_compute_subnetwork": {"purpose": "PRIVATE"},
+}
+
+// loadResourceSchemas reads the resource schemas of the providers initialized within current_cloud/. If the schemas
+// cannot be read, an empty map is returned and attributes are cleaned using built-in defaults instead.
+func (h *hclCreate) loadResourceSchemas() ResourceTypeToSchema {
+	resourceTypeToSchema := ResourceTypeToSchema{}
+
+	cmd := exec.Command(h.config.Runtime.Binary(), "providers", "schema", "-json")
+	cmd.Dir = "current_cloud"
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	err := cmd.Run()
+	if err != nil {
+		logrus.Warnf("[hclcreate][loadResourceSchemas] unable to read provider schemas, using built-in defaults: %v", err)
+		return resourceTypeToSchema
+	}
+
+	var providerSchemas ProviderSchemas
+	err = json.Unmarshal(out.Bytes(), &providerSchemas)
+	if err != nil {
+		logrus.Warnf("[hclcreate][loadResourceSchemas] unable to parse provider schemas, using built-in defaults: %v", err)
+		return resourceTypeToSchema
+	}
+
+	for _, providerSchema := range providerSchemas.ProviderSchemas {
+		for resourceType, resourceSchema := range providerSchema.ResourceSchemas {
+			resourceTypeToSchema[resourceType] = resourceSchema.Block
+		}
+	}
+
+	return resourceTypeToSchema
+}
+
+// cleanResourceBlock removes computed-only and default-valued attributes from a terraformer generated resource
+// block, along with any nested blocks left empty as a result, so that the imported resource plans with no changes.
+func (h *hclCreate) cleanResourceBlock(block *hclwrite.Block, resourceTypeToSchema ResourceTypeToSchema) {
+	resourceType := block.Labels()[0]
+	schema, hasSchema := resourceTypeToSchema[resourceType]
+
+	body := block.Body()
+	for name, attribute := range body.Attributes() {
+		if alwaysComputedAttributes[name] || (!hasSchema && fallbackComputedAttributes[name]) {
+			body.RemoveAttribute(name)
+			continue
+		}
+
+		if defaultValue, ok := resourceAttributeDefaults[resourceType][name]; ok && attributeEquals(attribute, defaultValue) {
+			body.RemoveAttribute(name)
+		}
+	}
+
+	if hasSchema {
+		cleanBody(body, schema)
+	}
+}
+
+// cleanBody removes attributes which are computed-only, or which are optional and set to an empty collection, from the
+// body and its nested blocks. Nested blocks which are empty after cleaning are removed. Other zero values are kept, as
+// the provider's default for the attribute is unknown.
+func cleanBody(body *hclwrite.Body, schema SchemaBlock) {
+	for name, attribute := range body.Attributes() {
+		attributeSchema, ok := schema.Attributes[name]
+		if !ok {
+			continue
+		}
+
+		isComputedOnly := attributeSchema.Computed && !attributeSchema.Optional && !attributeSchema.Required
+		isUnsetOptional := attributeSchema.Optional && !attributeSchema.Computed && isEmptyCollection(attribute)
+		if isComputedOnly || isUnsetOptional {
+			body.RemoveAttribute(name)
+		}
+	}
+
+	for _, nestedBlock := range body.Blocks() {
+		if blockType, ok := schema.BlockTypes[nestedBlock.Type()]; ok {
+			cleanBody(nestedBlock.Body(), blockType.Block)
+		}
+
+		if len(nestedBlock.Body().Attributes()) == 0 && len(nestedBlock.Body().Blocks()) == 0 {
+			body.RemoveBlock(nestedBlock)
+		}
+	}
+}
+
+// attributeEquals returns true if the attribute is a literal whose value, converted to a string, equals value.
+func attributeEquals(attribute *hclwrite.Attribute, value string) bool {
+	attributeValue, ok := literalValue(attribute)
+	if !ok || !attributeValue.Type().IsPrimitiveType() {
+		return false
+	}
+
+	return literalString(attributeValue) == value
+}
+
+// isEmptyCollection returns true if the attribute is a literal set to an empty collection, which providers treat the
+// same as an unset collection.
+func isEmptyCollection(attribute *hclwrite.Attribute) bool {
+	value, ok := literalValue(attribute)
+	if !ok || value.Type().IsPrimitiveType() {
+		return false
+	}
+
+	return value.CanIterateElements() && value.LengthInt() == 0
+}
+
+// literalString converts a known primitive value into its string representation.
+func literalString(value cty.Value) string {
+	switch value.Type() {
+	case cty.Bool:
+		return fmt.Sprintf("%v", value.True())
+	case cty.Number:
+		return value.AsBigFloat().Text('f', -1)
+	default:
+		return value.AsString()
+	}
+}
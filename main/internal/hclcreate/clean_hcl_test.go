@@ -0,0 +1,138 @@
+package hclcreate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanResourceBlock(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_lb" "web" {
+  arn                        = "arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/app/web/1"
+  id                         = "arn:aws:elasticloadbalancing:us-east-1:123:loadbalancer/app/web/1"
+  enable_deletion_protection = "false"
+  idle_timeout               = "60"
+  internal                   = "true"
+  name                       = "web"
+  security_groups            = []
+  tags_all = {
+    Name = "web"
+  }
+  access_logs {
+    enabled = "false"
+    bucket  = ""
+  }
+  subnet_mapping {
+    subnet_id = "subnet-1"
+  }
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diagnostics.HasErrors())
+
+	resourceTypeToSchema := ResourceTypeToSchema{
+		"aws_lb": {
+			Attributes: map[string]SchemaAttribute{
+				"arn":                        {Computed: true},
+				"id":                         {Optional: true, Computed: true},
+				"enable_deletion_protection": {Optional: true},
+				"idle_timeout":               {Optional: true},
+				"internal":                   {Optional: true, Computed: true},
+				"name":                       {Optional: true, Computed: true},
+				"security_groups":            {Optional: true},
+				"tags_all":                   {Optional: true, Computed: true},
+			},
+			BlockTypes: map[string]SchemaBlockType{
+				"access_logs": {Block: SchemaBlock{Attributes: map[string]SchemaAttribute{
+					"enabled": {Optional: true},
+					"bucket":  {Optional: true},
+				}}},
+				"subnet_mapping": {Block: SchemaBlock{Attributes: map[string]SchemaAttribute{
+					"subnet_id": {Required: true},
+				}}},
+			},
+		},
+	}
+
+	// When
+	h.cleanResourceBlock(hclFile.Body().Blocks()[0], resourceTypeToSchema)
+
+	// Then
+	expected := `resource "aws_lb" "web" {
+  internal = "true"
+  name     = "web"
+  access_logs {
+    enabled = "false"
+    bucket  = ""
+  }
+  subnet_mapping {
+    subnet_id = "subnet-1"
+  }
+}
+`
+	assert.Equal(t, expected, string(hclwrite.Format(hclFile.Bytes())))
+}
+
+func TestCleanResourceBlock_WithoutSchema(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_iam_role" "web" {
+  arn                  = "arn:aws:iam::123:role/web"
+  max_session_duration = "3600"
+  name                 = "web"
+  path                 = "/service/"
+  unique_id            = "AROA123"
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diagnostics.HasErrors())
+
+	// When
+	h.cleanResourceBlock(hclFile.Body().Blocks()[0], ResourceTypeToSchema{})
+
+	// Then
+	expected := `resource "aws_iam_role" "web" {
+  name = "web"
+  path = "/service/"
+}
+`
+	assert.Equal(t, expected, string(hclwrite.Format(hclFile.Bytes())))
+}
+
+func TestCleanResourceBlock_NonZeroDefault(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_instance" "nat" {
+  ami               = "ami-123"
+  ebs_optimized     = "false"
+  monitoring        = "false"
+  source_dest_check = "false"
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diagnostics.HasErrors())
+
+	resourceTypeToSchema := ResourceTypeToSchema{
+		"aws_instance": {
+			Attributes: map[string]SchemaAttribute{
+				"ami":               {Optional: true, Computed: true},
+				"ebs_optimized":     {Optional: true},
+				"monitoring":        {Optional: true},
+				"source_dest_check": {Optional: true},
+			},
+		},
+	}
+
+	// When
+	h.cleanResourceBlock(hclFile.Body().Blocks()[0], resourceTypeToSchema)
+
+	// Then
+	expected := `resource "aws_instance" "nat" {
+  ami               = "ami-123"
+  ebs_optimized     = "false"
+  source_dest_check = "false"
+}
+`
+	assert.Equal(t, expected, string(hclwrite.Format(hclFile.Bytes())))
+}
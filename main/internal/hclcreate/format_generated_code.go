@@ -0,0 +1,288 @@
+package hclcreate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// HCLOutputFormat writes generated code using Terraform's native HCL syntax.
+	HCLOutputFormat = "hcl"
+
+	// JSONOutputFormat writes generated code using Terraform's JSON syntax, as .tf.json files.
+	JSONOutputFormat = "json"
+)
+
+// blockTypeToReferenceAttributes is a map between block types and their meta-arguments whose values are references
+// rather than expressions, which JSON syntax writes as bare strings rather than "${...}" interpolations.
+var blockTypeToReferenceAttributes = map[string]map[string]bool{
+	"resource":  {"provider": true, "depends_on": true},
+	"data":      {"provider": true, "depends_on": true},
+	"module":    {"depends_on": true},
+	"import":    {"to": true, "provider": true},
+	"removed":   {"from": true},
+	"moved":     {"from": true, "to": true},
+	"lifecycle": {"ignore_changes": true, "replace_triggered_by": true},
+}
+
+// FormatGeneratedCode rewrites the new resources, import blocks and removed blocks generated within each workspace
+// using JSON syntax when configured to. HCL output is left unchanged.
+func (h *hclCreate) FormatGeneratedCode(workspaceToDirectory map[string]string) error {
+	if h.config.OutputFormat != JSONOutputFormat {
+		return nil
+	}
+
+	for _, workspace := range sortedKeys(workspaceToDirectory) {
+		directory := fmt.Sprintf("repo%v", workspaceToDirectory[workspace])
+
+		generatedFiles := []string{filepath.Join(directory, "new-resources.tf")}
+		for _, generatedDirectory := range []string{"imports", "removed"} {
+			files, err := filepath.Glob(filepath.Join(directory, "cloud-concierge", generatedDirectory, "*.tf"))
+			if err != nil {
+				return fmt.Errorf("[filepath.Glob]%v", err)
+			}
+			generatedFiles = append(generatedFiles, files...)
+		}
+
+		for _, generatedFile := range generatedFiles {
+			err := convertFileToJSON(generatedFile)
+			if err != nil {
+				return fmt.Errorf("[convertFileToJSON %v]%v", generatedFile, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// convertFileToJSON replaces a .tf file with the equivalent .tf.json file, if the .tf file exists.
+func convertFileToJSON(path string) error {
+	hclBytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("[os.ReadFile]%v", err)
+	}
+
+	jsonBytes, err := hclToJSON(hclBytes, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("[hclToJSON]%v", err)
+	}
+
+	err = os.WriteFile(path+".json", jsonBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile %v.json]%v", path, err)
+	}
+
+	return os.Remove(path)
+}
+
+// hclToJSON converts native HCL syntax into Terraform's JSON syntax. Literal values are written as JSON values,
+// other expressions as "${...}" interpolations, and the comments directly above each top level block as its "//" key.
+func hclToJSON(src []byte, filename string) ([]byte, error) {
+	file, diagnostics := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("[hclsyntax.ParseConfig]%v", diagnostics)
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("[unexpected body type %T]", file.Body)
+	}
+
+	converted := bodyToJSON(body, "", src)
+
+	buffer := bytes.Buffer{}
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(converted)
+	if err != nil {
+		return nil, fmt.Errorf("[encoder.Encode]%v", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// bodyToJSON converts a body of the given block type into a JSON object. Labelled blocks are nested by label, while
+// unlabelled blocks are written as arrays of objects. Top level blocks keep the comments directly above them.
+func bodyToJSON(body *hclsyntax.Body, blockType string, src []byte) map[string]interface{} {
+	output := map[string]interface{}{}
+
+	for name, attribute := range body.Attributes {
+		if blockTypeToReferenceAttributes[blockType][name] {
+			output[name] = referenceToJSON(attribute.Expr, src)
+			continue
+		}
+		output[name] = expressionToJSON(attribute.Expr, src)
+	}
+
+	for _, block := range body.Blocks {
+		blockJSON := bodyToJSON(block.Body, block.Type, src)
+		if blockType == "" {
+			if comment := leadingComment(src, block.Range().Start.Byte); comment != "" {
+				blockJSON["//"] = comment
+			}
+		}
+
+		if len(block.Labels) == 0 {
+			blocks, _ := output[block.Type].([]interface{})
+			output[block.Type] = append(blocks, blockJSON)
+			continue
+		}
+
+		parent, ok := output[block.Type].(map[string]interface{})
+		if !ok {
+			parent = map[string]interface{}{}
+			output[block.Type] = parent
+		}
+
+		for _, label := range block.Labels[:len(block.Labels)-1] {
+			child, ok := parent[label].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				parent[label] = child
+			}
+			parent = child
+		}
+
+		lastLabel := block.Labels[len(block.Labels)-1]
+		switch existing := parent[lastLabel].(type) {
+		case nil:
+			parent[lastLabel] = blockJSON
+		case map[string]interface{}:
+			parent[lastLabel] = []interface{}{existing, blockJSON}
+		case []interface{}:
+			parent[lastLabel] = append(existing, blockJSON)
+		}
+	}
+
+	return output
+}
+
+// expressionToJSON converts an expression into a JSON value. Literals, objects and tuples are converted directly,
+// templates keep their interpolations, and other expressions are written as a "${...}" interpolation.
+func expressionToJSON(expr hclsyntax.Expression, src []byte) interface{} {
+	switch e := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		return literalToJSON(e.Val, src, e)
+	case *hclsyntax.TemplateExpr:
+		template := strings.Builder{}
+		for _, part := range e.Parts {
+			if literal, ok := part.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.String {
+				template.WriteString(escapeTemplate(literal.Val.AsString()))
+				continue
+			}
+			template.WriteString(fmt.Sprintf("${%v}", expressionSource(part, src)))
+		}
+		return template.String()
+	case *hclsyntax.TemplateWrapExpr:
+		return fmt.Sprintf("${%v}", expressionSource(e.Wrapped, src))
+	case *hclsyntax.TupleConsExpr:
+		tuple := []interface{}{}
+		for _, element := range e.Exprs {
+			tuple = append(tuple, expressionToJSON(element, src))
+		}
+		return tuple
+	case *hclsyntax.ObjectConsExpr:
+		object := map[string]interface{}{}
+		for _, item := range e.Items {
+			object[objectKey(item.KeyExpr, src)] = expressionToJSON(item.ValueExpr, src)
+		}
+		return object
+	default:
+		return fmt.Sprintf("${%v}", expressionSource(expr, src))
+	}
+}
+
+// referenceToJSON converts a reference, or a tuple of references, into the bare strings JSON syntax expects for
+// meta-arguments such as depends_on.
+func referenceToJSON(expr hclsyntax.Expression, src []byte) interface{} {
+	if tuple, ok := expr.(*hclsyntax.TupleConsExpr); ok {
+		references := []interface{}{}
+		for _, element := range tuple.Exprs {
+			references = append(references, referenceToJSON(element, src))
+		}
+		return references
+	}
+
+	return strings.Trim(expressionSource(expr, src), `"`)
+}
+
+// literalToJSON converts a literal value into a JSON value, escaping template sequences within strings.
+func literalToJSON(value cty.Value, src []byte, expr hclsyntax.Expression) interface{} {
+	switch {
+	case value.IsNull():
+		return nil
+	case value.Type() == cty.String:
+		return escapeTemplate(value.AsString())
+	case value.Type() == cty.Bool:
+		return value.True()
+	case value.Type() == cty.Number:
+		return json.Number(value.AsBigFloat().Text('f', -1))
+	default:
+		return fmt.Sprintf("${%v}", expressionSource(expr, src))
+	}
+}
+
+// objectKey returns the key of an object constructor item, whether written as a bare keyword or a string.
+func objectKey(keyExpr hclsyntax.Expression, src []byte) string {
+	if keyword := hcl.ExprAsKeyword(keyExpr); keyword != "" {
+		return keyword
+	}
+
+	value, diagnostics := keyExpr.Value(nil)
+	if !diagnostics.HasErrors() && value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+		return value.AsString()
+	}
+
+	return expressionSource(keyExpr, src)
+}
+
+// escapeTemplate escapes the template sequences of a literal string, which JSON syntax would otherwise interpret.
+func escapeTemplate(value string) string {
+	value = strings.ReplaceAll(value, "${", "$${")
+	return strings.ReplaceAll(value, "%{", "%%{")
+}
+
+// expressionSource returns the source code of an expression.
+func expressionSource(expr hclsyntax.Expression, src []byte) string {
+	sourceRange := expr.Range()
+	return string(src[sourceRange.Start.Byte:sourceRange.End.Byte])
+}
+
+// leadingComment returns the comment lines directly above the given byte offset, ignoring blank lines in between.
+func leadingComment(src []byte, offset int) string {
+	lines := strings.Split(string(src[:offset]), "\n")
+
+	comments := []string{}
+	for i := len(lines) - 2; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			comments = append([]string{strings.TrimSpace(strings.TrimPrefix(line, "#"))}, comments...)
+			continue
+		}
+
+		if strings.HasPrefix(line, "//") {
+			comments = append([]string{strings.TrimSpace(strings.TrimPrefix(line, "//"))}, comments...)
+			continue
+		}
+
+		break
+	}
+
+	return strings.Join(comments, "\n")
+}
@@ -0,0 +1,89 @@
+package hclcreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHCLToJSON(t *testing.T) {
+	// Given
+	hclBytes := []byte(`# Monthly cost: $0.00
+# Cloud identifier: logs
+
+resource "aws_s3_bucket" "logs" {
+  provider      = aws.us_west_2
+  bucket        = "logs-${var.env}"
+  force_destroy = false
+  acl           = "$${literal}"
+  tags = {
+    Name  = "logs"
+    Owner = local.owner
+  }
+  count = 2
+
+  lifecycle {
+    ignore_changes = [tags]
+  }
+
+  depends_on = [aws_kms_key.logs]
+}
+
+import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+`)
+
+	// When
+	jsonBytes, err := hclToJSON(hclBytes, "new-resources.tf")
+
+	// Then
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "resource": {
+    "aws_s3_bucket": {
+      "logs": {
+        "//": "Monthly cost: $0.00\nCloud identifier: logs",
+        "provider": "aws.us_west_2",
+        "bucket": "logs-${var.env}",
+        "force_destroy": false,
+        "acl": "$${literal}",
+        "tags": {"Name": "logs", "Owner": "${local.owner}"},
+        "count": 2,
+        "lifecycle": [{"ignore_changes": ["tags"]}],
+        "depends_on": ["aws_kms_key.logs"]
+      }
+    }
+  },
+  "import": [{"to": "aws_s3_bucket.logs", "id": "logs"}]
+}`, string(jsonBytes))
+}
+
+func TestFormatGeneratedCode_JSON(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	directory := writeGeneratedCode(t)
+	h := hclCreate{config: Config{OutputFormat: JSONOutputFormat}}
+
+	// When
+	err = h.FormatGeneratedCode(map[string]string{"dev": "/dev/"})
+
+	// Then
+	require.NoError(t, err)
+
+	for _, generatedFile := range []string{"new-resources.tf", filepath.Join("cloud-concierge", "imports", "id_imports.tf")} {
+		_, err = os.Stat(filepath.Join(directory, generatedFile))
+		assert.True(t, os.IsNotExist(err))
+
+		_, err = os.Stat(filepath.Join(directory, generatedFile+".json"))
+		assert.NoError(t, err)
+	}
+}
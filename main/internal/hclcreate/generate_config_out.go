@@ -0,0 +1,243 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// TerraformerCodeGeneration writes new resources using the HCL generated by terraformer.
+	TerraformerCodeGeneration = "terraformer"
+
+	// GenerateConfigOutCodeGeneration writes new resources using the configuration generated by
+	// `terraform plan -generate-config-out` from import blocks. Workspaces whose Terraform version does not support
+	// config generation use the HCL generated by terraformer.
+	GenerateConfigOutCodeGeneration = "generate-config-out"
+
+	// generateConfigSandboxPath is the directory into which the repository is copied before generating configuration.
+	generateConfigSandboxPath = "outputs/generate-config"
+
+	// generateConfigImportsFileName is the file of import blocks written within the sandboxed workspace directory.
+	generateConfigImportsFileName = "cloud_concierge_generate_imports.tf"
+
+	// generatedConfigFileName is the file to which Terraform writes generated configuration.
+	generatedConfigFileName = "cloud_concierge_generated.tf"
+)
+
+// generateConfigOutHCLCreate implements the HCLCreate interface, generating the resource blocks of new resources with
+// `terraform plan -generate-config-out` rather than using terraformer's HCL, which can lag behind provider versions.
+type generateConfigOutHCLCreate struct {
+	*hclCreate
+}
+
+// ExtractResourceDefinitions generates the resource blocks of new resources with Terraform and writes them into each
+// workspace, with the same naming, cleaning and annotation as terraformer's HCL.
+func (g *generateConfigOutHCLCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToDirectory map[string]string) error {
+	return g.extractResourceDefinitions(noNewResources, workspaceToDirectory, g.generateResourceDefinitions)
+}
+
+// generateResourceDefinitions returns terraformer's resource definitions with the block of each new resource replaced
+// by the block Terraform generates for it. Blocks are labelled with the terraformer generated type and name.
+func (g *generateConfigOutHCLCreate) generateResourceDefinitions(
+	newResourceToWorkspace NewResourceToWorkspace,
+	workspaceToDirectory map[string]string,
+) (*hclwrite.File, error) {
+	terraformerResources, err := g.loadTerraformerResources(newResourceToWorkspace, workspaceToDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(newResourceToWorkspace) == 0 {
+		return terraformerResources, nil
+	}
+
+	resourceToImportLocation, err := os.ReadFile("outputs/resources-to-import-location.json")
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile] outputs/resources-to-import-location.json error: %v", err)
+	}
+
+	resourceToImportDataPair := ResourceToImportDataPair{}
+	err = json.Unmarshal(resourceToImportLocation, &resourceToImportDataPair)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `resourceToImportLocation`: %v", err)
+	}
+
+	workspaceToResources := map[string][]string{}
+	for resource, workspace := range newResourceToWorkspace {
+		workspaceToResources[workspace] = append(workspaceToResources[workspace], resource)
+	}
+
+	err = os.RemoveAll(generateConfigSandboxPath)
+	if err != nil {
+		return nil, fmt.Errorf("[os.RemoveAll %v]%v", generateConfigSandboxPath, err)
+	}
+
+	sandboxRepository := filepath.Join(generateConfigSandboxPath, "repo")
+	err = copyDirectory("repo", sandboxRepository)
+	if err != nil {
+		return nil, fmt.Errorf("[copyDirectory]%v", err)
+	}
+
+	for _, workspace := range sortedKeys(workspaceToResources) {
+		directory, ok := workspaceToDirectory[workspace]
+		if !ok {
+			continue
+		}
+
+		version, err := g.workspaceTerraformVersion(directory)
+		if err != nil {
+			return nil, fmt.Errorf("[g.workspaceTerraformVersion]%v", err)
+		}
+
+		if !version.Supports(GenerateConfigOutCapability) {
+			logrus.Infof("[generate_config_out] terraform %v does not support %v, using terraformer's HCL for workspace %v", version, GenerateConfigOutCapability, workspace)
+			continue
+		}
+
+		resources := workspaceToResources[workspace]
+		sort.Strings(resources)
+
+		generatedConfig, err := g.generateWorkspaceConfig(workspace, filepath.Join(sandboxRepository, directory), resources, resourceToImportDataPair)
+		if err != nil {
+			return nil, fmt.Errorf("[g.generateWorkspaceConfig %v]%v", workspace, err)
+		}
+
+		if generatedConfig == nil {
+			continue
+		}
+
+		for _, block := range generatedConfig.Body().Blocks() {
+			if block.Type() != "resource" || len(block.Labels()) != 2 {
+				continue
+			}
+
+			terraformerBlock := terraformerResources.Body().FirstMatchingBlock("resource", block.Labels())
+			if terraformerBlock != nil {
+				terraformerResources.Body().RemoveBlock(terraformerBlock)
+			}
+
+			removeNullAttributes(block.Body())
+			terraformerResources.Body().AppendBlock(block)
+		}
+	}
+
+	return terraformerResources, nil
+}
+
+// generateWorkspaceConfig writes an import block for each resource into the sandboxed workspace directory and runs
+// `terraform plan -generate-config-out` against a copy of the workspace's state, returning the generated
+// configuration without Terraform's "__generated__" comments. Returns nil when Terraform fails before generating
+// configuration, for example because of required variables without defaults or inaccessible module sources, in which
+// case terraformer's HCL is kept for the workspace.
+func (g *generateConfigOutHCLCreate) generateWorkspaceConfig(
+	workspace string,
+	sandboxDirectory string,
+	resources []string,
+	resourceToImportDataPair ResourceToImportDataPair,
+) (*hclwrite.File, error) {
+	importsFile := hclwrite.NewEmptyFile()
+	for _, resource := range resources {
+		resourceIdentifier := g.resourceToIdentifierStruct(resource)
+
+		importBody := importsFile.Body().AppendNewBlock("import", nil).Body()
+		importBody.SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: resourceIdentifier.resourceType},
+			hcl.TraverseAttr{Name: resourceIdentifier.resourceName},
+		})
+		importBody.SetAttributeValue("id", cty.StringVal(resourceToImportDataPair[resource].RemoteCloudReference))
+		if provider := g.resourceNames[resource].Provider; provider != "" {
+			importBody.SetAttributeRaw("provider", hclwrite.TokensForIdentifier(provider))
+		}
+	}
+
+	aliasToRegion := g.workspaceToNewProviders[workspace]
+	for _, alias := range sortedKeys(aliasToRegion) {
+		providerBody := importsFile.Body().AppendNewBlock("provider", []string{"aws"}).Body()
+		providerBody.SetAttributeValue("alias", cty.StringVal(alias))
+		providerBody.SetAttributeValue("region", cty.StringVal(aliasToRegion[alias]))
+	}
+
+	err := os.WriteFile(filepath.Join(sandboxDirectory, generateConfigImportsFileName), hclwrite.Format(importsFile.Bytes()), 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("[os.WriteFile %v]%v", generateConfigImportsFileName, err)
+	}
+
+	err = useLocalStateCopy(workspace, sandboxDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[useLocalStateCopy]%v", err)
+	}
+
+	binary := g.config.Runtime.Binary()
+	_, err = executeCommandInDirectory(sandboxDirectory, binary, "init", "-reconfigure", "-input=false", "-no-color")
+	if err != nil {
+		logrus.Warnf("[generate_config_out] terraform init failed, using terraformer's HCL for workspace %v: %v", workspace, commandErrorMessage(err))
+		return nil, nil
+	}
+
+	// Terraform writes the generated configuration even when planning with it fails, for example because of
+	// conflicting attributes, which are then reported when validating the generated code.
+	_, planErr := executeCommandInDirectory(
+		sandboxDirectory, binary, "plan", "-input=false", "-lock=false", "-no-color",
+		fmt.Sprintf("-generate-config-out=%v", generatedConfigFileName),
+	)
+
+	generatedBytes, err := os.ReadFile(filepath.Join(sandboxDirectory, generatedConfigFileName))
+	if errors.Is(err, os.ErrNotExist) && planErr != nil {
+		logrus.Warnf("[generate_config_out] terraform plan failed before generating configuration, using terraformer's HCL for workspace %v: %v", workspace, commandErrorMessage(planErr))
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile %v]%v", generatedConfigFileName, err)
+	}
+
+	if planErr != nil {
+		logrus.Warnf("[generate_config_out] terraform plan for workspace %v reported errors with the generated configuration: %v", workspace, commandErrorMessage(planErr))
+	}
+
+	return parseGeneratedConfig(generatedBytes)
+}
+
+// parseGeneratedConfig parses configuration generated by Terraform, dropping its "__generated__" comments.
+func parseGeneratedConfig(generatedBytes []byte) (*hclwrite.File, error) {
+	lines := []string{}
+	for _, line := range strings.Split(string(generatedBytes), "\n") {
+		if strings.HasPrefix(line, "# __generated__") || strings.HasPrefix(line, "# Please review these resources") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	generatedConfig, diagnostics := hclwrite.ParseConfig([]byte(strings.Join(lines, "\n")), generatedConfigFileName, hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("[hclwrite.ParseConfig]%v", diagnostics)
+	}
+
+	return generatedConfig, nil
+}
+
+// removeNullAttributes removes the attributes Terraform generates as null from the body and its nested blocks, along
+// with any nested blocks left empty as a result.
+func removeNullAttributes(body *hclwrite.Body) {
+	for name, attribute := range body.Attributes() {
+		if strings.TrimSpace(string(attribute.Expr().BuildTokens(nil).Bytes())) == "null" {
+			body.RemoveAttribute(name)
+		}
+	}
+
+	for _, nestedBlock := range body.Blocks() {
+		removeNullAttributes(nestedBlock.Body())
+
+		if len(nestedBlock.Body().Attributes()) == 0 && len(nestedBlock.Body().Blocks()) == 0 {
+			body.RemoveBlock(nestedBlock)
+		}
+	}
+}
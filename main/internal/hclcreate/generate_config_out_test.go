@@ -0,0 +1,132 @@
+package hclcreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+)
+
+func TestNewHCLCreate_CodeGeneration(t *testing.T) {
+	// Given
+	provider := terraformValueObjects.Provider("aws")
+
+	// When
+	terraformer, err := NewHCLCreate(Config{CodeGeneration: TerraformerCodeGeneration}, provider)
+	require.NoError(t, err)
+	generateConfigOut, err := NewHCLCreate(Config{CodeGeneration: GenerateConfigOutCodeGeneration}, provider)
+	require.NoError(t, err)
+
+	// Then
+	assert.IsType(t, &hclCreate{}, terraformer)
+	assert.IsType(t, &generateConfigOutHCLCreate{}, generateConfigOut)
+}
+
+func TestParseGeneratedConfig(t *testing.T) {
+	// Given
+	generatedBytes := []byte(`# __generated__ by Terraform
+# Please review these resources and move them into your main configuration files.
+
+# __generated__ by Terraform from "logs"
+resource "aws_s3_bucket" "tfer--logs" {
+  bucket              = "logs"
+  bucket_prefix       = null
+  force_destroy       = false
+  object_lock_enabled = false
+  tags                = {}
+  timeouts {
+    create = null
+  }
+}
+`)
+
+	// When
+	generatedConfig, err := parseGeneratedConfig(generatedBytes)
+	require.NoError(t, err)
+
+	block := generatedConfig.Body().Blocks()[0]
+	removeNullAttributes(block.Body())
+
+	// Then
+	assert.Equal(t, `resource "aws_s3_bucket" "tfer--logs" {
+  bucket              = "logs"
+  force_destroy       = false
+  object_lock_enabled = false
+  tags                = {}
+}
+`, string(hclwrite.Format(block.BuildTokens(nil).Bytes())))
+}
+
+func TestGenerateResourceDefinitions_UnsupportedTerraformVersion(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	terraformerHCL := `resource "aws_s3_bucket" "tfer--logs" {
+  bucket = "logs"
+}
+`
+	require.NoError(t, os.MkdirAll("current_cloud", 0o700))
+	require.NoError(t, os.MkdirAll("outputs", 0o700))
+	require.NoError(t, os.MkdirAll(filepath.Join("repo", "dev"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join("current_cloud", "resources.tf"), []byte(terraformerHCL), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join("outputs", "resources-to-import-location.json"),
+		[]byte(`{"aws_s3_bucket.tfer--logs": {"RemoteCloudReference": "logs"}}`), 0o600))
+
+	g := generateConfigOutHCLCreate{hclCreate: &hclCreate{config: Config{TerraformVersion: "1.4.6"}}}
+
+	// When
+	resources, err := g.generateResourceDefinitions(
+		NewResourceToWorkspace{"aws_s3_bucket.tfer--logs": "dev"},
+		map[string]string{"dev": "/dev/"},
+	)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, terraformerHCL, string(resources.Bytes()))
+}
+
+func TestGenerateResourceDefinitions_TerraformFails(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	binDirectory := t.TempDir()
+	fakeTerraform := "#!/bin/sh\nif [ \"$1\" = \"plan\" ]; then\n  echo 'Error: No value for required variable' >&2\n  exit 1\nfi\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDirectory, "terraform"), []byte(fakeTerraform), 0o700))
+	t.Setenv("PATH", binDirectory+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	terraformerHCL := `resource "aws_s3_bucket" "tfer--logs" {
+  bucket = "logs"
+}
+`
+	require.NoError(t, os.MkdirAll("current_cloud", 0o700))
+	require.NoError(t, os.MkdirAll("outputs", 0o700))
+	require.NoError(t, os.MkdirAll("state_files", 0o700))
+	require.NoError(t, os.MkdirAll(filepath.Join("repo", "dev"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join("current_cloud", "resources.tf"), []byte(terraformerHCL), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join("state_files", "dev.json"), []byte(`{"version": 4}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join("outputs", "resources-to-import-location.json"),
+		[]byte(`{"aws_s3_bucket.tfer--logs": {"RemoteCloudReference": "logs"}}`), 0o600))
+
+	g := generateConfigOutHCLCreate{hclCreate: &hclCreate{config: Config{TerraformVersion: "1.6.0"}}}
+
+	// When
+	resources, err := g.generateResourceDefinitions(
+		NewResourceToWorkspace{"aws_s3_bucket.tfer--logs": "dev"},
+		map[string]string{"dev": "/dev/"},
+	)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, terraformerHCL, string(resources.Bytes()))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"text/template"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/sirupsen/logrus"
@@ -30,22 +31,70 @@ type MigrationHistory struct {
 	// StorageType is the name of the storage resource used to store migration history files.
 	StorageType string
 
-	// Bucket is the name of the bucket-like storage location used to store migration history files.
+	// Bucket is the name of the bucket-like storage location used to store migration history files. For 'azurerm',
+	// the name of the blob container.
 	Bucket string
 
 	// Region is the region of the bucket for storage used to store migration history files. Only needed
 	// when storageType is 's3'
 	Region string
+
+	// StorageAccountName is the name of the storage account containing the blob container used to store migration
+	// history files. Only needed when storageType is 'azurerm'
+	StorageAccountName string
+
+	// Path is the directory, relative to the workspace, in which migration history files are stored. Only used when
+	// storageType is 'local', defaulting to the workspace's cloud-concierge/tfmigrate directory.
+	Path string
 }
 
 // Config is a struct comprising the configuration needed for hclCreate
 type Config struct {
 	// MigrationHistoryStorage is a map containing information needed for specifying tfmigrate
-	//// history storage appropriately.
-	MigrationHistoryStorage MigrationHistory `required:"true"`
+	// history storage appropriately. When no storage type is set, it is inferred from each workspace's backend.
+	MigrationHistoryStorage MigrationHistory
+
+	// TerraformVersion is the configured Terraform version or version constraint. Each workspace uses it when it
+	// satisfies the workspace's required_version, and otherwise the lowest version required_version allows. When
+	// empty, the version inferred from the repository takes its place.
+	TerraformVersion string
+
+	// ResourceNameTemplate is a Go template used to name generated resources. See ResourceNameData for the
+	// available fields. Empty uses the terraformer generated name.
+	ResourceNameTemplate string
+
+	// Division is the name of the cloud division being scanned.
+	Division string
+
+	// ModulePatterns are the patterns used to write groups of related new resources as module calls.
+	ModulePatterns ModulePatterns
+
+	// ParameterizeResources extracts literals repeated across new resources, such as the region or common tags,
+	// into variables and locals.
+	ParameterizeResources bool
 
-	// TerraformVersion is the version of Terraform used.
-	TerraformVersion string `required:"true"`
+	// DriftRemediation is how drift in managed resources is handled, either ReportDriftRemediation or
+	// AcceptCloudStateRemediation.
+	DriftRemediation string
+
+	// OrphanedResourceBlocks is how the resource blocks of deleted cloud resources are handled, one of
+	// KeepOrphanedResourceBlocks, CommentOrphanedResourceBlocks or DeleteOrphanedResourceBlocks.
+	OrphanedResourceBlocks string
+
+	// Runtime is the command line tool, Terraform or OpenTofu, used to generate, validate and plan configuration.
+	Runtime terraformValueObjects.Runtime
+
+	// CodeGeneration is how the resource blocks of new resources are generated, either TerraformerCodeGeneration or
+	// GenerateConfigOutCodeGeneration.
+	CodeGeneration string
+
+	// ValidateGeneratedCode is how generated code is validated before the pull request is opened, one of
+	// NoCodeValidation, ValidateCodeValidation or PlanCodeValidation.
+	ValidateGeneratedCode string
+
+	// OutputFormat is the syntax in which new resources, import blocks and removed blocks are written, either
+	// HCLOutputFormat or JSONOutputFormat.
+	OutputFormat string
 }
 
 // NewResourceToWorkspace is a map of resource unique id to workspace name
@@ -65,7 +114,7 @@ type ImportDataPair struct {
 // for generating and manipulating common HCL configuration.
 type HCLCreate interface {
 	// CreateMainTF outputs a bytes slice which defines a baseline main.tf file.
-	CreateMainTF(providers map[string]string) ([]byte, error)
+	CreateMainTF(terraformVersion string, providers map[string]string) ([]byte, error)
 
 	// CreateImports creates either import blocks or tfmigrate configuration to import resources
 	// into Terraform state.
@@ -92,6 +141,26 @@ type HCLCreate interface {
 
 	// WriteImportBlocks writes import blocks to .tf files for configurations using Terraform version 1.5.0 or higher.
 	WriteImportBlocks(uniqueID string, workspaceToDirectory map[string]string) error
+
+	// CreateDuplicatedResourceRemovals creates either removed blocks or tfmigrate state rm migrations to resolve
+	// resources managed by more than one state file.
+	CreateDuplicatedResourceRemovals(uniqueID string, workspaceToDirectory map[string]string) error
+
+	// CreateDeletedResourceRemovals creates either removed blocks or tfmigrate state rm migrations for resources
+	// whose cloud resource no longer exists, and handles their now-orphaned resource blocks.
+	CreateDeletedResourceRemovals(uniqueID string, workspaceToDirectory map[string]string) error
+
+	// RemediateDrift rewrites drifted attributes of managed resources to their cloud values when accepting the
+	// cloud state.
+	RemediateDrift(workspaceToDirectory map[string]string) error
+
+	// ValidateGeneratedCode runs `terraform validate`, and optionally `terraform plan`, against a sandboxed copy of
+	// each workspace touched by cloud-concierge, quarantining new resources that fail validation or whose import plans
+	// changes.
+	ValidateGeneratedCode(workspaceToDirectory map[string]string) error
+
+	// FormatGeneratedCode rewrites generated code using JSON syntax when configured to.
+	FormatGeneratedCode(workspaceToDirectory map[string]string) error
 }
 
 // hclCreate implements the HCLCreate interface.
@@ -102,14 +171,47 @@ type hclCreate struct {
 	// divisionToProvider is a mapping between a division and the provider that is responsible
 	// for that division.
 	provider terraformValueObjects.Provider `required:"true"`
+
+	// resourceNameTemplate is the parsed ResourceNameTemplate, nil if no template is configured.
+	resourceNameTemplate *template.Template
+
+	// resourceNames is a map between terraformer resource identifiers and the name chosen for that resource.
+	resourceNames ResourceToGeneratedName
+
+	// modulePlacements are the groups of new resources written as module calls.
+	modulePlacements []*ModulePlacement
+
+	// resourceToModulePlacement is a map between terraformer resource identifiers and their module placement.
+	resourceToModulePlacement map[string]*ModulePlacement
+
+	// workspaceToNewProviders is a map between workspaces and the new aliased AWS provider configurations, as a map
+	// between alias and region, needed by the new resources within that workspace.
+	workspaceToNewProviders map[string]map[string]string
+
+	// directoryToTerraformVersion is a map between workspace directories and the resolved Terraform version whose
+	// capabilities generated code may rely upon.
+	directoryToTerraformVersion map[string]TerraformVersion
 }
 
-// NewHCLCreate creates and returns a struct which implements the HCLCreate interface.
+// NewHCLCreate creates and returns a struct which implements the HCLCreate interface, generating the resource blocks
+// of new resources with Terraform rather than terraformer when configured to.
 func NewHCLCreate(config Config, provider terraformValueObjects.Provider) (HCLCreate, error) {
-	return &hclCreate{
-		config:   config,
-		provider: provider,
-	}, nil
+	resourceNameTemplate, err := ParseResourceNameTemplate(config.ResourceNameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("[ParseResourceNameTemplate]%v", err)
+	}
+
+	h := &hclCreate{
+		config:               config,
+		provider:             provider,
+		resourceNameTemplate: resourceNameTemplate,
+	}
+
+	if config.CodeGeneration == GenerateConfigOutCodeGeneration {
+		return &generateConfigOutHCLCreate{hclCreate: h}, nil
+	}
+
+	return h, nil
 }
 
 // Decode is a custom decoder of the MigrationHistoryDataMap for use with the envconfig library.
@@ -133,19 +235,41 @@ func (mhd *MigrationHistory) Decode(value string) error {
 		currentMap.StorageType = "gcs"
 	}
 
-	if currentMap.StorageType != "s3" && currentMap.StorageType != "gcs" {
-		return fmt.Errorf("only types of 's3' and 'gcs' are currently supported. Attempted %v", currentMap.StorageType)
+	err = validateMigrationHistory(currentMap)
+	if err != nil {
+		return err
 	}
 
-	if currentMap.Region == "" {
-		return fmt.Errorf("region variable cannot be empty")
-	}
+	*mhd = currentMap
+	return nil
+}
 
-	if currentMap.Bucket == "" {
-		return fmt.Errorf("the required field `bucket` is not present")
+// validateMigrationHistory checks that the fields required by the history storage type are present.
+func validateMigrationHistory(migrationHistory MigrationHistory) error {
+	switch migrationHistory.StorageType {
+	case "s3":
+		if migrationHistory.Region == "" {
+			return fmt.Errorf("region variable cannot be empty")
+		}
+		if migrationHistory.Bucket == "" {
+			return fmt.Errorf("the required field `bucket` is not present")
+		}
+	case "gcs":
+		if migrationHistory.Bucket == "" {
+			return fmt.Errorf("the required field `bucket` is not present")
+		}
+	case "azurerm":
+		if migrationHistory.StorageAccountName == "" {
+			return fmt.Errorf("the required field `storageAccountName` is not present")
+		}
+		if migrationHistory.Bucket == "" {
+			return fmt.Errorf("the required field `bucket` is not present")
+		}
+	case "local":
+	default:
+		return fmt.Errorf("only types of 's3', 'gcs', 'azurerm' and 'local' are currently supported. Attempted %v", migrationHistory.StorageType)
 	}
 
-	*mhd = currentMap
 	return nil
 }
 
@@ -153,13 +277,38 @@ func (mhd *MigrationHistory) Decode(value string) error {
 func (h *hclCreate) CreateImports(uniqueID string, workspaceToDirectory map[string]string) error {
 	logrus.Debugf("[hclcreate] CreateImports() called with uniqueID: %v, workspaceToDirectory: %v", uniqueID, workspaceToDirectory)
 
-	if h.config.TerraformVersion >= "1.5.0" {
-		err := h.WriteImportBlocks(uniqueID, workspaceToDirectory)
+	err := h.loadResourceNames()
+	if err != nil {
+		return fmt.Errorf("[h.loadResourceNames]%v", err)
+	}
+
+	importBlockWorkspaces := map[string]string{}
+	tfmigrateWorkspaces := map[string]string{}
+	for workspace, directory := range workspaceToDirectory {
+		version, err := h.workspaceTerraformVersion(directory)
+		if err != nil {
+			return fmt.Errorf("[h.workspaceTerraformVersion]%v", err)
+		}
+
+		// Import blocks are written one per resource even when the workspace supports ImportForEachCapability, as
+		// generated resources are individually named blocks and the `to` address of an import block with for_each
+		// can only vary by instance key.
+		if version.Supports(ImportBlocksCapability) {
+			importBlockWorkspaces[workspace] = directory
+		} else {
+			tfmigrateWorkspaces[workspace] = directory
+		}
+	}
+
+	if len(importBlockWorkspaces) > 0 {
+		err = h.WriteImportBlocks(uniqueID, importBlockWorkspaces)
 		if err != nil {
 			return fmt.Errorf("error creating import blocks: %v", err)
 		}
-	} else {
-		err := h.CreateTFMigrate(uniqueID, workspaceToDirectory)
+	}
+
+	if len(tfmigrateWorkspaces) > 0 {
+		err = h.CreateTFMigrate(uniqueID, tfmigrateWorkspaces)
 		if err != nil {
 			return fmt.Errorf("error creating tfmigrate configuration: %v", err)
 		}
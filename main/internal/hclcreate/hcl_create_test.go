@@ -62,9 +62,35 @@ func TestDecodeMigrationHistory(t *testing.T) {
 		t.Errorf("got %v, expected %v", envVar, expectedEnvVar)
 	}
 
+	// Case 4 - azurerm storage
+	err = envVar.Decode(`{"storageType": "azurerm", "bucket": "xyz", "storageAccountName": "abc"}`)
+	if err != nil {
+		t.Errorf("unexpected error in envVar.Decode: %v", err)
+	}
+
+	expectedEnvVar = MigrationHistory{
+		StorageType:        "azurerm",
+		Bucket:             "xyz",
+		StorageAccountName: "abc",
+	}
+	if !reflect.DeepEqual(envVar, expectedEnvVar) {
+		t.Errorf("got %v, expected %v", envVar, expectedEnvVar)
+	}
+
+	// Case 5 - local storage
+	err = envVar.Decode(`{"storageType": "local"}`)
+	if err != nil {
+		t.Errorf("unexpected error in envVar.Decode: %v", err)
+	}
+
+	expectedEnvVar = MigrationHistory{StorageType: "local"}
+	if !reflect.DeepEqual(envVar, expectedEnvVar) {
+		t.Errorf("got %v, expected %v", envVar, expectedEnvVar)
+	}
+
 	// Error case 1 - wrong storage type
 	err = envVar.Decode(`{"storageType": "azureBlob", "bucket": "xyz", "region": "us-east1"}`)
-	expectedError := "only types of 's3' and 'gcs' are currently supported. Attempted azureBlob"
+	expectedError := "only types of 's3', 'gcs', 'azurerm' and 'local' are currently supported. Attempted azureBlob"
 	if err.Error() != expectedError {
 		t.Errorf("got error %v, expected error %v", err, expectedError)
 	}
@@ -82,4 +108,11 @@ func TestDecodeMigrationHistory(t *testing.T) {
 	if err.Error() != expectedError {
 		t.Errorf("got error %v, expected error %v", err, expectedError)
 	}
+
+	// Error case 4 - no storage account specified
+	err = envVar.Decode(`{"storageType": "azurerm", "bucket": "xyz"}`)
+	expectedError = "the required field `storageAccountName` is not present"
+	if err.Error() != expectedError {
+		t.Errorf("got error %v, expected error %v", err, expectedError)
+	}
 }
@@ -0,0 +1,354 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModulePattern describes a group of related resources that should be written as a single module call instead of
+// as flat resource blocks.
+type ModulePattern struct {
+	// Name is the name of the pattern, for example "s3-bucket".
+	Name string `json:"name"`
+
+	// Source is the source of an existing module, for example "git::https://github.com/org/modules.git//s3-bucket".
+	// When empty, the matched resources are written to a new local module directory instead.
+	Source string `json:"source"`
+
+	// Version is the version constraint of the module, only used for registry module sources.
+	Version string `json:"version"`
+
+	// PrimaryResourceType is the resource type around which the other members of the pattern are grouped.
+	PrimaryResourceType string `json:"primaryResourceType"`
+
+	// PrimaryAddress is the address of the primary resource within the module, for example "aws_s3_bucket.this".
+	// Required when Source is set.
+	PrimaryAddress string `json:"primaryAddress"`
+
+	// PrimaryAttribute is the attribute of the primary resource that members refer to. Defaults to "id".
+	PrimaryAttribute string `json:"primaryAttribute"`
+
+	// Members are the resources grouped with the primary resource.
+	Members []ModulePatternMember `json:"members"`
+
+	// Inputs is a map between module variable names and the "type.attribute" whose value is passed to that variable.
+	// Only used when Source is set.
+	Inputs map[string]string `json:"inputs"`
+}
+
+// ModulePatternMember is a resource grouped with the primary resource of a ModulePattern.
+type ModulePatternMember struct {
+	// ResourceType is the Terraform resource type of the member.
+	ResourceType string `json:"resourceType"`
+
+	// Address is the address of the member within the module. Required when the pattern's Source is set.
+	Address string `json:"address"`
+
+	// MatchAttribute is the attribute of the member whose value equals the primary resource's PrimaryAttribute.
+	MatchAttribute string `json:"matchAttribute"`
+}
+
+// ModulePatterns is a list of ModulePattern values, where the first matching pattern is used.
+type ModulePatterns []ModulePattern
+
+// ModulePlacement is a group of new resources written as a single module call.
+type ModulePlacement struct {
+	// Pattern is the ModulePattern that matched the resources.
+	Pattern ModulePattern
+
+	// ModuleName is the name of the module call.
+	ModuleName string
+
+	// Workspace is the name of the workspace the module call is written to.
+	Workspace string
+
+	// PrimaryResource is the terraformer resource identifier of the primary resource.
+	PrimaryResource string
+
+	// ResourceToAddress is a map between the terraformer resource identifiers of the primary resource and members,
+	// and their address within the module.
+	ResourceToAddress map[string]string
+
+	// resourceToBlock is a map between terraformer resource identifiers and their generated resource block.
+	resourceToBlock map[string]*hclwrite.Block
+
+	// resourceToComments is a map between terraformer resource identifiers and their cost and cloud actor comments.
+	resourceToComments map[string]hclwrite.Tokens
+
+	// dataSources are the data sources referenced by the resources within a new local module.
+	dataSources []*hclwrite.Block
+}
+
+// Decode is a custom decoder of ModulePatterns for use with the envconfig library.
+func (p *ModulePatterns) Decode(value string) error {
+	logrus.Debugf("ModulePatterns.Decode() called with value: %v", value)
+	if value == "" {
+		return nil
+	}
+
+	var patterns ModulePatterns
+	err := json.Unmarshal([]byte(value), &patterns)
+	if err != nil {
+		return fmt.Errorf("Error parsing specified json string: %v", err)
+	}
+
+	for _, pattern := range patterns {
+		if pattern.Name == "" || pattern.PrimaryResourceType == "" {
+			return fmt.Errorf("module pattern requires both `name` and `primaryResourceType`")
+		}
+
+		if pattern.Source == "" {
+			continue
+		}
+
+		if pattern.PrimaryAddress == "" {
+			return fmt.Errorf("module pattern %v requires `primaryAddress` when `source` is set", pattern.Name)
+		}
+		for _, member := range pattern.Members {
+			if member.Address == "" {
+				return fmt.Errorf("module pattern %v member %v requires `address` when `source` is set", pattern.Name, member.ResourceType)
+			}
+		}
+	}
+
+	*p = patterns
+	return nil
+}
+
+// placeResourcesIntoModules groups new resources matching the configured module patterns into module placements,
+// updating the address of each placed resource within h.resourceNames.
+func (h *hclCreate) placeResourcesIntoModules(
+	newResourceToWorkspace NewResourceToWorkspace,
+	resourceToAttributesFlat map[string]map[string]string,
+	workspaceToExistingNames map[string]map[string]bool,
+) {
+	h.modulePlacements = nil
+	h.resourceToModulePlacement = map[string]*ModulePlacement{}
+
+	resourceIDs := make([]string, 0, len(newResourceToWorkspace))
+	for resourceID := range newResourceToWorkspace {
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	sort.Strings(resourceIDs)
+
+	usedModuleNames := map[string]bool{}
+	for _, pattern := range h.config.ModulePatterns {
+		primaryAttribute := pattern.PrimaryAttribute
+		if primaryAttribute == "" {
+			primaryAttribute = "id"
+		}
+
+		for _, primaryID := range resourceIDs {
+			primaryIdentifier := h.resourceToIdentifierStruct(primaryID)
+			if primaryIdentifier.resourceType != pattern.PrimaryResourceType || h.resourceToModulePlacement[primaryID] != nil {
+				continue
+			}
+
+			workspace := newResourceToWorkspace[primaryID]
+			moduleName := h.resourceName(primaryIdentifier)
+			for suffix := 2; usedModuleNames[workspace+".module."+moduleName] ||
+				workspaceToExistingNames[workspace]["module."+moduleName]; suffix++ {
+				moduleName = fmt.Sprintf("%v_%d", h.resourceName(primaryIdentifier), suffix)
+			}
+			usedModuleNames[workspace+".module."+moduleName] = true
+
+			placement := &ModulePlacement{
+				Pattern:            pattern,
+				ModuleName:         moduleName,
+				Workspace:          workspace,
+				PrimaryResource:    primaryID,
+				ResourceToAddress:  map[string]string{primaryID: h.moduleResourceAddress(pattern, pattern.PrimaryAddress, primaryIdentifier)},
+				resourceToBlock:    map[string]*hclwrite.Block{},
+				resourceToComments: map[string]hclwrite.Tokens{},
+			}
+
+			primaryValue := resourceToAttributesFlat[primaryID][primaryAttribute]
+			for _, member := range pattern.Members {
+				for _, memberID := range resourceIDs {
+					memberIdentifier := h.resourceToIdentifierStruct(memberID)
+					if memberIdentifier.resourceType != member.ResourceType ||
+						newResourceToWorkspace[memberID] != workspace ||
+						h.resourceToModulePlacement[memberID] != nil ||
+						placement.ResourceToAddress[memberID] != "" ||
+						primaryValue == "" ||
+						resourceToAttributesFlat[memberID][member.MatchAttribute] != primaryValue {
+						continue
+					}
+
+					placement.ResourceToAddress[memberID] = h.moduleResourceAddress(pattern, member.Address, memberIdentifier)
+
+					// An existing module defines a single instance of each member, so only the first match is placed.
+					if pattern.Source != "" {
+						break
+					}
+				}
+			}
+
+			for resourceID, address := range placement.ResourceToAddress {
+				h.resourceToModulePlacement[resourceID] = placement
+
+				generatedName := h.resourceNames[resourceID]
+				generatedName.Address = fmt.Sprintf("module.%v.%v", moduleName, address)
+				h.resourceNames[resourceID] = generatedName
+			}
+			h.modulePlacements = append(h.modulePlacements, placement)
+		}
+	}
+}
+
+// moduleResourceAddress returns the address of a resource within a module. Existing modules use the address
+// configured within the pattern, while new local modules use the resource's generated name.
+func (h *hclCreate) moduleResourceAddress(pattern ModulePattern, patternAddress string, resourceIdentifier ResourceIdentifier) string {
+	if pattern.Source != "" {
+		return patternAddress
+	}
+
+	return fmt.Sprintf("%v.%v", resourceIdentifier.resourceType, h.resourceName(resourceIdentifier))
+}
+
+// addBlockToModulePlacement records the generated block of a resource placed within a module. For new local modules,
+// the member's match attribute is rewritten into a reference to the primary resource.
+func (h *hclCreate) addBlockToModulePlacement(
+	placement *ModulePlacement,
+	resource string,
+	comments hclwrite.Tokens,
+	block *hclwrite.Block,
+) {
+	if placement.Pattern.Source == "" && resource != placement.PrimaryResource {
+		primaryAttribute := placement.Pattern.PrimaryAttribute
+		if primaryAttribute == "" {
+			primaryAttribute = "id"
+		}
+
+		memberType := block.Labels()[0]
+		for _, member := range placement.Pattern.Members {
+			if member.ResourceType == memberType && block.Body().GetAttribute(member.MatchAttribute) != nil {
+				block.Body().SetAttributeRaw(
+					member.MatchAttribute,
+					referenceTokens(placement.ResourceToAddress[placement.PrimaryResource], primaryAttribute),
+				)
+			}
+		}
+	}
+
+	placement.resourceToBlock[resource] = block
+	placement.resourceToComments[resource] = comments
+}
+
+// appendModuleCalls writes a module call for each module placement into the corresponding workspace's file.
+func (h *hclCreate) appendModuleCalls(workspaceToHCLFile WorkspaceToHCL) {
+	for _, placement := range h.modulePlacements {
+		fileBody := workspaceToHCLFile[placement.Workspace].Body()
+		fileBody.AppendUnstructuredTokens(placement.resourceToComments[placement.PrimaryResource])
+		fileBody.AppendNewline()
+
+		moduleBlock := fileBody.AppendNewBlock("module", []string{placement.ModuleName})
+		moduleBody := moduleBlock.Body()
+
+		if placement.Pattern.Source == "" {
+			moduleBody.SetAttributeValue("source", cty.StringVal(fmt.Sprintf("./modules/%v", placement.ModuleName)))
+			h.setModuleProviders(moduleBody, placement)
+			fileBody.AppendNewline()
+			continue
+		}
+
+		moduleBody.SetAttributeValue("source", cty.StringVal(placement.Pattern.Source))
+		if placement.Pattern.Version != "" {
+			moduleBody.SetAttributeValue("version", cty.StringVal(placement.Pattern.Version))
+		}
+		h.setModuleProviders(moduleBody, placement)
+
+		variables := make([]string, 0, len(placement.Pattern.Inputs))
+		for variable := range placement.Pattern.Inputs {
+			variables = append(variables, variable)
+		}
+		sort.Strings(variables)
+
+		for _, variable := range variables {
+			source := strings.SplitN(placement.Pattern.Inputs[variable], ".", 2)
+			if len(source) != 2 {
+				continue
+			}
+
+			attribute := placement.memberAttribute(source[0], source[1])
+			if attribute != nil {
+				moduleBody.SetAttributeRaw(variable, attribute.Expr().BuildTokens(nil))
+			}
+		}
+		fileBody.AppendNewline()
+	}
+}
+
+// setModuleProviders passes the aliased provider configuration of the placement's primary resource, if any, to the
+// module as its default AWS provider.
+func (h *hclCreate) setModuleProviders(moduleBody *hclwrite.Body, placement *ModulePlacement) {
+	provider := h.resourceNames[placement.PrimaryResource].Provider
+	if provider == "" {
+		return
+	}
+
+	moduleBody.SetAttributeRaw("providers", hclwrite.TokensForObject([]hclwrite.ObjectAttrTokens{{
+		Name:  hclwrite.TokensForIdentifier("aws"),
+		Value: hclwrite.TokensForIdentifier(provider),
+	}}))
+}
+
+// memberAttribute returns the attribute of the first resource of resourceType within the placement.
+func (p *ModulePlacement) memberAttribute(resourceType string, attributeName string) *hclwrite.Attribute {
+	resources := make([]string, 0, len(p.resourceToBlock))
+	for resource := range p.resourceToBlock {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	for _, resource := range resources {
+		block := p.resourceToBlock[resource]
+		if block.Labels()[0] == resourceType {
+			return block.Body().GetAttribute(attributeName)
+		}
+	}
+
+	return nil
+}
+
+// writeLocalModules writes the resources of each module placement without an existing module source into a new
+// local module directory, repo<directory>modules/<module name>/main.tf.
+func (h *hclCreate) writeLocalModules(workspaceToDirectory map[string]string) error {
+	for _, placement := range h.modulePlacements {
+		if placement.Pattern.Source != "" {
+			continue
+		}
+
+		resources := make([]string, 0, len(placement.resourceToBlock))
+		for resource := range placement.resourceToBlock {
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+
+		moduleFile := hclwrite.NewEmptyFile()
+		for _, resource := range resources {
+			h.writeBlockToWorkspaceHCL(moduleFile, placement.resourceToComments[resource], nil, placement.resourceToBlock[resource])
+		}
+		appendDataSources(moduleFile, placement.dataSources)
+
+		moduleDirectory := fmt.Sprintf("repo%vmodules/%v", workspaceToDirectory[placement.Workspace], placement.ModuleName)
+		err := os.MkdirAll(moduleDirectory, 0o400)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll] error making directory %v: %v", moduleDirectory, err)
+		}
+
+		err = os.WriteFile(fmt.Sprintf("%v/main.tf", moduleDirectory), hclwrite.Format(moduleFile.Bytes()), 0o400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile] Error for %v/main.tf: %v", moduleDirectory, err)
+		}
+	}
+
+	return nil
+}
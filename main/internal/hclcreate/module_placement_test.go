@@ -0,0 +1,139 @@
+package hclcreate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func s3BucketModuleFixture(t *testing.T, source string) (hclCreate, NewResourceToWorkspace, *hclwrite.File) {
+	modulePatterns := ModulePatterns{}
+	err := modulePatterns.Decode(`[{
+		"name": "s3-bucket",
+		"source": "` + source + `",
+		"primaryResourceType": "aws_s3_bucket",
+		"primaryAddress": "aws_s3_bucket.this",
+		"members": [
+			{"resourceType": "aws_s3_bucket_policy", "address": "aws_s3_bucket_policy.this", "matchAttribute": "bucket"},
+			{"resourceType": "aws_s3_bucket_public_access_block", "address": "aws_s3_bucket_public_access_block.this", "matchAttribute": "bucket"}
+		],
+		"inputs": {"bucket": "aws_s3_bucket.bucket", "policy": "aws_s3_bucket_policy.policy"}
+	}]`)
+	require.NoError(t, err)
+
+	h := hclCreate{config: Config{ModulePatterns: modulePatterns}}
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws_s3_bucket.tfer--logs":                     "workspace_1",
+		"aws_s3_bucket_policy.tfer--logs":              "workspace_1",
+		"aws_s3_bucket_public_access_block.tfer--logs": "workspace_1",
+		"aws_s3_bucket_policy.tfer--other":             "workspace_1",
+	}
+	var resourceNamesErr error
+	h.resourceNames, resourceNamesErr = h.assignResourceNames(newResourceToWorkspace, map[string]ResourceNameData{}, map[string]map[string]bool{})
+	require.NoError(t, resourceNamesErr)
+
+	h.placeResourcesIntoModules(
+		newResourceToWorkspace,
+		map[string]map[string]string{
+			"aws_s3_bucket.tfer--logs":                     {"id": "logs", "bucket": "logs"},
+			"aws_s3_bucket_policy.tfer--logs":              {"id": "logs", "bucket": "logs"},
+			"aws_s3_bucket_public_access_block.tfer--logs": {"id": "logs", "bucket": "logs"},
+			"aws_s3_bucket_policy.tfer--other":             {"id": "other", "bucket": "other"},
+		},
+		map[string]map[string]bool{"workspace_1": {"module.logs": true}},
+	)
+
+	resources, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_s3_bucket" "tfer--logs" {
+  bucket = "logs"
+}
+resource "aws_s3_bucket_policy" "tfer--logs" {
+  bucket = "logs"
+  policy = "{}"
+}
+resource "aws_s3_bucket_public_access_block" "tfer--logs" {
+  block_public_acls = "true"
+  bucket            = "logs"
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	require.False(t, diagnostics.HasErrors())
+
+	return h, newResourceToWorkspace, resources
+}
+
+func TestPlaceResourcesIntoModules_ExistingModule(t *testing.T) {
+	// Given
+	h, _, resources := s3BucketModuleFixture(t, "git::https://github.com/org/modules.git//s3-bucket")
+	workspaceFile := hclwrite.NewEmptyFile()
+
+	// When
+	for _, block := range resources.Body().Blocks() {
+		resource := block.Labels()[0] + "." + block.Labels()[1]
+		h.addBlockToModulePlacement(h.resourceToModulePlacement[resource], resource, nil, block)
+	}
+	h.appendModuleCalls(WorkspaceToHCL{"workspace_1": workspaceFile})
+
+	// Then
+	assert.Equal(t, "module.logs_2.aws_s3_bucket.this", h.resourceAddress(ResourceIdentifier{"aws_s3_bucket", "tfer--logs"}))
+	assert.Equal(t, "module.logs_2.aws_s3_bucket_policy.this", h.resourceAddress(ResourceIdentifier{"aws_s3_bucket_policy", "tfer--logs"}))
+	assert.Equal(t, "aws_s3_bucket_policy.other", h.resourceAddress(ResourceIdentifier{"aws_s3_bucket_policy", "tfer--other"}))
+	assert.Equal(t, "import module.logs_2.aws_s3_bucket_public_access_block.this logs", h.generateImportStatementText(
+		"logs", ResourceIdentifier{"aws_s3_bucket_public_access_block", "tfer--logs"},
+	))
+
+	expected := `
+module "logs_2" {
+  source = "git::https://github.com/org/modules.git//s3-bucket"
+  bucket = "logs"
+  policy = "{}"
+}
+
+`
+	assert.Equal(t, expected, string(hclwrite.Format(workspaceFile.Bytes())))
+}
+
+func TestPlaceResourcesIntoModules_LocalModule(t *testing.T) {
+	// Given
+	h, _, resources := s3BucketModuleFixture(t, "")
+	workspaceFile := hclwrite.NewEmptyFile()
+
+	// When
+	for _, block := range resources.Body().Blocks() {
+		resource := block.Labels()[0] + "." + block.Labels()[1]
+		placement := h.resourceToModulePlacement[resource]
+		block.SetLabels([]string{block.Labels()[0], h.resourceName(h.resourceToIdentifierStruct(resource))})
+		h.addBlockToModulePlacement(placement, resource, nil, block)
+	}
+	h.appendModuleCalls(WorkspaceToHCL{"workspace_1": workspaceFile})
+
+	// Then
+	assert.Equal(t, "module.logs_2.aws_s3_bucket_policy.logs", h.resourceAddress(ResourceIdentifier{"aws_s3_bucket_policy", "tfer--logs"}))
+
+	expected := `
+module "logs_2" {
+  source = "./modules/logs_2"
+}
+
+`
+	assert.Equal(t, expected, string(hclwrite.Format(workspaceFile.Bytes())))
+
+	policy := h.resourceToModulePlacement["aws_s3_bucket_policy.tfer--logs"].resourceToBlock["aws_s3_bucket_policy.tfer--logs"]
+	assert.Equal(t, `resource "aws_s3_bucket_policy" "logs" {
+  bucket = aws_s3_bucket.logs.id
+  policy = "{}"
+}
+`, string(hclwrite.Format(policy.BuildTokens(nil).Bytes())))
+}
+
+func TestModulePatternsDecode_MissingAddress(t *testing.T) {
+	// Given
+	modulePatterns := ModulePatterns{}
+
+	// When
+	err := modulePatterns.Decode(`[{"name": "s3-bucket", "source": "./s3", "primaryResourceType": "aws_s3_bucket"}]`)
+
+	// Then
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,88 @@
+package hclcreate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+const (
+	// KeepOrphanedResourceBlocks leaves the resource blocks of deleted cloud resources unchanged.
+	KeepOrphanedResourceBlocks = "keep"
+
+	// CommentOrphanedResourceBlocks comments out the resource blocks of deleted cloud resources.
+	CommentOrphanedResourceBlocks = "comment"
+
+	// DeleteOrphanedResourceBlocks deletes the resource blocks of deleted cloud resources.
+	DeleteOrphanedResourceBlocks = "delete"
+)
+
+// updateOrphanedResourceBlocks comments out or deletes the root module resource blocks of each workspace which
+// correspond to deleted cloud resources.
+func (h *hclCreate) updateOrphanedResourceBlocks(
+	workspaceToOrphanedResources map[string][]string,
+	workspaceToDirectory map[string]string,
+) error {
+	if h.config.OrphanedResourceBlocks != CommentOrphanedResourceBlocks &&
+		h.config.OrphanedResourceBlocks != DeleteOrphanedResourceBlocks {
+		return nil
+	}
+
+	for _, workspace := range sortedKeys(workspaceToOrphanedResources) {
+		directory, ok := workspaceToDirectory[workspace]
+		if !ok {
+			continue
+		}
+
+		pathToFile := map[string]*hclwrite.File{}
+		resources, err := loadConfiguredResources(fmt.Sprintf("repo%v", directory), pathToFile)
+		if err != nil {
+			return fmt.Errorf("[loadConfiguredResources]%v", err)
+		}
+
+		pathToOrphanedBlocks := map[string][]*hclwrite.Block{}
+		for _, address := range workspaceToOrphanedResources[workspace] {
+			if resource, ok := resources[address]; ok {
+				pathToOrphanedBlocks[resource.path] = append(pathToOrphanedBlocks[resource.path], resource.block)
+			}
+		}
+
+		for path, blocks := range pathToOrphanedBlocks {
+			fileBytes := removeOrphanedBlocks(pathToFile[path], blocks, h.config.OrphanedResourceBlocks == CommentOrphanedResourceBlocks)
+
+			err = os.WriteFile(path, fileBytes, 0o400)
+			if err != nil {
+				return fmt.Errorf("[os.WriteFile %v]%v", path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// removeOrphanedBlocks returns the contents of the file with each block either commented out in place or deleted.
+func removeOrphanedBlocks(hclFile *hclwrite.File, blocks []*hclwrite.Block, commentOut bool) []byte {
+	if !commentOut {
+		for _, block := range blocks {
+			hclFile.Body().RemoveBlock(block)
+		}
+		return bytes.TrimLeft(hclwrite.Format(hclFile.Bytes()), "\n")
+	}
+
+	fileText := string(hclFile.Bytes())
+	for _, block := range blocks {
+		blockText := string(block.BuildTokens(nil).Bytes())
+
+		commentedLines := []string{"# The cloud resource below no longer exists and has been removed from state by cloud-concierge."}
+		for _, line := range strings.Split(strings.TrimSuffix(blockText, "\n"), "\n") {
+			commentedLines = append(commentedLines, strings.TrimRight("# "+line, " "))
+		}
+
+		fileText = strings.Replace(fileText, blockText, strings.Join(commentedLines, "\n")+"\n", 1)
+	}
+
+	return []byte(fileText)
+}
@@ -0,0 +1,611 @@
+package hclcreate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// parameterizableAttributes maps attributes whose values are commonly repeated across resources, such as the
+// project or region, to the name of the variable created for them.
+var parameterizableAttributes = map[string]string{
+	"location":            "location",
+	"project":             "project_id",
+	"region":              "region",
+	"resource_group_name": "resource_group_name",
+	"zone":                "zone",
+}
+
+// accountIDAttributes are attributes whose value is an AWS account id.
+var accountIDAttributes = map[string]bool{
+	"account_id": true,
+	"owner_id":   true,
+}
+
+// tagAttributes maps attributes holding tags or labels to the name of the local created for their common subset.
+var tagAttributes = map[string]string{
+	"labels": "common_labels",
+	"tags":   "common_tags",
+}
+
+// arnAccountID matches the account id within an AWS ARN.
+var arnAccountID = regexp.MustCompile(`^arn:aws[a-z-]*:[^:]*:[^:]*:(\d{12}):`)
+
+// minimumRepetitions is the minimum number of generated blocks a literal must appear within to be extracted.
+const minimumRepetitions = 2
+
+// existingParameters are the variables and locals already defined within a workspace.
+type existingParameters struct {
+	// variables is a map between variable names and their value, either from the variable's default or a tfvars file.
+	// Variables without a known literal value are omitted.
+	variables map[string]cty.Value
+
+	// locals is a map between local names and their value. Locals without a literal value are omitted.
+	locals map[string]cty.Value
+
+	// names is the set of all declared "var.<name>" and "local.<name>" references.
+	names map[string]bool
+}
+
+// parameterization is the set of new variables and locals created while parameterizing a workspace's generated HCL.
+type parameterization struct {
+	// variables is a map between new variable names and their default value.
+	variables map[string]cty.Value
+
+	// locals is a map between new local names and their value.
+	locals map[string]cty.Value
+}
+
+// bodyReference is a body within the generated HCL along with the index of the top level block containing it.
+type bodyReference struct {
+	blockIndex int
+	body       *hclwrite.Body
+}
+
+// parameterizeWorkspaces replaces literals repeated across the generated blocks of each workspace with references to
+// variables and locals, reusing those already defined within the workspace and otherwise appending new definitions
+// to the workspace's variables.tf and locals.tf files.
+func (h *hclCreate) parameterizeWorkspaces(workspaceToHCLFile WorkspaceToHCL, workspaceToDirectory map[string]string) error {
+	for workspace, hclFile := range workspaceToHCLFile {
+		directory := fmt.Sprintf("repo%v", workspaceToDirectory[workspace])
+
+		existing, err := loadExistingParameters(directory)
+		if err != nil {
+			return fmt.Errorf("[loadExistingParameters] %v: %v", directory, err)
+		}
+
+		newParameters := parameterizeFile(hclFile, existing)
+
+		err = appendParameterDefinitions(directory, newParameters)
+		if err != nil {
+			return fmt.Errorf("[appendParameterDefinitions] %v: %v", directory, err)
+		}
+	}
+
+	return nil
+}
+
+// loadExistingParameters reads the variables and locals defined within the .tf files of a directory, along with
+// variable values set within terraform.tfvars and *.auto.tfvars files.
+func loadExistingParameters(directory string) (existingParameters, error) {
+	existing := existingParameters{
+		variables: map[string]cty.Value{},
+		locals:    map[string]cty.Value{},
+		names:     map[string]bool{},
+	}
+
+	configFiles, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return existing, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	for _, configFile := range configFiles {
+		hclFile, err := parseHCLFile(configFile)
+		if err != nil {
+			return existing, err
+		}
+
+		for _, block := range hclFile.Body().Blocks() {
+			switch {
+			case block.Type() == "variable" && len(block.Labels()) == 1:
+				name := block.Labels()[0]
+				existing.names["var."+name] = true
+				if defaultAttribute := block.Body().GetAttribute("default"); defaultAttribute != nil {
+					if value, ok := literalValue(defaultAttribute); ok {
+						existing.variables[name] = value
+					}
+				}
+			case block.Type() == "locals":
+				for name, attribute := range block.Body().Attributes() {
+					existing.names["local."+name] = true
+					if value, ok := literalValue(attribute); ok {
+						existing.locals[name] = value
+					}
+				}
+			}
+		}
+	}
+
+	variableFiles, err := filepath.Glob(filepath.Join(directory, "*.auto.tfvars"))
+	if err != nil {
+		return existing, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+	variableFiles = append([]string{filepath.Join(directory, "terraform.tfvars")}, variableFiles...)
+
+	for _, variableFile := range variableFiles {
+		hclFile, err := parseHCLFile(variableFile)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return existing, err
+		}
+
+		for name, attribute := range hclFile.Body().Attributes() {
+			if !existing.names["var."+name] {
+				continue
+			}
+
+			if value, ok := literalValue(attribute); ok {
+				existing.variables[name] = value
+			} else {
+				delete(existing.variables, name)
+			}
+		}
+	}
+
+	return existing, nil
+}
+
+// parseHCLFile reads and parses the HCL file at path.
+func parseHCLFile(path string) (*hclwrite.File, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile %v]%w", path, err)
+	}
+
+	hclFile, diagnostics := hclwrite.ParseConfig(fileBytes, path, hcl.Pos{Line: 1, Column: 1})
+	if diagnostics.HasErrors() {
+		return nil, fmt.Errorf("[hclwrite.ParseConfig %v]%v", path, diagnostics)
+	}
+
+	return hclFile, nil
+}
+
+// parameterizeFile replaces repeated literals within the resource and module blocks of hclFile with references,
+// returning the new variables and locals that must be defined.
+func parameterizeFile(hclFile *hclwrite.File, existing existingParameters) parameterization {
+	newParameters := parameterization{variables: map[string]cty.Value{}, locals: map[string]cty.Value{}}
+
+	var bodies []bodyReference
+	var topLevelBodies []bodyReference
+	for i, block := range hclFile.Body().Blocks() {
+		if block.Type() != "resource" && block.Type() != "module" {
+			continue
+		}
+		topLevelBodies = append(topLevelBodies, bodyReference{blockIndex: i, body: block.Body()})
+		bodies = append(bodies, nestedBodies(i, block.Body())...)
+	}
+
+	attributeNames := sortedKeys(parameterizableAttributes)
+	for _, attributeName := range attributeNames {
+		valueToBlocks := map[string]map[int]bool{}
+		for _, reference := range bodies {
+			if value, ok := stringAttributeValue(reference.body, attributeName); ok {
+				addValueOccurrence(valueToBlocks, value, reference.blockIndex)
+			}
+		}
+
+		value, ok := mostRepeatedValue(valueToBlocks)
+		if !ok {
+			continue
+		}
+
+		address := existing.reference(cty.StringVal(value), parameterizableAttributes[attributeName], newParameters)
+		for _, reference := range bodies {
+			if current, ok := stringAttributeValue(reference.body, attributeName); ok && current == value {
+				reference.body.SetAttributeRaw(attributeName, hclwrite.TokensForIdentifier(address))
+			}
+		}
+	}
+
+	parameterizeAccountID(bodies, existing, newParameters)
+
+	for _, attributeName := range sortedKeys(tagAttributes) {
+		parameterizeTags(topLevelBodies, attributeName, existing, newParameters)
+	}
+
+	return newParameters
+}
+
+// parameterizeAccountID replaces a repeated AWS account id, both as the value of account id attributes and within
+// ARNs, with a reference.
+func parameterizeAccountID(bodies []bodyReference, existing existingParameters, newParameters parameterization) {
+	valueToBlocks := map[string]map[int]bool{}
+	for _, reference := range bodies {
+		for attributeName, attribute := range reference.body.Attributes() {
+			for _, value := range stringValues(attribute) {
+				if accountIDAttributes[attributeName] {
+					addValueOccurrence(valueToBlocks, value, reference.blockIndex)
+				} else if match := arnAccountID.FindStringSubmatch(value); match != nil {
+					addValueOccurrence(valueToBlocks, match[1], reference.blockIndex)
+				}
+			}
+		}
+	}
+
+	accountID, ok := mostRepeatedValue(valueToBlocks)
+	if !ok {
+		return
+	}
+
+	address := existing.reference(cty.StringVal(accountID), "account_id", newParameters)
+	for _, reference := range bodies {
+		for attributeName, attribute := range reference.body.Attributes() {
+			if accountIDAttributes[attributeName] {
+				if value, ok := stringAttributeValue(reference.body, attributeName); ok && value == accountID {
+					reference.body.SetAttributeRaw(attributeName, hclwrite.TokensForIdentifier(address))
+				}
+				continue
+			}
+
+			value, ok := literalValue(attribute)
+			if !ok {
+				continue
+			}
+
+			if value.Type() == cty.String && arnContainsAccountID(value.AsString(), accountID) {
+				reference.body.SetAttributeRaw(attributeName, accountTemplateTokens(value.AsString(), accountID, address))
+				continue
+			}
+
+			if (value.Type().IsTupleType() || value.Type().IsListType()) && len(stringValues(attribute)) == value.LengthInt() {
+				var elements []hclwrite.Tokens
+				rewritten := false
+				for _, element := range value.AsValueSlice() {
+					if arnContainsAccountID(element.AsString(), accountID) {
+						elements = append(elements, accountTemplateTokens(element.AsString(), accountID, address))
+						rewritten = true
+					} else {
+						elements = append(elements, hclwrite.TokensForValue(element))
+					}
+				}
+				if rewritten {
+					reference.body.SetAttributeRaw(attributeName, hclwrite.TokensForTuple(elements))
+				}
+			}
+		}
+	}
+}
+
+// parameterizeTags extracts the tags shared by every generated block into a local, preferring an existing local
+// that is a subset of every block's tags. Each block's tags are rewritten to merge the local with its remaining tags.
+func parameterizeTags(
+	topLevelBodies []bodyReference,
+	attributeName string,
+	existing existingParameters,
+	newParameters parameterization,
+) {
+	var taggedBodies []*hclwrite.Body
+	var tagMaps []map[string]string
+	for _, reference := range topLevelBodies {
+		attribute := reference.body.GetAttribute(attributeName)
+		if attribute == nil {
+			continue
+		}
+
+		tags, ok := stringMapValue(attribute)
+		if !ok || len(tags) == 0 {
+			continue
+		}
+
+		taggedBodies = append(taggedBodies, reference.body)
+		tagMaps = append(tagMaps, tags)
+	}
+
+	if len(tagMaps) < minimumRepetitions {
+		return
+	}
+
+	var address string
+	var commonTags map[string]string
+	for _, name := range sortedKeys(existing.locals) {
+		localTags, ok := stringMapFromValue(existing.locals[name])
+		if !ok || len(localTags) <= len(commonTags) || !isSubsetOfAll(localTags, tagMaps) {
+			continue
+		}
+		address, commonTags = "local."+name, localTags
+	}
+
+	if address == "" {
+		commonTags = map[string]string{}
+		for key, value := range tagMaps[0] {
+			commonTags[key] = value
+		}
+		for _, tags := range tagMaps[1:] {
+			for key, value := range commonTags {
+				if tags[key] != value {
+					delete(commonTags, key)
+				}
+			}
+		}
+
+		if len(commonTags) == 0 {
+			return
+		}
+
+		name := existing.uniqueName("local", tagAttributes[attributeName], newParameters)
+		newParameters.locals[name] = stringMapToValue(commonTags)
+		address = "local." + name
+	}
+
+	for i, body := range taggedBodies {
+		remainingTags := map[string]string{}
+		for key, value := range tagMaps[i] {
+			if _, ok := commonTags[key]; !ok {
+				remainingTags[key] = value
+			}
+		}
+
+		if len(remainingTags) == 0 {
+			body.SetAttributeRaw(attributeName, hclwrite.TokensForIdentifier(address))
+			continue
+		}
+
+		body.SetAttributeRaw(attributeName, hclwrite.TokensForFunctionCall(
+			"merge", hclwrite.TokensForIdentifier(address), hclwrite.TokensForValue(stringMapToValue(remainingTags)),
+		))
+	}
+}
+
+// reference returns the reference to an existing variable or local with the given value, or otherwise records a new
+// variable with the given value and returns a reference to it.
+func (e existingParameters) reference(value cty.Value, variableName string, newParameters parameterization) string {
+	for _, name := range sortedKeys(e.variables) {
+		if valuesEqual(e.variables[name], value) {
+			return "var." + name
+		}
+	}
+	for _, name := range sortedKeys(e.locals) {
+		if valuesEqual(e.locals[name], value) {
+			return "local." + name
+		}
+	}
+	for _, name := range sortedKeys(newParameters.variables) {
+		if valuesEqual(newParameters.variables[name], value) {
+			return "var." + name
+		}
+	}
+
+	name := e.uniqueName("var", variableName, newParameters)
+	newParameters.variables[name] = value
+	return "var." + name
+}
+
+// uniqueName returns name, suffixed with a number if needed, such that it is not already declared within the workspace.
+func (e existingParameters) uniqueName(kind string, name string, newParameters parameterization) string {
+	isTaken := func(candidate string) bool {
+		_, isNewVariable := newParameters.variables[candidate]
+		_, isNewLocal := newParameters.locals[candidate]
+		return e.names[kind+"."+candidate] || (kind == "var" && isNewVariable) || (kind == "local" && isNewLocal)
+	}
+
+	candidate := name
+	for suffix := 2; isTaken(candidate); suffix++ {
+		candidate = fmt.Sprintf("%v_%d", name, suffix)
+	}
+	return candidate
+}
+
+// appendParameterDefinitions appends new variables to variables.tf and new locals to locals.tf within directory,
+// creating the files if needed.
+func appendParameterDefinitions(directory string, newParameters parameterization) error {
+	if len(newParameters.variables) > 0 {
+		variablesFile := hclwrite.NewEmptyFile()
+		for _, name := range sortedKeys(newParameters.variables) {
+			variablesFile.Body().AppendNewline()
+			variableBody := variablesFile.Body().AppendNewBlock("variable", []string{name}).Body()
+			variableBody.SetAttributeRaw("type", hclwrite.TokensForIdentifier("string"))
+			variableBody.SetAttributeValue("default", newParameters.variables[name])
+		}
+
+		err := appendToFile(filepath.Join(directory, "variables.tf"), hclwrite.Format(variablesFile.Bytes()))
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(newParameters.locals) > 0 {
+		localsFile := hclwrite.NewEmptyFile()
+		localsFile.Body().AppendNewline()
+		localsBody := localsFile.Body().AppendNewBlock("locals", nil).Body()
+		for _, name := range sortedKeys(newParameters.locals) {
+			localsBody.SetAttributeValue(name, newParameters.locals[name])
+		}
+
+		err := appendToFile(filepath.Join(directory, "locals.tf"), hclwrite.Format(localsFile.Bytes()))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appendToFile appends content to the file at path, creating the file if it does not exist.
+func appendToFile(path string, content []byte) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.OpenFile %v]%v", path, err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(content)
+	if err != nil {
+		return fmt.Errorf("[file.Write %v]%v", path, err)
+	}
+
+	return nil
+}
+
+// nestedBodies returns body along with the bodies of all blocks nested within it.
+func nestedBodies(blockIndex int, body *hclwrite.Body) []bodyReference {
+	bodies := []bodyReference{{blockIndex: blockIndex, body: body}}
+	for _, block := range body.Blocks() {
+		bodies = append(bodies, nestedBodies(blockIndex, block.Body())...)
+	}
+	return bodies
+}
+
+// addValueOccurrence records that value occurs within the top level block at blockIndex.
+func addValueOccurrence(valueToBlocks map[string]map[int]bool, value string, blockIndex int) {
+	if value == "" {
+		return
+	}
+	if valueToBlocks[value] == nil {
+		valueToBlocks[value] = map[int]bool{}
+	}
+	valueToBlocks[value][blockIndex] = true
+}
+
+// mostRepeatedValue returns the value occurring within the most blocks, if it occurs within at least
+// minimumRepetitions blocks. Ties are broken by the lexically smallest value.
+func mostRepeatedValue(valueToBlocks map[string]map[int]bool) (string, bool) {
+	mostRepeated := ""
+	for _, value := range sortedKeys(valueToBlocks) {
+		if len(valueToBlocks[value]) > len(valueToBlocks[mostRepeated]) {
+			mostRepeated = value
+		}
+	}
+
+	return mostRepeated, len(valueToBlocks[mostRepeated]) >= minimumRepetitions
+}
+
+// stringAttributeValue returns the value of the named attribute within body if it is a literal string.
+func stringAttributeValue(body *hclwrite.Body, attributeName string) (string, bool) {
+	attribute := body.GetAttribute(attributeName)
+	if attribute == nil {
+		return "", false
+	}
+
+	value, ok := literalValue(attribute)
+	if !ok || value.Type() != cty.String {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// stringValues returns the literal strings of an attribute that is either a string or a list of strings.
+func stringValues(attribute *hclwrite.Attribute) []string {
+	value, ok := literalValue(attribute)
+	if !ok {
+		return nil
+	}
+
+	if value.Type() == cty.String {
+		return []string{value.AsString()}
+	}
+
+	var values []string
+	if value.Type().IsTupleType() || value.Type().IsListType() {
+		for _, element := range value.AsValueSlice() {
+			if element.Type() == cty.String && !element.IsNull() {
+				values = append(values, element.AsString())
+			}
+		}
+	}
+	return values
+}
+
+// stringMapValue returns the value of an attribute that is a literal map of strings.
+func stringMapValue(attribute *hclwrite.Attribute) (map[string]string, bool) {
+	value, ok := literalValue(attribute)
+	if !ok {
+		return nil, false
+	}
+	return stringMapFromValue(value)
+}
+
+// stringMapFromValue converts an object or map value with only string values into a map.
+func stringMapFromValue(value cty.Value) (map[string]string, bool) {
+	if !value.Type().IsObjectType() && !value.Type().IsMapType() {
+		return nil, false
+	}
+
+	stringMap := map[string]string{}
+	for key, element := range value.AsValueMap() {
+		if element.Type() != cty.String || element.IsNull() {
+			return nil, false
+		}
+		stringMap[key] = element.AsString()
+	}
+	return stringMap, true
+}
+
+// stringMapToValue converts a map of strings into an object value.
+func stringMapToValue(stringMap map[string]string) cty.Value {
+	values := map[string]cty.Value{}
+	for key, value := range stringMap {
+		values[key] = cty.StringVal(value)
+	}
+	return cty.ObjectVal(values)
+}
+
+// isSubsetOfAll returns true if every pair within subset is present within each of the maps.
+func isSubsetOfAll(subset map[string]string, maps []map[string]string) bool {
+	for _, stringMap := range maps {
+		for key, value := range subset {
+			if current, ok := stringMap[key]; !ok || current != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// valuesEqual returns true if both values are known strings with the same content.
+func valuesEqual(a cty.Value, b cty.Value) bool {
+	return a.Type() == cty.String && b.Type() == cty.String && !a.IsNull() && !b.IsNull() && a.AsString() == b.AsString()
+}
+
+// arnContainsAccountID returns true if value is an ARN within the given account.
+func arnContainsAccountID(value string, accountID string) bool {
+	match := arnAccountID.FindStringSubmatch(value)
+	return match != nil && match[1] == accountID
+}
+
+// accountTemplateTokens builds a string template equal to value with each occurrence of accountID replaced by an
+// interpolation of address.
+func accountTemplateTokens(value string, accountID string, address string) hclwrite.Tokens {
+	parts := strings.Split(value, accountID)
+	for i, part := range parts {
+		quoted := string(hclwrite.TokensForValue(cty.StringVal(part)).Bytes())
+		parts[i] = quoted[1 : len(quoted)-1]
+	}
+
+	template := fmt.Sprintf("value = \"%v\"\n", strings.Join(parts, fmt.Sprintf("${%v}", address)))
+	templateFile, diagnostics := hclwrite.ParseConfig([]byte(template), "", hcl.Pos{Line: 1, Column: 1})
+	if diagnostics.HasErrors() {
+		return hclwrite.TokensForValue(cty.StringVal(value))
+	}
+
+	return templateFile.Body().GetAttribute("value").Expr().BuildTokens(nil)
+}
+
+// sortedKeys returns the keys of a map with string keys in sorted order.
+func sortedKeys[V any](stringMap map[string]V) []string {
+	keys := make([]string, 0, len(stringMap))
+	for key := range stringMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
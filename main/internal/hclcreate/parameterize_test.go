@@ -0,0 +1,129 @@
+package hclcreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestLoadExistingParameters(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "variables.tf"), []byte(`variable "aws_region" {
+  type    = string
+  default = "us-west-2"
+}
+
+variable "environment" {
+  type = string
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "locals.tf"), []byte(`locals {
+  common_tags = {
+    Team = "platform"
+  }
+  name_prefix = "${var.environment}-app"
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "terraform.tfvars"), []byte(`aws_region  = "us-east-1"
+environment = "prod"
+`), 0o600))
+
+	// When
+	existing, err := loadExistingParameters(directory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cty.Value{
+		"aws_region":  cty.StringVal("us-east-1"),
+		"environment": cty.StringVal("prod"),
+	}, existing.variables)
+	assert.Equal(t, map[string]cty.Value{
+		"common_tags": cty.ObjectVal(map[string]cty.Value{"Team": cty.StringVal("platform")}),
+	}, existing.locals)
+	assert.Equal(t, map[string]bool{
+		"var.aws_region":    true,
+		"var.environment":   true,
+		"local.common_tags": true,
+		"local.name_prefix": true,
+	}, existing.names)
+}
+
+func TestParameterizeFile(t *testing.T) {
+	// Given
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_iam_role_policy_attachment" "a" {
+  policy_arn = "arn:aws:iam::123456789012:policy/a"
+  region     = "us-east-1"
+  tags = {
+    Name = "a"
+    Team = "platform"
+    Env  = "prod"
+  }
+}
+
+resource "aws_iam_role_policy_attachment" "b" {
+  policy_arn = "arn:aws:iam::123456789012:policy/b"
+  region     = "us-east-1"
+  tags = {
+    Team = "platform"
+    Env  = "prod"
+  }
+}
+
+resource "aws_sns_topic" "c" {
+  region = "eu-west-1"
+  tags = {
+    Name = "c"
+    Env  = "prod"
+  }
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	require.False(t, diagnostics.HasErrors())
+
+	existing := existingParameters{
+		variables: map[string]cty.Value{"aws_region": cty.StringVal("us-east-1")},
+		locals:    map[string]cty.Value{},
+		names:     map[string]bool{"var.aws_region": true, "var.account_id": true},
+	}
+
+	// When
+	newParameters := parameterizeFile(hclFile, existing)
+
+	// Then
+	assert.Equal(t, map[string]cty.Value{"account_id_2": cty.StringVal("123456789012")}, newParameters.variables)
+	assert.Equal(t, map[string]cty.Value{
+		"common_tags": cty.ObjectVal(map[string]cty.Value{"Env": cty.StringVal("prod")}),
+	}, newParameters.locals)
+
+	expected := `resource "aws_iam_role_policy_attachment" "a" {
+  policy_arn = "arn:aws:iam::${var.account_id_2}:policy/a"
+  region     = var.aws_region
+  tags = merge(local.common_tags, {
+    Name = "a"
+    Team = "platform"
+  })
+}
+
+resource "aws_iam_role_policy_attachment" "b" {
+  policy_arn = "arn:aws:iam::${var.account_id_2}:policy/b"
+  region     = var.aws_region
+  tags = merge(local.common_tags, {
+    Team = "platform"
+  })
+}
+
+resource "aws_sns_topic" "c" {
+  region = "eu-west-1"
+  tags = merge(local.common_tags, {
+    Name = "c"
+  })
+}
+`
+	assert.Equal(t, expected, string(hclwrite.Format(hclFile.Bytes())))
+}
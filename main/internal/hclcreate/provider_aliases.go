@@ -0,0 +1,188 @@
+package hclcreate
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// workspaceProviders are the AWS provider configurations defined within a workspace.
+type workspaceProviders struct {
+	// defaultRegion is the region of the default, un-aliased, provider configuration. Empty if it cannot be determined.
+	defaultRegion string
+
+	// regionToAlias is a map between regions and the alias of the provider configuration for that region.
+	regionToAlias map[string]string
+
+	// aliases is the set of aliases already in use.
+	aliases map[string]bool
+}
+
+// resourceRegion returns the region of an AWS resource, or an empty string for global resources and resources whose
+// region cannot be determined from their attributes.
+func resourceRegion(resourceType string, attributesFlat map[string]string) string {
+	if !strings.HasPrefix(resourceType, "aws_") {
+		return ""
+	}
+
+	arnSplit := strings.Split(attributesFlat["arn"], ":")
+	if attributesFlat["region"] == "" && (len(arnSplit) < 4 || arnSplit[3] == "") {
+		return ""
+	}
+
+	region, err := driftDetector.ParseRegionFromTfStateMap(attributesFlat, "aws")
+	if err != nil {
+		return ""
+	}
+	return region
+}
+
+// assignProviderAliases sets the provider configuration of each new AWS resource outside of the default provider's
+// region, reusing aliased providers already defined within the workspace and otherwise recording new aliased
+// providers to be written alongside the new resources.
+func (h *hclCreate) assignProviderAliases(
+	resourceToRegion map[string]string,
+	workspaceToDirectory map[string]string,
+) error {
+	h.workspaceToNewProviders = map[string]map[string]string{}
+
+	workspaceToProviders := map[string]workspaceProviders{}
+	for _, resourceID := range sortedKeys(resourceToRegion) {
+		region := resourceToRegion[resourceID]
+		generatedName, ok := h.resourceNames[resourceID]
+		if !ok || region == "" {
+			continue
+		}
+
+		providers, ok := workspaceToProviders[generatedName.Workspace]
+		if !ok {
+			var err error
+			providers, err = loadWorkspaceProviders(fmt.Sprintf("repo%v", workspaceToDirectory[generatedName.Workspace]))
+			if err != nil {
+				return fmt.Errorf("[loadWorkspaceProviders]%v", err)
+			}
+			workspaceToProviders[generatedName.Workspace] = providers
+		}
+
+		if region == providers.defaultRegion {
+			continue
+		}
+
+		alias, ok := providers.regionToAlias[region]
+		if !ok {
+			baseAlias := strings.ReplaceAll(region, "-", "_")
+			alias = baseAlias
+			for suffix := 2; providers.aliases[alias]; suffix++ {
+				alias = fmt.Sprintf("%v_%d", baseAlias, suffix)
+			}
+
+			providers.regionToAlias[region] = alias
+			providers.aliases[alias] = true
+			if h.workspaceToNewProviders[generatedName.Workspace] == nil {
+				h.workspaceToNewProviders[generatedName.Workspace] = map[string]string{}
+			}
+			h.workspaceToNewProviders[generatedName.Workspace][alias] = region
+		}
+
+		generatedName.Provider = "aws." + alias
+		h.resourceNames[resourceID] = generatedName
+	}
+
+	return nil
+}
+
+// loadWorkspaceProviders reads the AWS provider configurations defined within the .tf files of a directory. Regions
+// set through variables are resolved using the variable's default or tfvars value.
+func loadWorkspaceProviders(directory string) (workspaceProviders, error) {
+	providers := workspaceProviders{regionToAlias: map[string]string{}, aliases: map[string]bool{}}
+
+	existing, err := loadExistingParameters(directory)
+	if err != nil {
+		return providers, fmt.Errorf("[loadExistingParameters]%v", err)
+	}
+
+	configFiles, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return providers, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	for _, configFile := range configFiles {
+		hclFile, err := parseHCLFile(configFile)
+		if err != nil {
+			return providers, err
+		}
+
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() != "provider" || len(block.Labels()) != 1 || block.Labels()[0] != "aws" {
+				continue
+			}
+
+			region := providerRegion(block.Body(), existing)
+			alias, hasAlias := stringAttributeValue(block.Body(), "alias")
+			if !hasAlias {
+				providers.defaultRegion = region
+				continue
+			}
+
+			providers.aliases[alias] = true
+			if _, ok := providers.regionToAlias[region]; region != "" && !ok {
+				providers.regionToAlias[region] = alias
+			}
+		}
+	}
+
+	return providers, nil
+}
+
+// providerRegion returns the region of a provider configuration, which is either a literal or a reference to a
+// variable with a known value.
+func providerRegion(body *hclwrite.Body, existing existingParameters) string {
+	if region, ok := stringAttributeValue(body, "region"); ok {
+		return region
+	}
+
+	attribute := body.GetAttribute("region")
+	if attribute == nil {
+		return ""
+	}
+
+	reference := strings.TrimSpace(string(attribute.Expr().BuildTokens(nil).Bytes()))
+	if value, ok := existing.variables[strings.TrimPrefix(reference, "var.")]; ok && strings.HasPrefix(reference, "var.") {
+		if value.Type() == cty.String && !value.IsNull() {
+			return value.AsString()
+		}
+	}
+	return ""
+}
+
+// setResourceProvider sets the provider meta-argument of a resource block to the resource's aliased provider, if any.
+func (h *hclCreate) setResourceProvider(block *hclwrite.Block, resource string) {
+	if provider := h.resourceNames[resource].Provider; provider != "" {
+		block.Body().SetAttributeRaw("provider", hclwrite.TokensForIdentifier(provider))
+	}
+}
+
+// appendProviderAliases writes the new aliased provider configurations of each workspace into its file.
+func (h *hclCreate) appendProviderAliases(workspaceToHCLFile WorkspaceToHCL) {
+	workspaces := make([]string, 0, len(h.workspaceToNewProviders))
+	for workspace := range h.workspaceToNewProviders {
+		workspaces = append(workspaces, workspace)
+	}
+	sort.Strings(workspaces)
+
+	for _, workspace := range workspaces {
+		aliasToRegion := h.workspaceToNewProviders[workspace]
+		for _, alias := range sortedKeys(aliasToRegion) {
+			providerBody := workspaceToHCLFile[workspace].Body().AppendNewBlock("provider", []string{"aws"}).Body()
+			providerBody.SetAttributeValue("alias", cty.StringVal(alias))
+			providerBody.SetAttributeValue("region", cty.StringVal(aliasToRegion[alias]))
+			workspaceToHCLFile[workspace].Body().AppendNewline()
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package hclcreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceRegion(t *testing.T) {
+	// Given
+	inputToExpected := map[string]map[string]string{
+		"eu-west-1": {"arn": "arn:aws:sqs:eu-west-1:123456789012:queue"},
+		"us-west-2": {"region": "us-west-2"},
+		"":          {"arn": "arn:aws:iam::123456789012:role/global"},
+	}
+
+	for expected, attributesFlat := range inputToExpected {
+		// When
+		region := resourceRegion("aws_sqs_queue", attributesFlat)
+
+		// Then
+		assert.Equal(t, expected, region)
+	}
+	assert.Equal(t, "", resourceRegion("google_storage_bucket", map[string]string{"location": "US"}))
+}
+
+func TestAssignProviderAliases(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	directory := filepath.Join("repo", "workspace")
+	require.NoError(t, os.MkdirAll(directory, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "providers.tf"), []byte(`provider "aws" {
+  region = var.region
+}
+
+provider "aws" {
+  alias  = "ireland"
+  region = "eu-west-1"
+}
+
+provider "aws" {
+  alias  = "ap_southeast_2"
+  region = "ap-southeast-1"
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "variables.tf"), []byte(`variable "region" {
+  default = "us-east-1"
+}
+`), 0o600))
+
+	h := hclCreate{resourceNames: ResourceToGeneratedName{
+		"aws_sqs_queue.tfer--default":   {Name: "default", Address: "aws_sqs_queue.default", Workspace: "workspace"},
+		"aws_sqs_queue.tfer--ireland":   {Name: "ireland", Address: "aws_sqs_queue.ireland", Workspace: "workspace"},
+		"aws_sqs_queue.tfer--sydney":    {Name: "sydney", Address: "aws_sqs_queue.sydney", Workspace: "workspace"},
+		"aws_iam_role.tfer--global":     {Name: "global", Address: "aws_iam_role.global", Workspace: "workspace"},
+		"aws_sqs_queue.tfer--in_module": {Name: "in_module", Address: "module.queue.aws_sqs_queue.this", Workspace: "workspace"},
+	}}
+
+	// When
+	err = h.assignProviderAliases(map[string]string{
+		"aws_sqs_queue.tfer--default":   "us-east-1",
+		"aws_sqs_queue.tfer--ireland":   "eu-west-1",
+		"aws_sqs_queue.tfer--sydney":    "ap-southeast-2",
+		"aws_iam_role.tfer--global":     "",
+		"aws_sqs_queue.tfer--in_module": "ap-southeast-2",
+	}, map[string]string{"workspace": "/workspace/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "", h.resourceNames["aws_sqs_queue.tfer--default"].Provider)
+	assert.Equal(t, "aws.ireland", h.resourceNames["aws_sqs_queue.tfer--ireland"].Provider)
+	assert.Equal(t, "aws.ap_southeast_2_2", h.resourceNames["aws_sqs_queue.tfer--sydney"].Provider)
+	assert.Equal(t, "", h.resourceNames["aws_iam_role.tfer--global"].Provider)
+	assert.Equal(t, map[string]map[string]string{"workspace": {"ap_southeast_2_2": "ap-southeast-2"}}, h.workspaceToNewProviders)
+
+	importFile := hclwrite.NewEmptyFile()
+	h.hclImportBlock(importFile.Body(), ImportDataPair{
+		TerraformConfigLocation: "aws_sqs_queue.sydney",
+		RemoteCloudReference:    "https://sqs.ap-southeast-2.amazonaws.com/123456789012/sydney",
+	}, h.resourceImportProvider("aws_sqs_queue.tfer--sydney"))
+	assert.Equal(t, `import {
+  to       = "aws_sqs_queue.sydney"
+  id       = "https://sqs.ap-southeast-2.amazonaws.com/123456789012/sydney"
+  provider = aws.ap_southeast_2_2
+}
+`, string(hclwrite.Format(importFile.Bytes())))
+	assert.Equal(t, "", h.resourceImportProvider("aws_sqs_queue.tfer--in_module"))
+
+	providersFile := hclwrite.NewEmptyFile()
+	h.appendProviderAliases(WorkspaceToHCL{"workspace": providersFile})
+	assert.Equal(t, `provider "aws" {
+  alias  = "ap_southeast_2_2"
+  region = "ap-southeast-2"
+}
+
+`, string(hclwrite.Format(providersFile.Bytes())))
+}
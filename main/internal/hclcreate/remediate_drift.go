@@ -0,0 +1,340 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+const (
+	// ReportDriftRemediation only reports drift in managed resources.
+	ReportDriftRemediation = "report"
+
+	// AcceptCloudStateRemediation rewrites drifted attributes within configuration to match the cloud.
+	AcceptCloudStateRemediation = "accept-cloud-state"
+
+	// driftRemediationPath is the output file recording the outcome of remediating each drifted attribute.
+	driftRemediationPath = "outputs/drift-remediation.json"
+)
+
+// DriftRemediation is the outcome of accepting the cloud value of a single drifted attribute.
+type DriftRemediation struct {
+	StateFileName string
+	ModuleName    string
+	ResourceType  string
+	ResourceName  string
+	AttributeName string
+	CloudValue    string
+
+	// File is the configuration file containing the resource block, relative to the repository root.
+	File string
+
+	// Remediated is true when the configuration was rewritten to the cloud value.
+	Remediated bool
+
+	// Note explains why the attribute was not remediated.
+	Note string
+}
+
+// configuredResource is a resource block within a workspace's configuration files.
+type configuredResource struct {
+	// path is the path of the file containing the block.
+	path string
+
+	// block is the resource block.
+	block *hclwrite.Block
+}
+
+// driftedExpression is an attribute set by a variable or expression.
+type driftedExpression struct {
+	// body is the body containing the attribute.
+	body *hclwrite.Body
+
+	// name is the name of the attribute.
+	name string
+}
+
+// RemediateDrift rewrites drifted attributes of managed resources to their cloud values when accepting the
+// cloud state. Attributes set by variables or expressions are left unchanged and annotated with the cloud value.
+func (h *hclCreate) RemediateDrift(workspaceToDirectory map[string]string) error {
+	if h.config.DriftRemediation != AcceptCloudStateRemediation {
+		return nil
+	}
+
+	differencesBytes, err := os.ReadFile("outputs/drift-resources-differences.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("[os.ReadFile] outputs/drift-resources-differences.json error: %v", err)
+	}
+
+	var differences []driftDetector.AttributeDifference
+	err = json.Unmarshal(differencesBytes, &differences)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `differences`: %v", err)
+	}
+
+	sort.SliceStable(differences, func(i, j int) bool {
+		return driftDifferenceKey(differences[i]) < driftDifferenceKey(differences[j])
+	})
+
+	remediations := []DriftRemediation{}
+	pathToFile := map[string]*hclwrite.File{}
+	workspaceToResources := map[string]map[string]configuredResource{}
+	modifiedPaths := map[string]bool{}
+	expressionToNotes := map[driftedExpression][]string{}
+
+	for _, difference := range differences {
+		workspace := string(difference.StateFileName)
+		remediation := DriftRemediation{
+			StateFileName: workspace,
+			ModuleName:    difference.ModuleName,
+			ResourceType:  difference.ResourceType,
+			ResourceName:  difference.ResourceName,
+			AttributeName: difference.AttributeName,
+			CloudValue:    difference.CloudValue,
+		}
+
+		directory, ok := workspaceToDirectory[workspace]
+		if !ok {
+			remediation.Note = "state file is not associated with a workspace directory"
+			remediations = append(remediations, remediation)
+			continue
+		}
+
+		if difference.ModuleName != "" {
+			remediation.Note = fmt.Sprintf("resource is defined within %v; update the module's inputs to accept the cloud value", difference.ModuleName)
+			remediations = append(remediations, remediation)
+			continue
+		}
+
+		resources, ok := workspaceToResources[workspace]
+		if !ok {
+			resources, err = loadConfiguredResources(fmt.Sprintf("repo%v", directory), pathToFile)
+			if err != nil {
+				return fmt.Errorf("[loadConfiguredResources]%v", err)
+			}
+			workspaceToResources[workspace] = resources
+		}
+
+		resource, ok := resources[fmt.Sprintf("%v.%v", difference.ResourceType, difference.ResourceName)]
+		if !ok {
+			remediation.Note = fmt.Sprintf("resource block not found within %v", directory)
+			remediations = append(remediations, remediation)
+			continue
+		}
+		remediation.File = strings.TrimPrefix(resource.path, "repo")
+
+		if resource.block.Body().GetAttribute("count") != nil || resource.block.Body().GetAttribute("for_each") != nil {
+			remediation.Note = "resource is defined with count or for_each, so its instances share configuration"
+			remediations = append(remediations, remediation)
+			continue
+		}
+
+		expression, err := acceptCloudValue(resource.block.Body(), strings.Split(difference.AttributeName, "."), difference.CloudValue)
+		switch {
+		case err != nil:
+			remediation.Note = err.Error()
+		case expression != nil:
+			remediation.Note = "attribute is set by a variable or expression and was left unchanged"
+			expressionToNotes[*expression] = append(
+				expressionToNotes[*expression],
+				fmt.Sprintf("%v is %q in the cloud", difference.AttributeName, difference.CloudValue),
+			)
+			modifiedPaths[resource.path] = true
+		default:
+			remediation.Remediated = true
+			modifiedPaths[resource.path] = true
+		}
+		remediations = append(remediations, remediation)
+	}
+
+	for expression, notes := range expressionToNotes {
+		annotateDriftedExpression(expression, notes)
+	}
+
+	for path := range modifiedPaths {
+		err = os.WriteFile(path, hclwrite.Format(pathToFile[path].Bytes()), 0o400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile %v]%v", path, err)
+		}
+	}
+
+	remediationsJSON, err := json.MarshalIndent(remediations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%v", err)
+	}
+
+	return os.WriteFile(driftRemediationPath, remediationsJSON, 0o400)
+}
+
+// driftDifferenceKey returns a key ordering differences by workspace, resource and attribute.
+func driftDifferenceKey(difference driftDetector.AttributeDifference) string {
+	return strings.Join([]string{
+		string(difference.StateFileName), difference.ModuleName, difference.ResourceType,
+		difference.ResourceName, difference.AttributeName,
+	}, "\x00")
+}
+
+// loadConfiguredResources parses the .tf files of a directory, returning a map between resource addresses and
+// their blocks. Parsed files are recorded within pathToFile.
+func loadConfiguredResources(directory string, pathToFile map[string]*hclwrite.File) (map[string]configuredResource, error) {
+	configFiles, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	resources := map[string]configuredResource{}
+	for _, configFile := range configFiles {
+		hclFile, err := parseHCLFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		pathToFile[configFile] = hclFile
+
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() == "resource" && len(block.Labels()) == 2 {
+				resources[strings.Join(block.Labels(), ".")] = configuredResource{path: configFile, block: block}
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// acceptCloudValue sets the attribute at the flattened path within body to the cloud value. When the attribute is
+// set by a variable or expression it is left unchanged and returned. An error explains why the value could not be set.
+func acceptCloudValue(body *hclwrite.Body, path []string, cloudValue string) (*driftedExpression, error) {
+	if attribute := body.GetAttribute(path[0]); attribute != nil {
+		value, ok := literalValue(attribute)
+		if !ok {
+			return &driftedExpression{body: body, name: path[0]}, nil
+		}
+
+		newValue, err := replaceFlatPath(value, path[1:], cloudValue)
+		if err != nil {
+			return nil, err
+		}
+
+		body.SetAttributeRaw(path[0], hclwrite.TokensForValue(newValue))
+		return nil, nil
+	}
+
+	var nestedBlocks []*hclwrite.Block
+	for _, block := range body.Blocks() {
+		if block.Type() == path[0] {
+			nestedBlocks = append(nestedBlocks, block)
+		}
+	}
+
+	if len(nestedBlocks) > 0 && len(path) > 2 {
+		index, err := strconv.Atoi(path[1])
+		if err == nil && index < len(nestedBlocks) {
+			return acceptCloudValue(nestedBlocks[index].Body(), path[2:], cloudValue)
+		}
+	}
+
+	return nil, fmt.Errorf("%v is not set within configuration, so the provider default or a computed value applies", path[0])
+}
+
+// replaceFlatPath returns value with the element at the flattened path replaced by the cloud value, converted to
+// the type of the existing element. Map keys absent from the cloud are removed.
+func replaceFlatPath(value cty.Value, path []string, cloudValue string) (cty.Value, error) {
+	if len(path) == 0 {
+		return convertCloudValue(value.Type(), cloudValue)
+	}
+
+	switch {
+	case value.Type().IsObjectType() || value.Type().IsMapType():
+		elements := value.AsValueMap()
+		if elements == nil {
+			elements = map[string]cty.Value{}
+		}
+
+		element, ok := elements[path[0]]
+		switch {
+		case len(path) == 1 && cloudValue == "":
+			delete(elements, path[0])
+		case !ok && len(path) == 1:
+			elements[path[0]] = cty.StringVal(cloudValue)
+		case !ok:
+			return cty.NilVal, fmt.Errorf("%v is not set within configuration", path[0])
+		default:
+			newElement, err := replaceFlatPath(element, path[1:], cloudValue)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elements[path[0]] = newElement
+		}
+
+		if len(elements) == 0 {
+			return cty.EmptyObjectVal, nil
+		}
+		return cty.ObjectVal(elements), nil
+
+	case value.Type().IsTupleType() || value.Type().IsListType() || value.Type().IsSetType():
+		elements := value.AsValueSlice()
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index >= len(elements) {
+			return cty.NilVal, fmt.Errorf("element %v is not set within configuration", path[0])
+		}
+		if len(path) == 1 && cloudValue == "" {
+			return cty.NilVal, fmt.Errorf("element %v was removed in the cloud and must be removed manually", path[0])
+		}
+
+		elements[index], err = replaceFlatPath(elements[index], path[1:], cloudValue)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		return cty.TupleVal(elements), nil
+	}
+
+	return cty.NilVal, fmt.Errorf("cannot set %v within a %v value", strings.Join(path, "."), value.Type().FriendlyName())
+}
+
+// convertCloudValue converts a flattened cloud value to the given primitive type.
+func convertCloudValue(valueType cty.Type, cloudValue string) (cty.Value, error) {
+	switch valueType {
+	case cty.Number:
+		value, err := cty.ParseNumberVal(cloudValue)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("cloud value %q is not a number", cloudValue)
+		}
+		return value, nil
+	case cty.Bool:
+		value, err := strconv.ParseBool(cloudValue)
+		if err != nil {
+			return cty.NilVal, fmt.Errorf("cloud value %q is not a boolean", cloudValue)
+		}
+		return cty.BoolVal(value), nil
+	case cty.String:
+		return cty.StringVal(cloudValue), nil
+	}
+
+	return cty.NilVal, fmt.Errorf("cannot set a %v value to %q", valueType.FriendlyName(), cloudValue)
+}
+
+// annotateDriftedExpression appends a comment to an attribute set by a variable or expression, recording the cloud
+// values it should be changed to.
+func annotateDriftedExpression(expression driftedExpression, notes []string) {
+	sort.Strings(notes)
+	expressionTokens := expression.body.GetAttribute(expression.name).Expr().BuildTokens(nil)
+	expressionTokens = append(expressionTokens, &hclwrite.Token{
+		Type:         hclsyntax.TokenComment,
+		Bytes:        []byte(fmt.Sprintf("# drift: %v; set by a variable or expression, so left unchanged", strings.Join(notes, ", "))),
+		SpacesBefore: 1,
+	})
+
+	expression.body.SetAttributeRaw(expression.name, expressionTokens)
+}
@@ -0,0 +1,133 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+func TestRemediateDrift(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	directory := filepath.Join("repo", "workspace")
+	require.NoError(t, os.MkdirAll(directory, 0o700))
+	require.NoError(t, os.MkdirAll("outputs", 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "main.tf"), []byte(`resource "aws_s3_bucket" "logs" {
+  bucket        = "logs"
+  force_destroy = false
+  tags = {
+    Team = "platform"
+    Env  = "prod"
+  }
+
+  lifecycle_rule {
+    enabled = true
+    expiration {
+      days = 30
+    }
+  }
+}
+
+resource "aws_sqs_queue" "jobs" {
+  delay_seconds = var.delay_seconds
+}
+`), 0o600))
+
+	differences := []driftDetector.AttributeDifference{
+		{AttributeName: "force_destroy", CloudValue: "true"},
+		{AttributeName: "tags.Env", CloudValue: ""},
+		{AttributeName: "tags.Owner", CloudValue: "ops"},
+		{AttributeName: "lifecycle_rule.0.expiration.0.days", CloudValue: "90"},
+		{AttributeName: "acl", CloudValue: "private"},
+	}
+	for i := range differences {
+		differences[i].AttributeDetail = driftDetector.AttributeDetail{StateFileName: "workspace", ResourceType: "aws_s3_bucket", ResourceName: "logs"}
+	}
+	differences = append(differences,
+		driftDetector.AttributeDifference{
+			AttributeName:   "delay_seconds",
+			CloudValue:      "10",
+			AttributeDetail: driftDetector.AttributeDetail{StateFileName: "workspace", ResourceType: "aws_sqs_queue", ResourceName: "jobs"},
+		},
+		driftDetector.AttributeDifference{
+			AttributeName:   "delay_seconds",
+			CloudValue:      "10",
+			AttributeDetail: driftDetector.AttributeDetail{StateFileName: "workspace", ModuleName: "module.queue", ResourceType: "aws_sqs_queue", ResourceName: "this"},
+		},
+	)
+	differencesJSON, err := json.Marshal(differences)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("outputs/drift-resources-differences.json", differencesJSON, 0o600))
+
+	h := hclCreate{config: Config{DriftRemediation: AcceptCloudStateRemediation}}
+
+	// When
+	err = h.RemediateDrift(map[string]string{"workspace": "/workspace/"})
+
+	// Then
+	require.NoError(t, err)
+
+	expected := `resource "aws_s3_bucket" "logs" {
+  bucket        = "logs"
+  force_destroy = true
+  tags = {
+    Owner = "ops"
+    Team  = "platform"
+  }
+
+  lifecycle_rule {
+    enabled = true
+    expiration {
+      days = 90
+    }
+  }
+}
+
+resource "aws_sqs_queue" "jobs" {
+  delay_seconds = var.delay_seconds # drift: delay_seconds is "10" in the cloud; set by a variable or expression, so left unchanged
+}
+`
+	configuration, err := os.ReadFile(filepath.Join(directory, "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(configuration))
+
+	remediationsJSON, err := os.ReadFile(driftRemediationPath)
+	require.NoError(t, err)
+	var remediations []DriftRemediation
+	require.NoError(t, json.Unmarshal(remediationsJSON, &remediations))
+
+	attributeToRemediated := map[string]bool{}
+	for _, remediation := range remediations {
+		attributeToRemediated[remediation.ModuleName+remediation.ResourceName+"."+remediation.AttributeName] = remediation.Remediated
+	}
+	assert.Equal(t, map[string]bool{
+		"logs.force_destroy":                      true,
+		"logs.tags.Env":                           true,
+		"logs.tags.Owner":                         true,
+		"logs.lifecycle_rule.0.expiration.0.days": true,
+		"logs.acl":                                false,
+		"jobs.delay_seconds":                      false,
+		"module.queuethis.delay_seconds":          false,
+	}, attributeToRemediated)
+}
+
+func TestRemediateDrift_ReportOnly(t *testing.T) {
+	// Given
+	h := hclCreate{config: Config{DriftRemediation: ReportDriftRemediation}}
+
+	// When
+	err := h.RemediateDrift(map[string]string{"workspace": "/workspace/"})
+
+	// Then
+	assert.NoError(t, err)
+}
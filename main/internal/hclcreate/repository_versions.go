@@ -0,0 +1,334 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// repositoryVersionsPath is the output file recording the Terraform and provider versions inferred from the repository.
+const repositoryVersionsPath = "outputs/repository-versions.json"
+
+// WorkspaceVersionPins are the Terraform and provider versions pinned within a workspace's configuration.
+type WorkspaceVersionPins struct {
+	// RequiredVersion is the workspace's required_version constraint.
+	RequiredVersion string
+
+	// RequiredProviders is a map between provider names and their required_providers version constraint.
+	RequiredProviders map[string]string
+
+	// LockedProviders is a map between provider names and their version within .terraform.lock.hcl.
+	LockedProviders map[string]string
+}
+
+// VersionMismatch is a Terraform or provider version that is pinned inconsistently across workspaces, or which
+// disagrees with the configured version.
+type VersionMismatch struct {
+	// Name is either "terraform" or the name of the provider.
+	Name string
+
+	// Selected is the version used by cloud-concierge.
+	Selected string
+
+	// WorkspaceToPin is a map between workspace names and the version pinned within that workspace.
+	WorkspaceToPin map[string]string
+
+	// Reason describes the mismatch.
+	Reason string
+}
+
+// RepositoryVersions are the Terraform and provider versions used by cloud-concierge, inferred from the pins of
+// each workspace within the repository.
+type RepositoryVersions struct {
+	// TerraformVersion is the Terraform version installed.
+	TerraformVersion string
+
+	// Providers is a map between provider names and the version constraint used.
+	Providers map[string]string
+
+	// Workspaces is a map between workspace names and their version pins.
+	Workspaces map[string]WorkspaceVersionPins
+
+	// Mismatches are the versions pinned inconsistently.
+	Mismatches []VersionMismatch
+}
+
+// LoadWorkspaceVersionPins reads the required_version, required_providers and .terraform.lock.hcl pins of the
+// workspace within directory.
+func LoadWorkspaceVersionPins(directory string) (WorkspaceVersionPins, error) {
+	pins := WorkspaceVersionPins{RequiredProviders: map[string]string{}, LockedProviders: map[string]string{}}
+
+	configFiles, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return pins, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	requiredVersions := []string{}
+	for _, configFile := range configFiles {
+		hclFile, err := parseHCLFile(configFile)
+		if err != nil {
+			return pins, err
+		}
+
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() != "terraform" {
+				continue
+			}
+
+			if requiredVersion, ok := stringAttributeValue(block.Body(), "required_version"); ok {
+				requiredVersions = append(requiredVersions, requiredVersion)
+			}
+
+			for _, requiredProviders := range block.Body().Blocks() {
+				if requiredProviders.Type() != "required_providers" {
+					continue
+				}
+
+				for name, attribute := range requiredProviders.Body().Attributes() {
+					value, ok := literalValue(attribute)
+					switch {
+					case !ok:
+					case value.Type() == cty.String:
+						pins.RequiredProviders[name] = value.AsString()
+					case value.Type().IsObjectType() && value.Type().HasAttribute("version"):
+						if version := value.GetAttr("version"); version.Type() == cty.String {
+							pins.RequiredProviders[name] = version.AsString()
+						}
+					}
+				}
+			}
+		}
+	}
+	pins.RequiredVersion = strings.Join(requiredVersions, ", ")
+
+	lockFile, err := parseHCLFile(filepath.Join(directory, ".terraform.lock.hcl"))
+	if errors.Is(err, os.ErrNotExist) {
+		return pins, nil
+	} else if err != nil {
+		return pins, err
+	}
+
+	for _, block := range lockFile.Body().Blocks() {
+		if block.Type() != "provider" || len(block.Labels()) != 1 {
+			continue
+		}
+
+		if version, ok := stringAttributeValue(block.Body(), "version"); ok {
+			sourceParts := strings.Split(block.Labels()[0], "/")
+			pins.LockedProviders[sourceParts[len(sourceParts)-1]] = version
+		}
+	}
+
+	return pins, nil
+}
+
+// ResolveRepositoryVersions infers the Terraform version and the version of each configured provider from the pins
+// of each workspace. Configured versions are used for anything the repository does not pin, and pins that disagree
+// across workspaces or with the configured versions are recorded as mismatches.
+func ResolveRepositoryVersions(
+	workspaceToDirectory map[string]string,
+	configuredTerraformVersion string,
+	configuredProviders map[string]string,
+) (RepositoryVersions, error) {
+	versions := RepositoryVersions{Providers: map[string]string{}, Workspaces: map[string]WorkspaceVersionPins{}}
+	for workspace, directory := range workspaceToDirectory {
+		pins, err := LoadWorkspaceVersionPins(fmt.Sprintf("repo%v", directory))
+		if err != nil {
+			return versions, fmt.Errorf("[LoadWorkspaceVersionPins %v]%v", directory, err)
+		}
+		versions.Workspaces[workspace] = pins
+	}
+
+	err := versions.resolveTerraformVersion(configuredTerraformVersion)
+	if err != nil {
+		return versions, err
+	}
+
+	for _, provider := range sortedKeys(configuredProviders) {
+		versions.resolveProviderVersion(provider, configuredProviders[provider])
+	}
+
+	return versions, nil
+}
+
+// resolveTerraformVersion selects the lowest Terraform version satisfying the configured version and every
+// workspace's required_version.
+func (r *RepositoryVersions) resolveTerraformVersion(configured string) error {
+	workspaceToPin := map[string]string{}
+	required := TerraformVersionConstraints{}
+	for workspace, pins := range r.Workspaces {
+		if pins.RequiredVersion == "" {
+			continue
+		}
+
+		constraints, err := ParseTerraformVersionConstraints(pins.RequiredVersion)
+		if err != nil {
+			return fmt.Errorf("[ParseTerraformVersionConstraints %v]%v", workspace, err)
+		}
+		required = append(required, constraints...)
+		workspaceToPin[workspace] = pins.RequiredVersion
+	}
+
+	if configured == "" && len(required) == 0 {
+		return fmt.Errorf("terraform version could not be inferred from the repository and is not configured")
+	}
+
+	configuredConstraints := TerraformVersionConstraints{}
+	if configured != "" {
+		var err error
+		configuredConstraints, err = ParseTerraformVersionConstraints(configured)
+		if err != nil {
+			return fmt.Errorf("[ParseTerraformVersionConstraints]%v", err)
+		}
+	}
+
+	if version, ok := append(required, configuredConstraints...).LowestVersion(); ok {
+		r.TerraformVersion = version.String()
+		return nil
+	}
+
+	mismatch := VersionMismatch{Name: "terraform", WorkspaceToPin: workspaceToPin}
+	if version, ok := required.LowestVersion(); ok {
+		mismatch.Reason = fmt.Sprintf("the configured version %v does not satisfy the required_version of every workspace", configured)
+		r.TerraformVersion = version.String()
+	} else {
+		mismatch.Reason = "no single version satisfies the required_version of every workspace"
+		r.TerraformVersion = highestLowestVersion(workspaceToPin).String()
+		if version, ok := configuredConstraints.LowestVersion(); ok && configured != "" {
+			r.TerraformVersion = version.String()
+		}
+	}
+
+	mismatch.Selected = r.TerraformVersion
+	r.Mismatches = append(r.Mismatches, mismatch)
+	return nil
+}
+
+// highestLowestVersion returns the highest of the lowest versions allowed by each constraint.
+func highestLowestVersion(workspaceToPin map[string]string) TerraformVersion {
+	highest := TerraformVersion{}
+	for _, pin := range workspaceToPin {
+		constraints, _ := ParseTerraformVersionConstraints(pin)
+		if version, ok := constraints.LowestVersion(); ok && version.Compare(highest) > 0 {
+			highest = version
+		}
+	}
+	return highest
+}
+
+// resolveProviderVersion selects the version of a provider, preferring the version locked within
+// .terraform.lock.hcl, then the required_providers constraints, and lastly the configured version.
+func (r *RepositoryVersions) resolveProviderVersion(provider string, configured string) {
+	workspaceToLocked := map[string]string{}
+	workspaceToRequired := map[string]string{}
+	for workspace, pins := range r.Workspaces {
+		if locked, ok := pins.LockedProviders[provider]; ok {
+			workspaceToLocked[workspace] = locked
+		}
+		if required, ok := pins.RequiredProviders[provider]; ok {
+			workspaceToRequired[workspace] = required
+		}
+	}
+
+	lockedVersions := map[string]bool{}
+	for _, locked := range workspaceToLocked {
+		lockedVersions[locked] = true
+	}
+
+	switch {
+	case len(lockedVersions) > 0:
+		r.Providers[provider] = highestLowestVersion(workspaceToLocked).String()
+		if len(lockedVersions) > 1 {
+			r.Mismatches = append(r.Mismatches, VersionMismatch{
+				Name:           provider,
+				Selected:       r.Providers[provider],
+				WorkspaceToPin: workspaceToLocked,
+				Reason:         "workspaces lock different provider versions within .terraform.lock.hcl",
+			})
+			return
+		}
+	case len(workspaceToRequired) > 0:
+		requiredConstraints := []string{}
+		for _, workspace := range sortedKeys(workspaceToRequired) {
+			requiredConstraints = append(requiredConstraints, workspaceToRequired[workspace])
+		}
+		r.Providers[provider] = strings.Join(requiredConstraints, ", ")
+
+		constraints, err := ParseTerraformVersionConstraints(r.Providers[provider])
+		if _, ok := constraints.LowestVersion(); err != nil || !ok {
+			r.Providers[provider] = workspaceToRequired[sortedKeys(workspaceToRequired)[0]]
+			r.Mismatches = append(r.Mismatches, VersionMismatch{
+				Name:           provider,
+				Selected:       r.Providers[provider],
+				WorkspaceToPin: workspaceToRequired,
+				Reason:         "no single provider version satisfies the required_providers of every workspace",
+			})
+			return
+		}
+	default:
+		r.Providers[provider] = configured
+		return
+	}
+
+	if configured == "" {
+		return
+	}
+
+	configuredConstraints, err := ParseTerraformVersionConstraints(configured)
+	selectedConstraints, selectedErr := ParseTerraformVersionConstraints(r.Providers[provider])
+	selected, ok := selectedConstraints.LowestVersion()
+	if err == nil && selectedErr == nil && ok && configuredConstraints.Check(selected) {
+		return
+	}
+
+	workspaceToPin := workspaceToLocked
+	if len(workspaceToPin) == 0 {
+		workspaceToPin = workspaceToRequired
+	}
+
+	r.Mismatches = append(r.Mismatches, VersionMismatch{
+		Name:           provider,
+		Selected:       r.Providers[provider],
+		WorkspaceToPin: workspaceToPin,
+		Reason:         fmt.Sprintf("the repository's pins replace the configured version %v", configured),
+	})
+}
+
+// Write saves the repository versions within the outputs directory.
+func (r RepositoryVersions) Write() error {
+	sort.SliceStable(r.Mismatches, func(i, j int) bool {
+		return r.Mismatches[i].Name < r.Mismatches[j].Name
+	})
+
+	versionsJSON, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%v", err)
+	}
+
+	return os.WriteFile(repositoryVersionsPath, versionsJSON, 0o400)
+}
+
+// loadRepositoryTerraformVersion returns the Terraform version inferred from the repository, or an empty string if
+// versions have not been inferred.
+func loadRepositoryTerraformVersion() (string, error) {
+	versionsBytes, err := os.ReadFile(repositoryVersionsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("[os.ReadFile] %v error: %v", repositoryVersionsPath, err)
+	}
+
+	var versions RepositoryVersions
+	err = json.Unmarshal(versionsBytes, &versions)
+	if err != nil {
+		return "", fmt.Errorf("[json.Unmarshal] error unmarshalling `versions`: %v", err)
+	}
+
+	return versions.TerraformVersion, nil
+}
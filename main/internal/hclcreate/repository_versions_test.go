@@ -0,0 +1,121 @@
+package hclcreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeWorkspacePins(t *testing.T, workspace string, versionsTF string, lockFile string) {
+	directory := filepath.Join("repo", workspace)
+	require.NoError(t, os.MkdirAll(directory, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "versions.tf"), []byte(versionsTF), 0o600))
+	if lockFile != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(directory, ".terraform.lock.hcl"), []byte(lockFile), 0o600))
+	}
+}
+
+func TestResolveRepositoryVersions(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	writeWorkspacePins(t, "prod", `terraform {
+  required_version = ">= 1.5.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+`, `provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+}
+`)
+	writeWorkspacePins(t, "dev", `terraform {
+  required_version = "~> 1.6.0"
+
+  required_providers {
+    aws = "~> 4.0"
+  }
+}
+`, `provider "registry.terraform.io/hashicorp/aws" {
+  version = "4.67.0"
+}
+`)
+
+	// When
+	versions, err := ResolveRepositoryVersions(
+		map[string]string{"prod": "/prod/", "dev": "/dev/"},
+		"",
+		map[string]string{"aws": "~>4.59.0"},
+	)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "1.6.0", versions.TerraformVersion)
+	assert.Equal(t, map[string]string{"aws": "5.31.0"}, versions.Providers)
+	assert.Equal(t, WorkspaceVersionPins{
+		RequiredVersion:   "~> 1.6.0",
+		RequiredProviders: map[string]string{"aws": "~> 4.0"},
+		LockedProviders:   map[string]string{"aws": "4.67.0"},
+	}, versions.Workspaces["dev"])
+	assert.Equal(t, []VersionMismatch{{
+		Name:           "aws",
+		Selected:       "5.31.0",
+		WorkspaceToPin: map[string]string{"prod": "5.31.0", "dev": "4.67.0"},
+		Reason:         "workspaces lock different provider versions within .terraform.lock.hcl",
+	}}, versions.Mismatches)
+}
+
+func TestResolveRepositoryVersions_ConfiguredVersions(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	writeWorkspacePins(t, "prod", `terraform {
+  required_version = "~> 1.7.0"
+}
+`, "")
+
+	// When
+	versions, err := ResolveRepositoryVersions(
+		map[string]string{"prod": "/prod/"},
+		"1.5.0",
+		map[string]string{"google": "~>4.27.0"},
+	)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "1.7.0", versions.TerraformVersion)
+	assert.Equal(t, map[string]string{"google": "~>4.27.0"}, versions.Providers)
+	require.Len(t, versions.Mismatches, 1)
+	assert.Equal(t, "terraform", versions.Mismatches[0].Name)
+	assert.Equal(t, "1.7.0", versions.Mismatches[0].Selected)
+}
+
+func TestResolveRepositoryVersions_NoVersion(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	writeWorkspacePins(t, "prod", "", "")
+
+	// When
+	_, err = ResolveRepositoryVersions(map[string]string{"prod": "/prod/"}, "", map[string]string{"aws": ""})
+
+	// Then
+	assert.NotNil(t, err)
+}
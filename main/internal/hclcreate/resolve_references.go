@@ -0,0 +1,398 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Jeffail/gabs/v2"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// ReferenceTarget is a resource, either already managed by Terraform or being imported within the current run,
+// that a hard-coded id within generated HCL can be rewritten to reference.
+type ReferenceTarget struct {
+	// Workspace is the name of the workspace that manages, or will manage, the resource.
+	Workspace string
+
+	// ResourceType is the Terraform resource type.
+	ResourceType string
+
+	// Address is the resource instance address within the workspace, relative to the root module.
+	Address string
+
+	// InModule is true if the resource is defined within a child module, and so cannot be referenced directly.
+	InModule bool
+}
+
+// IDToReferenceTargets is a map between cloud resource ids and the resources that have that id.
+type IDToReferenceTargets map[string][]ReferenceTarget
+
+// dataSourceLookup defines the data source and lookup argument used to reference a resource managed elsewhere.
+type dataSourceLookup struct {
+	// dataSourceType is the Terraform data source type.
+	dataSourceType string
+
+	// argument is the data source argument that accepts the resource id.
+	argument string
+
+	// filterName is the name of the EC2 filter which matches the resource id, for data sources that can only look
+	// up resources through filter blocks. When set, argument is unused.
+	filterName string
+}
+
+// resourceTypeToDataSourceLookup maps resource types to the data source which looks up that resource by id.
+var resourceTypeToDataSourceLookup = map[string]dataSourceLookup{
+	"aws_ebs_volume":        {dataSourceType: "aws_ebs_volume", filterName: "volume-id"},
+	"aws_eip":               {dataSourceType: "aws_eip", argument: "id"},
+	"aws_iam_policy":        {dataSourceType: "aws_iam_policy", argument: "arn"},
+	"aws_iam_role":          {dataSourceType: "aws_iam_role", argument: "name"},
+	"aws_instance":          {dataSourceType: "aws_instance", argument: "instance_id"},
+	"aws_internet_gateway":  {dataSourceType: "aws_internet_gateway", argument: "internet_gateway_id"},
+	"aws_kms_key":           {dataSourceType: "aws_kms_key", argument: "key_id"},
+	"aws_lb":                {dataSourceType: "aws_lb", argument: "arn"},
+	"aws_nat_gateway":       {dataSourceType: "aws_nat_gateway", argument: "id"},
+	"aws_network_interface": {dataSourceType: "aws_network_interface", argument: "id"},
+	"aws_route_table":       {dataSourceType: "aws_route_table", argument: "route_table_id"},
+	"aws_s3_bucket":         {dataSourceType: "aws_s3_bucket", argument: "bucket"},
+	"aws_security_group":    {dataSourceType: "aws_security_group", argument: "id"},
+	"aws_subnet":            {dataSourceType: "aws_subnet", argument: "id"},
+	"aws_vpc":               {dataSourceType: "aws_vpc", argument: "id"},
+	"aws_vpc_endpoint":      {dataSourceType: "aws_vpc_endpoint", argument: "id"},
+}
+
+// nonReferenceAttributes are `*_id` and `*_ids` attributes whose values identify something other than a resource
+// cloud-concierge can import, such as an AWS account, availability zone or the hosted zone of an alias target, and
+// so are never rewritten into references or annotated. Attributes ending in "account_id" or "account_ids" are
+// also skipped.
+var nonReferenceAttributes = map[string]bool{
+	"ami_id":                   true,
+	"availability_zone_id":     true,
+	"availability_zone_ids":    true,
+	"canonical_hosted_zone_id": true,
+	"client_id":                true,
+	"hosted_zone_id":           true,
+	"image_id":                 true,
+	"owner_id":                 true,
+	"project_id":               true,
+	"subscription_id":          true,
+	"tenant_id":                true,
+}
+
+// nonReferenceBlocks are nested blocks whose `*_id` attributes identify something other than a resource
+// cloud-concierge can import, such as the hosted zone of a Route 53 alias target.
+var nonReferenceBlocks = map[string]bool{
+	"alias": true,
+}
+
+// invalidNameCharacters matches characters which are not valid within a Terraform block name.
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// loadReferenceTargets indexes, by cloud id, the new resources being written within the current run as well as
+// the resources already managed within each workspace's state file.
+func (h *hclCreate) loadReferenceTargets(
+	parsedNewResourceToWorkspace *gabs.Container,
+	workspaceToDirectory map[string]string,
+) (IDToReferenceTargets, error) {
+	idToTargets := IDToReferenceTargets{}
+
+	terraformerStateBytes, err := os.ReadFile("current_cloud/terraform.tfstate")
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile current_cloud/terraform.tfstate]%v", err)
+	}
+
+	terraformerState, err := driftDetector.ParseTerraformerStateFile(terraformerStateBytes)
+	if err != nil {
+		return nil, fmt.Errorf("[driftDetector.ParseTerraformerStateFile]%v", err)
+	}
+
+	newResourceToWorkspace := map[string]string{}
+	for resource, workspaceName := range parsedNewResourceToWorkspace.ChildrenMap() {
+		newResourceToWorkspace[resource] = workspaceName.Data().(string)
+	}
+
+	for _, resource := range terraformerState.Resources {
+		workspace, ok := newResourceToWorkspace[fmt.Sprintf("%v.%v", resource.Type, resource.Name)]
+		if !ok || len(resource.Instances) == 0 {
+			continue
+		}
+
+		id := resource.Instances[0].AttributesFlat["id"]
+		if id == "" {
+			continue
+		}
+
+		address := h.resourceAddress(ResourceIdentifier{resourceType: resource.Type, resourceName: resource.Name})
+		idToTargets[id] = append(idToTargets[id], ReferenceTarget{
+			Workspace:    workspace,
+			ResourceType: resource.Type,
+			Address:      address,
+			InModule:     strings.HasPrefix(address, "module."),
+		})
+	}
+
+	for workspace := range workspaceToDirectory {
+		stateBytes, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", workspace))
+		if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile state_files/%v.json]%v", workspace, err)
+		}
+
+		var stateFile driftDetector.TerraformStateFile
+		err = json.Unmarshal(stateBytes, &stateFile)
+		if err != nil {
+			return nil, fmt.Errorf("[json.Unmarshal state_files/%v.json]%v", workspace, err)
+		}
+
+		addStateReferenceTargets(idToTargets, workspace, stateFile)
+	}
+
+	return idToTargets, nil
+}
+
+// addStateReferenceTargets adds the managed resources within a workspace's state file to idToTargets.
+func addStateReferenceTargets(idToTargets IDToReferenceTargets, workspace string, stateFile driftDetector.TerraformStateFile) {
+	for _, resource := range stateFile.Resources {
+		if resource.Mode != "managed" {
+			continue
+		}
+
+		for _, instance := range resource.Instances {
+			id, ok := instance.Attributes["id"].(string)
+			if !ok || id == "" {
+				continue
+			}
+
+			address := fmt.Sprintf("%v.%v", resource.Type, resource.Name)
+			switch key := instance.IndexKey.(type) {
+			case float64:
+				address = fmt.Sprintf("%v[%d]", address, int(key))
+			case string:
+				address = fmt.Sprintf("%v[%q]", address, key)
+			}
+
+			idToTargets[id] = append(idToTargets[id], ReferenceTarget{
+				Workspace:    workspace,
+				ResourceType: resource.Type,
+				Address:      address,
+				InModule:     resource.Module != "",
+			})
+		}
+	}
+}
+
+// resolveReferences rewrites hard-coded ids within `*_id` and `*_ids` attributes of the block, other than
+// nonReferenceAttributes, into references.
+// Resources written to the same workspace are referenced directly, while resources managed elsewhere are
+// referenced through a data source, which is returned for inclusion within the workspace's configuration.
+// Ids that cannot be resolved are left as literals with a comment explaining why.
+func (h *hclCreate) resolveReferences(block *hclwrite.Block, workspace string, idToTargets IDToReferenceTargets) []*hclwrite.Block {
+	selfAddress := strings.Join(block.Labels(), ".")
+	return resolveBodyReferences(block.Body(), workspace, selfAddress, idToTargets)
+}
+
+// resolveBodyReferences rewrites hard-coded ids within the body and any nested blocks.
+func resolveBodyReferences(body *hclwrite.Body, workspace string, selfAddress string, idToTargets IDToReferenceTargets) []*hclwrite.Block {
+	dataSources := make([]*hclwrite.Block, 0)
+
+	attributeNames := make([]string, 0)
+	for name := range body.Attributes() {
+		if nonReferenceAttributes[name] || strings.HasSuffix(name, "account_id") || strings.HasSuffix(name, "account_ids") {
+			continue
+		}
+		if strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_ids") {
+			attributeNames = append(attributeNames, name)
+		}
+	}
+	sort.Strings(attributeNames)
+
+	for _, name := range attributeNames {
+		value, ok := literalAttributeValue(body.GetAttribute(name))
+		if !ok {
+			continue
+		}
+
+		var elements []cty.Value
+		if value.Type() == cty.String {
+			elements = []cty.Value{value}
+		} else {
+			elements = value.AsValueSlice()
+		}
+
+		elementTokens := make([]hclwrite.Tokens, 0, len(elements))
+		unresolvedReasons := make([]string, 0)
+		resolvedAny := false
+
+		for _, element := range elements {
+			if element.Type() != cty.String || element.AsString() == "" {
+				elementTokens = append(elementTokens, hclwrite.TokensForValue(element))
+				continue
+			}
+
+			tokens, dataSource, reason := resolveID(name, element.AsString(), workspace, selfAddress, idToTargets)
+			if reason != "" {
+				unresolvedReasons = append(unresolvedReasons, reason)
+				elementTokens = append(elementTokens, hclwrite.TokensForValue(element))
+				continue
+			}
+
+			resolvedAny = true
+			elementTokens = append(elementTokens, tokens)
+			if dataSource != nil {
+				dataSources = append(dataSources, dataSource)
+			}
+		}
+
+		var expressionTokens hclwrite.Tokens
+		if value.Type() == cty.String {
+			expressionTokens = elementTokens[0]
+		} else {
+			expressionTokens = hclwrite.TokensForTuple(elementTokens)
+		}
+
+		if len(unresolvedReasons) > 0 {
+			expressionTokens = append(expressionTokens, &hclwrite.Token{
+				Type:         hclsyntax.TokenComment,
+				Bytes:        []byte(fmt.Sprintf("# %v", strings.Join(unresolvedReasons, "; "))),
+				SpacesBefore: 1,
+			})
+		}
+
+		if resolvedAny || len(unresolvedReasons) > 0 {
+			body.SetAttributeRaw(name, expressionTokens)
+		}
+	}
+
+	for _, nestedBlock := range body.Blocks() {
+		if nonReferenceBlocks[nestedBlock.Type()] {
+			continue
+		}
+		dataSources = append(dataSources, resolveBodyReferences(nestedBlock.Body(), workspace, selfAddress, idToTargets)...)
+	}
+
+	return dataSources
+}
+
+// literalValue returns the value of an attribute whose expression can be evaluated without any references.
+func literalValue(attribute *hclwrite.Attribute) (cty.Value, bool) {
+	expressionBytes := attribute.Expr().BuildTokens(nil).Bytes()
+	expression, diagnostics := hclsyntax.ParseExpression(expressionBytes, "", hcl.Pos{Line: 1, Column: 1})
+	if diagnostics.HasErrors() {
+		return cty.NilVal, false
+	}
+
+	value, diagnostics := expression.Value(nil)
+	if diagnostics.HasErrors() || value.IsNull() || !value.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+
+	return value, true
+}
+
+// literalAttributeValue returns the value of an attribute whose expression is a string literal or a tuple of
+// string literals.
+func literalAttributeValue(attribute *hclwrite.Attribute) (cty.Value, bool) {
+	value, ok := literalValue(attribute)
+	if !ok {
+		return cty.NilVal, false
+	}
+
+	if value.Type() == cty.String {
+		return value, true
+	}
+	if value.Type().IsTupleType() || value.Type().IsListType() {
+		return value, value.LengthInt() > 0
+	}
+	return cty.NilVal, false
+}
+
+// resolveID returns the tokens referencing the resource with the specified id, along with a data source block if
+// one is needed. If the id cannot be resolved, a reason is returned instead.
+func resolveID(
+	attributeName string, id string, workspace string, selfAddress string, idToTargets IDToReferenceTargets,
+) (hclwrite.Tokens, *hclwrite.Block, string) {
+	targets := matchingTargets(attributeName, idToTargets[id])
+	if len(targets) == 0 {
+		return nil, nil, fmt.Sprintf("%q is not managed by Terraform or imported within this run", id)
+	}
+	if len(targets) > 1 {
+		return nil, nil, fmt.Sprintf("%q matches more than one resource", id)
+	}
+
+	target := targets[0]
+	if target.Workspace == workspace && !target.InModule {
+		if target.Address == selfAddress {
+			return nil, nil, fmt.Sprintf("%q refers to this resource", id)
+		}
+		return referenceTokens(target.Address, "id"), nil, ""
+	}
+
+	lookup, ok := resourceTypeToDataSourceLookup[target.ResourceType]
+	if !ok {
+		return nil, nil, fmt.Sprintf(
+			"%q is %v in workspace %v, which has no supported data source lookup", id, target.Address, target.Workspace,
+		)
+	}
+
+	dataSourceName := invalidNameCharacters.ReplaceAllString(fmt.Sprintf("%v_%v", target.Workspace, target.Address), "_")
+	dataSource := hclwrite.NewBlock("data", []string{lookup.dataSourceType, dataSourceName})
+	if lookup.filterName != "" {
+		filter := dataSource.Body().AppendNewBlock("filter", nil)
+		filter.Body().SetAttributeValue("name", cty.StringVal(lookup.filterName))
+		filter.Body().SetAttributeValue("values", cty.ListVal([]cty.Value{cty.StringVal(id)}))
+	} else {
+		dataSource.Body().SetAttributeValue(lookup.argument, cty.StringVal(id))
+	}
+
+	return referenceTokens(fmt.Sprintf("data.%v.%v", lookup.dataSourceType, dataSourceName), "id"), dataSource, ""
+}
+
+// matchingTargets narrows down targets sharing the same id to those whose type matches the attribute name, such as
+// aws_vpc for vpc_id, when more than one resource type has the id.
+func matchingTargets(attributeName string, targets []ReferenceTarget) []ReferenceTarget {
+	if len(targets) <= 1 {
+		return targets
+	}
+
+	attributeSubject := strings.TrimSuffix(strings.TrimSuffix(attributeName, "_ids"), "_id")
+	matching := make([]ReferenceTarget, 0)
+	for _, target := range targets {
+		if strings.HasSuffix(target.ResourceType, attributeSubject) {
+			matching = append(matching, target)
+		}
+	}
+	return matching
+}
+
+// referenceTokens returns the tokens for a reference to the attribute of the resource at the specified address.
+func referenceTokens(address string, attribute string) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fmt.Sprintf("%v.%v", address, attribute))},
+	}
+}
+
+// appendDataSources appends the unique data source blocks to the workspace's HCL file, ordered by data source name.
+func appendDataSources(hclFile *hclwrite.File, dataSources []*hclwrite.Block) {
+	nameToDataSource := map[string]*hclwrite.Block{}
+	for _, dataSource := range dataSources {
+		nameToDataSource[strings.Join(dataSource.Labels(), ".")] = dataSource
+	}
+
+	names := make([]string, 0, len(nameToDataSource))
+	for name := range nameToDataSource {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		hclFile.Body().AppendBlock(nameToDataSource[name])
+		hclFile.Body().AppendNewline()
+	}
+}
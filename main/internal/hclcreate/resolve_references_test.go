@@ -0,0 +1,188 @@
+package hclcreate
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+func TestResolveReferences(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_instance" "web" {
+  ami                    = "ami-123"
+  subnet_id              = "subnet-new"
+  vpc_security_group_ids = ["sg-managed", "sg-other", "sg-unknown"]
+  iam_instance_profile_id = "profile-elsewhere"
+  network_interface {
+    network_interface_id = "eni-module"
+  }
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diagnostics.HasErrors())
+
+	idToTargets := IDToReferenceTargets{
+		"subnet-new": {{Workspace: "workspace", ResourceType: "aws_subnet", Address: "aws_subnet.private"}},
+		"profile-elsewhere": {
+			{Workspace: "other", ResourceType: "aws_iam_instance_profile", Address: "aws_iam_instance_profile.web"},
+		},
+	}
+	addStateReferenceTargets(idToTargets, "workspace", driftDetector.TerraformStateFile{
+		Resources: []*driftDetector.Resource{
+			{
+				Mode: "managed", Type: "aws_security_group", Name: "web",
+				Instances: []driftDetector.ResourceInstance{{IndexKey: float64(0), Attributes: map[string]interface{}{"id": "sg-managed"}}},
+			},
+			{
+				Mode: "managed", Module: "module.network", Type: "aws_network_interface", Name: "web",
+				Instances: []driftDetector.ResourceInstance{{Attributes: map[string]interface{}{"id": "eni-module"}}},
+			},
+		},
+	})
+	addStateReferenceTargets(idToTargets, "other", driftDetector.TerraformStateFile{
+		Resources: []*driftDetector.Resource{
+			{
+				Mode: "managed", Type: "aws_security_group", Name: "shared",
+				Instances: []driftDetector.ResourceInstance{{Attributes: map[string]interface{}{"id": "sg-other"}}},
+			},
+		},
+	})
+
+	// When
+	dataSources := h.resolveReferences(hclFile.Body().Blocks()[0], "workspace", idToTargets)
+	appendDataSources(hclFile, dataSources)
+
+	// Then
+	expected := `resource "aws_instance" "web" {
+  ami                     = "ami-123"
+  subnet_id               = aws_subnet.private.id
+  vpc_security_group_ids  = [aws_security_group.web[0].id, data.aws_security_group.other_aws_security_group_shared.id, "sg-unknown"] # "sg-unknown" is not managed by Terraform or imported within this run
+  iam_instance_profile_id = "profile-elsewhere"                                                                                      # "profile-elsewhere" is aws_iam_instance_profile.web in workspace other, which has no supported data source lookup
+  network_interface {
+    network_interface_id = data.aws_network_interface.workspace_aws_network_interface_web.id
+  }
+}
+data "aws_network_interface" "workspace_aws_network_interface_web" {
+  id = "eni-module"
+}
+
+data "aws_security_group" "other_aws_security_group_shared" {
+  id = "sg-other"
+}
+
+`
+	assert.Equal(t, expected, string(hclwrite.Format(hclFile.Bytes())))
+}
+
+func TestResolveReferences_CrossWorkspaceDataSources(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_volume_attachment" "data" {
+  device_name = "/dev/sdf"
+  instance_id = "i-elsewhere"
+  volume_id   = "vol-elsewhere"
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diagnostics.HasErrors())
+
+	idToTargets := IDToReferenceTargets{}
+	addStateReferenceTargets(idToTargets, "compute", driftDetector.TerraformStateFile{
+		Resources: []*driftDetector.Resource{
+			{
+				Mode: "managed", Type: "aws_instance", Name: "app",
+				Instances: []driftDetector.ResourceInstance{{IndexKey: "blue", Attributes: map[string]interface{}{"id": "i-elsewhere"}}},
+			},
+			{
+				Mode: "managed", Type: "aws_ebs_volume", Name: "data",
+				Instances: []driftDetector.ResourceInstance{{Attributes: map[string]interface{}{"id": "vol-elsewhere"}}},
+			},
+		},
+	})
+
+	// When
+	dataSources := h.resolveReferences(hclFile.Body().Blocks()[0], "storage", idToTargets)
+	appendDataSources(hclFile, dataSources)
+
+	// Then
+	expected := `resource "aws_volume_attachment" "data" {
+  device_name = "/dev/sdf"
+  instance_id = data.aws_instance.compute_aws_instance_app__blue__.id
+  volume_id   = data.aws_ebs_volume.compute_aws_ebs_volume_data.id
+}
+data "aws_ebs_volume" "compute_aws_ebs_volume_data" {
+  filter {
+    name   = "volume-id"
+    values = ["vol-elsewhere"]
+  }
+}
+
+data "aws_instance" "compute_aws_instance_app__blue__" {
+  instance_id = "i-elsewhere"
+}
+
+`
+	assert.Equal(t, expected, string(hclwrite.Format(hclFile.Bytes())))
+}
+
+func TestMatchingTargets(t *testing.T) {
+	// Given
+	targets := []ReferenceTarget{
+		{Workspace: "network", ResourceType: "aws_vpc", Address: "aws_vpc.main"},
+		{Workspace: "network", ResourceType: "aws_default_security_group", Address: "aws_default_security_group.main"},
+	}
+
+	// When
+	vpcTargets := matchingTargets("vpc_id", targets)
+	subnetTargets := matchingTargets("subnet_id", targets)
+
+	// Then
+	assert.Equal(t, targets[:1], vpcTargets)
+	assert.Empty(t, subnetTargets)
+}
+
+func TestResolveReferences_NonReferenceAttributes(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_route53_record" "www" {
+  zone_id = "Z0123456789"
+  alias {
+    name    = "web-123.us-east-1.elb.amazonaws.com"
+    zone_id = "Z35SXDOTRQ7X7K"
+  }
+}
+
+resource "aws_ec2_transit_gateway_peering_attachment" "peer" {
+  peer_account_id      = "111111111111"
+  owner_id             = "222222222222"
+  availability_zone_id = "use1-az1"
+  hosted_zone_id       = "Z35SXDOTRQ7X7K"
+}
+`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	assert.False(t, diagnostics.HasErrors())
+
+	// When
+	dataSources := h.resolveReferences(hclFile.Body().Blocks()[0], "workspace", IDToReferenceTargets{})
+	dataSources = append(dataSources, h.resolveReferences(hclFile.Body().Blocks()[1], "workspace", IDToReferenceTargets{})...)
+
+	// Then
+	assert.Empty(t, dataSources)
+	assert.Equal(t, `resource "aws_route53_record" "www" {
+  zone_id = "Z0123456789" # "Z0123456789" is not managed by Terraform or imported within this run
+  alias {
+    name    = "web-123.us-east-1.elb.amazonaws.com"
+    zone_id = "Z35SXDOTRQ7X7K"
+  }
+}
+
+resource "aws_ec2_transit_gateway_peering_attachment" "peer" {
+  peer_account_id      = "111111111111"
+  owner_id             = "222222222222"
+  availability_zone_id = "use1-az1"
+  hosted_zone_id       = "Z35SXDOTRQ7X7K"
+}
+`, string(hclwrite.Format(hclFile.Bytes())))
+}
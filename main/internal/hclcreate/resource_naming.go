@@ -0,0 +1,351 @@
+package hclcreate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// ResourceNameData is the data available to a resource name template, for example
+// `{{.ShortType}}_{{index .Tags "Name"}}_{{.Region}}`.
+type ResourceNameData struct {
+	// Type is the Terraform resource type, for example "aws_s3_bucket".
+	Type string
+
+	// ShortType is the Terraform resource type without the provider prefix, for example "s3_bucket".
+	ShortType string
+
+	// Name is the name terraformer generated for the resource, with the "tfer--" prefix removed.
+	Name string
+
+	// Tags are the tags, or labels in GCP, of the cloud resource.
+	Tags map[string]string
+
+	// Region is the region of the cloud resource, if any.
+	Region string
+
+	// Division is the cloud division being scanned.
+	Division string
+
+	// CloudID is the id of the cloud resource.
+	CloudID string
+}
+
+// GeneratedResourceName is the name chosen for a resource being imported into Terraform control.
+type GeneratedResourceName struct {
+	// Name is the Terraform resource name.
+	Name string `json:"Name"`
+
+	// Address is the Terraform resource address within the workspace.
+	Address string `json:"Address"`
+
+	// Workspace is the name of the workspace the resource is written to.
+	Workspace string `json:"Workspace"`
+
+	// CloudID is the id of the cloud resource.
+	CloudID string `json:"CloudID"`
+
+	// Provider is the aliased provider configuration of the resource, for example "aws.eu_west_1". Empty when the
+	// resource uses the workspace's default provider configuration.
+	Provider string `json:"Provider,omitempty"`
+}
+
+// ResourceToGeneratedName is a map between terraformer resource identifiers, of the form "type.tfer--name",
+// and the name chosen for that resource.
+type ResourceToGeneratedName map[string]GeneratedResourceName
+
+// resourceNamesPath is the path of the file recording the name chosen for each new resource.
+const resourceNamesPath = "outputs/new-resources-names.json"
+
+// camelCaseBoundary matches the boundary between a lowercase letter or digit and an uppercase letter.
+var camelCaseBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// nonAlphanumericCharacters matches runs of characters which are not letters or digits.
+var nonAlphanumericCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ParseResourceNameTemplate parses a resource name template. An empty template returns a nil template, in which
+// case the terraformer generated name is used.
+func ParseResourceNameTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	resourceNameTemplate, err := template.New("resource-name").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("[template.Parse]%v", err)
+	}
+
+	return resourceNameTemplate, nil
+}
+
+// normalizeResourceName converts a rendered name into snake case, ensuring the result is a valid Terraform name.
+func normalizeResourceName(name string) string {
+	snakeCase := camelCaseBoundary.ReplaceAllString(name, "${1}_${2}")
+	snakeCase = nonAlphanumericCharacters.ReplaceAllString(strings.ToLower(snakeCase), "_")
+	snakeCase = strings.Trim(snakeCase, "_")
+
+	if snakeCase == "" || unicode.IsDigit(rune(snakeCase[0])) {
+		snakeCase = "r_" + snakeCase
+	}
+
+	return snakeCase
+}
+
+// generateResourceNames chooses the name, address and provider configuration of each new resource, placing resources
+// into module calls where they match a module pattern, writes the result to outputs/new-resources-names.json, and stores it for use when
+// writing imports.
+func (h *hclCreate) generateResourceNames(newResourceToWorkspace NewResourceToWorkspace, workspaceToDirectory map[string]string) error {
+	terraformerStateBytes, err := os.ReadFile("current_cloud/terraform.tfstate")
+	if err != nil {
+		return fmt.Errorf("[os.ReadFile current_cloud/terraform.tfstate]%v", err)
+	}
+
+	terraformerState, err := driftDetector.ParseTerraformerStateFile(terraformerStateBytes)
+	if err != nil {
+		return fmt.Errorf("[driftDetector.ParseTerraformerStateFile]%v", err)
+	}
+
+	resourceToNameData := map[string]ResourceNameData{}
+	resourceToAttributesFlat := map[string]map[string]string{}
+	resourceToRegion := map[string]string{}
+	for _, resource := range terraformerState.Resources {
+		resourceID := fmt.Sprintf("%v.%v", resource.Type, resource.Name)
+		if _, ok := newResourceToWorkspace[resourceID]; !ok || len(resource.Instances) == 0 {
+			continue
+		}
+
+		resourceToNameData[resourceID] = h.resourceNameData(resource.Type, resource.Name, resource.Instances[0].AttributesFlat)
+		resourceToAttributesFlat[resourceID] = resource.Instances[0].AttributesFlat
+		resourceToRegion[resourceID] = resourceRegion(resource.Type, resource.Instances[0].AttributesFlat)
+	}
+
+	workspaceToExistingNames := map[string]map[string]bool{}
+	for _, workspace := range newResourceToWorkspace {
+		if _, ok := workspaceToExistingNames[workspace]; ok {
+			continue
+		}
+
+		existingNames, err := loadExistingResourceNames(workspace)
+		if err != nil {
+			return fmt.Errorf("[loadExistingResourceNames]%v", err)
+		}
+		workspaceToExistingNames[workspace] = existingNames
+	}
+
+	resourceNames, err := h.assignResourceNames(newResourceToWorkspace, resourceToNameData, workspaceToExistingNames)
+	if err != nil {
+		return fmt.Errorf("[h.assignResourceNames]%v", err)
+	}
+
+	h.resourceNames = resourceNames
+	h.placeResourcesIntoModules(newResourceToWorkspace, resourceToAttributesFlat, workspaceToExistingNames)
+
+	err = h.assignProviderAliases(resourceToRegion, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[h.assignProviderAliases]%v", err)
+	}
+
+	resourceNamesBytes, err := json.MarshalIndent(h.resourceNames, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%v", err)
+	}
+
+	err = os.WriteFile(resourceNamesPath, resourceNamesBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile %v]%v", resourceNamesPath, err)
+	}
+
+	return nil
+}
+
+// resourceNameData builds the template data for a terraformer resource from its flattened attributes.
+func (h *hclCreate) resourceNameData(resourceType string, terraformerName string, attributesFlat map[string]string) ResourceNameData {
+	provider := strings.Split(resourceType, "_")[0]
+
+	tagsPrefix := "tags."
+	if provider == "google" {
+		tagsPrefix = "labels."
+	}
+
+	tags := map[string]string{}
+	for key, value := range attributesFlat {
+		if strings.HasPrefix(key, tagsPrefix) && key != tagsPrefix+"%" {
+			tags[strings.TrimPrefix(key, tagsPrefix)] = value
+		}
+	}
+
+	region, err := driftDetector.ParseRegionFromTfStateMap(attributesFlat, provider)
+	if err != nil {
+		logrus.Debugf("[hclcreate][resourceNameData] unable to parse region of %v.%v: %v", resourceType, terraformerName, err)
+	}
+
+	return ResourceNameData{
+		Type:      resourceType,
+		ShortType: strings.TrimPrefix(resourceType, provider+"_"),
+		Name:      ConvertTerraformerResourceName(terraformerName),
+		Tags:      tags,
+		Region:    region,
+		Division:  h.config.Division,
+		CloudID:   attributesFlat["id"],
+	}
+}
+
+// loadExistingResourceNames returns the addresses of the resources, and the names of the module calls, within the root
+// module of a workspace's state file, which new names must not collide with. A workspace without a state file has no
+// existing names.
+func loadExistingResourceNames(workspace string) (map[string]bool, error) {
+	existingNames := map[string]bool{}
+
+	stateBytes, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", workspace))
+	if errors.Is(err, os.ErrNotExist) {
+		return existingNames, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile state_files/%v.json]%v", workspace, err)
+	}
+
+	var stateFile driftDetector.TerraformStateFile
+	err = json.Unmarshal(stateBytes, &stateFile)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal state_files/%v.json]%v", workspace, err)
+	}
+
+	for _, resource := range stateFile.Resources {
+		if resource.Module != "" {
+			existingNames[strings.Join(strings.SplitN(resource.Module, ".", 3)[:2], ".")] = true
+		} else if resource.Mode == "managed" {
+			existingNames[fmt.Sprintf("%v.%v", resource.Type, resource.Name)] = true
+		}
+	}
+
+	return existingNames, nil
+}
+
+// assignResourceNames renders the name of each new resource and resolves collisions across the whole job, as well
+// as with resources already within the destination workspace, by appending a numeric suffix. Resources are processed
+// in sorted order so that names are stable between runs.
+func (h *hclCreate) assignResourceNames(
+	newResourceToWorkspace NewResourceToWorkspace,
+	resourceToNameData map[string]ResourceNameData,
+	workspaceToExistingNames map[string]map[string]bool,
+) (ResourceToGeneratedName, error) {
+	resourceIDs := make([]string, 0, len(newResourceToWorkspace))
+	for resourceID := range newResourceToWorkspace {
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	sort.Strings(resourceIDs)
+
+	usedAddresses := map[string]bool{}
+	resourceNames := ResourceToGeneratedName{}
+	for _, resourceID := range resourceIDs {
+		workspace := newResourceToWorkspace[resourceID]
+		resourceIdentifier := h.resourceToIdentifierStruct(resourceID)
+
+		nameData, ok := resourceToNameData[resourceID]
+		if !ok {
+			nameData = ResourceNameData{
+				Type: resourceIdentifier.resourceType,
+				Name: ConvertTerraformerResourceName(resourceIdentifier.resourceName),
+			}
+		}
+
+		baseName, err := h.renderResourceName(nameData)
+		if err != nil {
+			return nil, fmt.Errorf("[h.renderResourceName] %v: %v", resourceID, err)
+		}
+
+		name := baseName
+		for suffix := 2; usedAddresses[resourceIdentifier.resourceType+"."+name] ||
+			workspaceToExistingNames[workspace][resourceIdentifier.resourceType+"."+name]; suffix++ {
+			name = fmt.Sprintf("%v_%d", baseName, suffix)
+		}
+
+		address := fmt.Sprintf("%v.%v", resourceIdentifier.resourceType, name)
+		usedAddresses[address] = true
+		resourceNames[resourceID] = GeneratedResourceName{
+			Name:      name,
+			Address:   address,
+			Workspace: workspace,
+			CloudID:   nameData.CloudID,
+		}
+	}
+
+	return resourceNames, nil
+}
+
+// renderResourceName renders the configured name template for a resource. Without a template, or when the template
+// renders an empty name, the terraformer generated name is used.
+func (h *hclCreate) renderResourceName(nameData ResourceNameData) (string, error) {
+	if h.resourceNameTemplate == nil {
+		return nameData.Name, nil
+	}
+
+	var rendered bytes.Buffer
+	err := h.resourceNameTemplate.Execute(&rendered, nameData)
+	if err != nil {
+		return "", fmt.Errorf("[template.Execute]%v", err)
+	}
+
+	if nonAlphanumericCharacters.ReplaceAllString(strings.ToLower(rendered.String()), "") == "" {
+		return normalizeResourceName(nameData.Name), nil
+	}
+
+	return normalizeResourceName(rendered.String()), nil
+}
+
+// loadResourceNames reads the names chosen for new resources, if they have not already been generated within this run.
+func (h *hclCreate) loadResourceNames() error {
+	if h.resourceNames != nil {
+		return nil
+	}
+
+	resourceNamesBytes, err := os.ReadFile(resourceNamesPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("[os.ReadFile %v]%v", resourceNamesPath, err)
+	}
+
+	resourceNames := ResourceToGeneratedName{}
+	err = json.Unmarshal(resourceNamesBytes, &resourceNames)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal %v]%v", resourceNamesPath, err)
+	}
+
+	h.resourceNames = resourceNames
+	return nil
+}
+
+// resourceName returns the name chosen for a terraformer generated resource, defaulting to the terraformer
+// generated name with the "tfer--" prefix removed.
+func (h *hclCreate) resourceName(resourceIdentifier ResourceIdentifier) string {
+	resourceID := fmt.Sprintf("%v.%v", resourceIdentifier.resourceType, resourceIdentifier.resourceName)
+	if generatedName, ok := h.resourceNames[resourceID]; ok {
+		return generatedName.Name
+	}
+
+	return ConvertTerraformerResourceName(resourceIdentifier.resourceName)
+}
+
+// resourceAddress returns the address chosen for a terraformer generated resource, which is within a module call
+// when the resource matched a module pattern.
+func (h *hclCreate) resourceAddress(resourceIdentifier ResourceIdentifier) string {
+	resourceID := fmt.Sprintf("%v.%v", resourceIdentifier.resourceType, resourceIdentifier.resourceName)
+	if generatedName, ok := h.resourceNames[resourceID]; ok {
+		return generatedName.Address
+	}
+
+	return fmt.Sprintf("%v.%v", resourceIdentifier.resourceType, ConvertTerraformerResourceName(resourceIdentifier.resourceName))
+}
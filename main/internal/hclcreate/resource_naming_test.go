@@ -0,0 +1,84 @@
+package hclcreate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeResourceName(t *testing.T) {
+	// Given
+	inputToExpected := map[string]string{
+		"s3_bucket_MyAppLogs_us-east-1": "s3_bucket_my_app_logs_us_east_1",
+		"--Web Server--":                "web_server",
+		"123-data":                      "r_123_data",
+		"":                              "r_",
+	}
+
+	for input, expected := range inputToExpected {
+		// When
+		output := normalizeResourceName(input)
+
+		// Then
+		assert.Equal(t, expected, output, input)
+	}
+}
+
+func TestAssignResourceNames(t *testing.T) {
+	// Given
+	resourceNameTemplate, err := ParseResourceNameTemplate(`{{.ShortType}}_{{index .Tags "Name"}}`)
+	require.NoError(t, err)
+	h := hclCreate{resourceNameTemplate: resourceNameTemplate}
+
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws_s3_bucket.tfer--logs-a": "workspace_1",
+		"aws_s3_bucket.tfer--logs-b": "workspace_2",
+		"aws_s3_bucket.tfer--web":    "workspace_1",
+		"aws_vpc.tfer--vpc-123":      "workspace_1",
+	}
+	resourceToNameData := map[string]ResourceNameData{
+		"aws_s3_bucket.tfer--logs-a": {Type: "aws_s3_bucket", ShortType: "s3_bucket", Name: "logs_a", Tags: map[string]string{"Name": "AppLogs"}, CloudID: "logs-a"},
+		"aws_s3_bucket.tfer--logs-b": {Type: "aws_s3_bucket", ShortType: "s3_bucket", Name: "logs_b", Tags: map[string]string{"Name": "AppLogs"}, CloudID: "logs-b"},
+		"aws_s3_bucket.tfer--web":    {Type: "aws_s3_bucket", ShortType: "s3_bucket", Name: "web", Tags: map[string]string{"Name": "web"}, CloudID: "web"},
+		"aws_vpc.tfer--vpc-123":      {Type: "aws_vpc", ShortType: "vpc", Name: "vpc_123", Tags: map[string]string{}, CloudID: "vpc-123"},
+	}
+	workspaceToExistingNames := map[string]map[string]bool{
+		"workspace_1": {"aws_s3_bucket.s3_bucket_web": true},
+	}
+
+	// When
+	resourceNames, err := h.assignResourceNames(newResourceToWorkspace, resourceToNameData, workspaceToExistingNames)
+
+	// Then
+	require.NoError(t, err)
+	expected := ResourceToGeneratedName{
+		"aws_s3_bucket.tfer--logs-a": {Name: "s3_bucket_app_logs", Address: "aws_s3_bucket.s3_bucket_app_logs", Workspace: "workspace_1", CloudID: "logs-a"},
+		"aws_s3_bucket.tfer--logs-b": {Name: "s3_bucket_app_logs_2", Address: "aws_s3_bucket.s3_bucket_app_logs_2", Workspace: "workspace_2", CloudID: "logs-b"},
+		"aws_s3_bucket.tfer--web":    {Name: "s3_bucket_web_2", Address: "aws_s3_bucket.s3_bucket_web_2", Workspace: "workspace_1", CloudID: "web"},
+		"aws_vpc.tfer--vpc-123":      {Name: "vpc", Address: "aws_vpc.vpc", Workspace: "workspace_1", CloudID: "vpc-123"},
+	}
+	assert.Equal(t, expected, resourceNames)
+
+	h.resourceNames = resourceNames
+	assert.Equal(t, "import aws_s3_bucket.s3_bucket_app_logs_2 logs-b", h.generateImportStatementText(
+		"logs-b", ResourceIdentifier{resourceType: "aws_s3_bucket", resourceName: "tfer--logs-b"},
+	))
+}
+
+func TestAssignResourceNames_WithoutTemplate(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	newResourceToWorkspace := NewResourceToWorkspace{
+		"aws_vpc.tfer--main-vpc": "workspace_1",
+		"aws_vpc.tfer--main_vpc": "workspace_1",
+	}
+
+	// When
+	resourceNames, err := h.assignResourceNames(newResourceToWorkspace, map[string]ResourceNameData{}, map[string]map[string]bool{})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "main_vpc", resourceNames["aws_vpc.tfer--main-vpc"].Name)
+	assert.Equal(t, "main_vpc_2", resourceNames["aws_vpc.tfer--main_vpc"].Name)
+}
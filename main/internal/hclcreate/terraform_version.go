@@ -0,0 +1,321 @@
+package hclcreate
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TerraformCapability is a Terraform language or CLI feature used by generated code.
+type TerraformCapability string
+
+const (
+	// ImportBlocksCapability is support for `import` blocks.
+	ImportBlocksCapability TerraformCapability = "import blocks"
+
+	// ImportForEachCapability is support for `for_each` within `import` blocks.
+	ImportForEachCapability TerraformCapability = "import blocks with for_each"
+
+	// RemovedBlocksCapability is support for `removed` blocks.
+	RemovedBlocksCapability TerraformCapability = "removed blocks"
+
+	// GenerateConfigOutCapability is support for `terraform plan -generate-config-out`.
+	GenerateConfigOutCapability TerraformCapability = "plan -generate-config-out"
+)
+
+// terraformCapabilityVersions is the capability matrix, a map between each capability and the first Terraform
+// version supporting it.
+var terraformCapabilityVersions = map[TerraformCapability]TerraformVersion{
+	ImportBlocksCapability:      {Major: 1, Minor: 5},
+	ImportForEachCapability:     {Major: 1, Minor: 7},
+	RemovedBlocksCapability:     {Major: 1, Minor: 7},
+	GenerateConfigOutCapability: {Major: 1, Minor: 5},
+}
+
+// versionPattern matches a version of up to three numeric components with an optional pre-release suffix.
+var versionPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?$`)
+
+// TerraformVersion is a parsed semantic version of Terraform.
+type TerraformVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// ParseTerraformVersion parses a version such as "1.7.0", "v1.10" or "1.7.0-beta1".
+func ParseTerraformVersion(text string) (TerraformVersion, error) {
+	version, _, err := parseVersionWithPrecision(text)
+	return version, err
+}
+
+// parseVersionWithPrecision parses a version, also returning the number of numeric components specified.
+func parseVersionWithPrecision(text string) (TerraformVersion, int, error) {
+	matches := versionPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if matches == nil {
+		return TerraformVersion{}, 0, fmt.Errorf("invalid version %q", text)
+	}
+
+	components := [3]int{}
+	precision := 0
+	for i := range components {
+		if matches[i+1] == "" {
+			break
+		}
+		components[i], _ = strconv.Atoi(matches[i+1])
+		precision++
+	}
+
+	return TerraformVersion{Major: components[0], Minor: components[1], Patch: components[2], Prerelease: matches[4]}, precision, nil
+}
+
+// String returns the version in "major.minor.patch" form.
+func (v TerraformVersion) String() string {
+	if v.Prerelease != "" {
+		return fmt.Sprintf("%d.%d.%d-%v", v.Major, v.Minor, v.Patch, v.Prerelease)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 when the version is lower than, equal to or greater than other. A pre-release is
+// lower than the release of the same version.
+func (v TerraformVersion) Compare(other TerraformVersion) int {
+	for _, difference := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if difference < 0 {
+			return -1
+		} else if difference > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	case v.Prerelease < other.Prerelease:
+		return -1
+	}
+	return 1
+}
+
+// Supports returns true if the version supports the capability. Pre-releases of the first supporting version are
+// considered to support it.
+func (v TerraformVersion) Supports(capability TerraformCapability) bool {
+	minimum := terraformCapabilityVersions[capability]
+	release := TerraformVersion{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	return release.Compare(minimum) >= 0
+}
+
+// versionConstraint is a single operator and version within a version constraint string.
+type versionConstraint struct {
+	operator  string
+	version   TerraformVersion
+	precision int
+}
+
+// TerraformVersionConstraints are the comma separated constraints of a `required_version` string, all of which must
+// be satisfied.
+type TerraformVersionConstraints []versionConstraint
+
+// constraintPattern matches a single constraint, an optional operator followed by a version.
+var constraintPattern = regexp.MustCompile(`^(~>|>=|<=|!=|=|>|<)?\s*(\S+)$`)
+
+// ParseTerraformVersionConstraints parses a constraint string such as ">= 1.5.0, < 2.0.0" or "~> 1.7".
+func ParseTerraformVersionConstraints(text string) (TerraformVersionConstraints, error) {
+	constraints := TerraformVersionConstraints{}
+	for _, clause := range strings.Split(text, ",") {
+		matches := constraintPattern.FindStringSubmatch(strings.TrimSpace(clause))
+		if matches == nil {
+			return nil, fmt.Errorf("invalid version constraint %q", clause)
+		}
+
+		version, precision, err := parseVersionWithPrecision(matches[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %v", clause, err)
+		}
+
+		operator := matches[1]
+		if operator == "" {
+			operator = "="
+		}
+		constraints = append(constraints, versionConstraint{operator: operator, version: version, precision: precision})
+	}
+
+	return constraints, nil
+}
+
+// Check returns true if the version satisfies every constraint.
+func (c TerraformVersionConstraints) Check(version TerraformVersion) bool {
+	for _, constraint := range c {
+		comparison := version.Compare(constraint.version)
+		satisfied := false
+		switch constraint.operator {
+		case "=":
+			satisfied = comparison == 0
+		case "!=":
+			satisfied = comparison != 0
+		case ">":
+			satisfied = comparison > 0
+		case ">=":
+			satisfied = comparison >= 0
+		case "<":
+			satisfied = comparison < 0
+		case "<=":
+			satisfied = comparison <= 0
+		case "~>":
+			satisfied = comparison >= 0 && version.Compare(pessimisticUpperBound(constraint)) < 0
+		}
+
+		if !satisfied {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pessimisticUpperBound returns the exclusive upper bound of a "~>" constraint, which allows only the rightmost
+// specified version component to increase.
+func pessimisticUpperBound(constraint versionConstraint) TerraformVersion {
+	switch constraint.precision {
+	case 3:
+		return TerraformVersion{Major: constraint.version.Major, Minor: constraint.version.Minor + 1}
+	default:
+		return TerraformVersion{Major: constraint.version.Major + 1}
+	}
+}
+
+// LowestVersion returns the lowest version satisfying every constraint, and false if no version does.
+func (c TerraformVersionConstraints) LowestVersion() (TerraformVersion, bool) {
+	lowest := TerraformVersion{}
+	for _, constraint := range c {
+		candidate := constraint.version
+		switch constraint.operator {
+		case ">":
+			candidate = TerraformVersion{Major: candidate.Major, Minor: candidate.Minor, Patch: candidate.Patch + 1}
+		case "<", "<=", "!=":
+			continue
+		}
+
+		if candidate.Compare(lowest) > 0 {
+			lowest = candidate
+		}
+	}
+
+	// Versions excluded by "!=" constraints are skipped by moving to the next patch release.
+	for i := 0; i <= len(c); i++ {
+		if c.Check(lowest) {
+			return lowest, true
+		}
+		lowest = TerraformVersion{Major: lowest.Major, Minor: lowest.Minor, Patch: lowest.Patch + 1}
+	}
+
+	return TerraformVersion{}, false
+}
+
+// resolveTerraformVersion returns the Terraform version whose capabilities generated code may rely upon. The
+// configured version, itself either a version or constraint, is used when it satisfies the workspace's
+// required_version, and otherwise, or when no version is configured, the lowest version allowed by required_version.
+func resolveTerraformVersion(configured string, required TerraformVersionConstraints) (TerraformVersion, error) {
+	if configured == "" {
+		requiredVersion, ok := required.LowestVersion()
+		if !ok {
+			return TerraformVersion{}, fmt.Errorf("no version satisfies the workspace's required_version")
+		}
+		return requiredVersion, nil
+	}
+
+	configuredConstraints, err := ParseTerraformVersionConstraints(configured)
+	if err != nil {
+		return TerraformVersion{}, fmt.Errorf("[ParseTerraformVersionConstraints]%v", err)
+	}
+
+	configuredVersion, ok := configuredConstraints.LowestVersion()
+	if !ok {
+		return TerraformVersion{}, fmt.Errorf("no version satisfies the configured terraform version %q", configured)
+	}
+
+	if required.Check(configuredVersion) {
+		return configuredVersion, nil
+	}
+
+	requiredVersion, ok := required.LowestVersion()
+	if !ok {
+		return TerraformVersion{}, fmt.Errorf("no version satisfies the workspace's required_version")
+	}
+	return requiredVersion, nil
+}
+
+// workspaceTerraformVersion returns the Terraform version whose capabilities the code generated for the workspace
+// directory may rely upon, taking the workspace's required_version into account. Without a configured version, the
+// version inferred from the repository is used.
+func (h *hclCreate) workspaceTerraformVersion(directory string) (TerraformVersion, error) {
+	if version, ok := h.directoryToTerraformVersion[directory]; ok {
+		return version, nil
+	}
+
+	required, err := loadRequiredVersion(fmt.Sprintf("repo%v", directory))
+	if err != nil {
+		return TerraformVersion{}, fmt.Errorf("[loadRequiredVersion]%v", err)
+	}
+
+	configured := h.config.TerraformVersion
+	if configured == "" {
+		configured, err = loadRepositoryTerraformVersion()
+		if err != nil {
+			return TerraformVersion{}, fmt.Errorf("[loadRepositoryTerraformVersion]%v", err)
+		}
+	}
+
+	version, err := resolveTerraformVersion(configured, required)
+	if err != nil {
+		return TerraformVersion{}, fmt.Errorf("[resolveTerraformVersion %v]%v", directory, err)
+	}
+
+	if h.directoryToTerraformVersion == nil {
+		h.directoryToTerraformVersion = map[string]TerraformVersion{}
+	}
+	h.directoryToTerraformVersion[directory] = version
+	return version, nil
+}
+
+// loadRequiredVersion reads the required_version constraints of every terraform block within the .tf files of a
+// directory.
+func loadRequiredVersion(directory string) (TerraformVersionConstraints, error) {
+	configFiles, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return nil, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	required := TerraformVersionConstraints{}
+	for _, configFile := range configFiles {
+		hclFile, err := parseHCLFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() != "terraform" {
+				continue
+			}
+
+			requiredVersion, ok := stringAttributeValue(block.Body(), "required_version")
+			if !ok {
+				continue
+			}
+
+			constraints, err := ParseTerraformVersionConstraints(requiredVersion)
+			if err != nil {
+				return nil, fmt.Errorf("[ParseTerraformVersionConstraints %v]%v", configFile, err)
+			}
+			required = append(required, constraints...)
+		}
+	}
+
+	return required, nil
+}
@@ -0,0 +1,102 @@
+package hclcreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformVersionSupports(t *testing.T) {
+	cases := []struct {
+		version    string
+		capability TerraformCapability
+		expected   bool
+	}{
+		{"1.5.0", ImportBlocksCapability, true},
+		{"1.10.2", ImportBlocksCapability, true},
+		{"1.4.6", ImportBlocksCapability, false},
+		{"1.7.5", ImportForEachCapability, true},
+		{"1.6.6", ImportForEachCapability, false},
+		{"v1.7.0", RemovedBlocksCapability, true},
+		{"1.7.0-beta1", RemovedBlocksCapability, true},
+		{"1.6.6", RemovedBlocksCapability, false},
+		{"2.0", RemovedBlocksCapability, true},
+	}
+
+	for _, c := range cases {
+		version, err := ParseTerraformVersion(c.version)
+		require.NoError(t, err)
+
+		assert.Equal(t, c.expected, version.Supports(c.capability), "%v supports %v", c.version, c.capability)
+	}
+}
+
+func TestTerraformVersionConstraints(t *testing.T) {
+	cases := []struct {
+		constraints string
+		lowest      string
+		allowed     []string
+		excluded    []string
+	}{
+		{"~> 1.7.0", "1.7.0", []string{"1.7.5"}, []string{"1.8.0", "1.6.9"}},
+		{"~> 1.7", "1.7.0", []string{"1.10.0"}, []string{"2.0.0", "1.6.0"}},
+		{">= 1.5.0, < 2.0.0", "1.5.0", []string{"1.9.8"}, []string{"2.0.0", "1.4.7"}},
+		{"> 1.5.0, != 1.5.1", "1.5.2", []string{"1.6.0"}, []string{"1.5.0", "1.5.1"}},
+		{"1.6.2", "1.6.2", []string{"1.6.2"}, []string{"1.6.3"}},
+	}
+
+	for _, c := range cases {
+		constraints, err := ParseTerraformVersionConstraints(c.constraints)
+		require.NoError(t, err)
+
+		lowest, ok := constraints.LowestVersion()
+		assert.True(t, ok)
+		assert.Equal(t, c.lowest, lowest.String(), c.constraints)
+
+		for _, allowed := range c.allowed {
+			version, err := ParseTerraformVersion(allowed)
+			require.NoError(t, err)
+			assert.True(t, constraints.Check(version), "%v allows %v", c.constraints, allowed)
+		}
+		for _, excluded := range c.excluded {
+			version, err := ParseTerraformVersion(excluded)
+			require.NoError(t, err)
+			assert.False(t, constraints.Check(version), "%v excludes %v", c.constraints, excluded)
+		}
+	}
+
+	_, err := ParseTerraformVersionConstraints(">= one")
+	assert.NotNil(t, err)
+}
+
+func TestWorkspaceTerraformVersion(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	for workspace, requiredVersion := range map[string]string{"legacy": "~> 1.4.0", "current": ">= 1.3.0"} {
+		directory := filepath.Join("repo", workspace)
+		require.NoError(t, os.MkdirAll(directory, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(directory, "versions.tf"), []byte(`terraform {
+  required_version = "`+requiredVersion+`"
+}
+`), 0o600))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join("repo", "unconstrained"), 0o700))
+
+	h := hclCreate{config: Config{TerraformVersion: "~>1.7.1"}}
+
+	for directory, expected := range map[string]string{"/legacy/": "1.4.0", "/current/": "1.7.1", "/unconstrained/": "1.7.1"} {
+		// When
+		version, err := h.workspaceTerraformVersion(directory)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, expected, version.String(), directory)
+	}
+}
@@ -14,9 +14,23 @@ import (
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
+// resourceDefinitionsLoader returns a file containing the resource block of each new resource, labelled with the
+// resource's terraformer generated type and name.
+type resourceDefinitionsLoader func(newResourceToWorkspace NewResourceToWorkspace, workspaceToDirectory map[string]string) (*hclwrite.File, error)
+
 // ExtractResourceDefinitions outputs a bytes slice which defines needed Terraform resources extracted from
 // another configuration file.
 func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToDirectory map[string]string) error {
+	return h.extractResourceDefinitions(noNewResources, workspaceToDirectory, h.loadTerraformerResources)
+}
+
+// extractResourceDefinitions writes the resource blocks of new resources, as loaded by loadResourceDefinitions, into
+// each workspace after naming, cleaning and annotating them.
+func (h *hclCreate) extractResourceDefinitions(
+	noNewResources bool,
+	workspaceToDirectory map[string]string,
+	loadResourceDefinitions resourceDefinitionsLoader,
+) error {
 	logrus.Debugf("[hclcreate][ExtractResourceDefinitions] noNewResources: %v", noNewResources)
 
 	// Mapping between workspace and the new hclwrite document for that workspace
@@ -51,21 +65,6 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 		return fmt.Errorf("[gabsContainerToAllCostsStruct]%v", err)
 	}
 
-	hclBytes, err := os.ReadFile("current_cloud/resources.tf")
-	if err != nil {
-		return fmt.Errorf("[os.ReadFile()] Error reading in resources.tf")
-	}
-
-	terraformerResources, hclDiagnostics := hclwrite.ParseConfig(
-		hclBytes,
-		"cloud-resources.tf",
-		hcl.Pos{Line: 0, Column: 0, Byte: 0},
-	)
-
-	if hclDiagnostics != nil {
-		return fmt.Errorf("[hclwrite.ParseConfig]%v", hclDiagnostics)
-	}
-
 	resourceActions, err := h.cloudActionsToResourceActionMap(parsedCloudActions)
 	if err != nil {
 		return fmt.Errorf("[h.subsetCloudActionsToCurrentDivision]%v", err)
@@ -85,17 +84,56 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 		return fmt.Errorf("[gabs.ParseJSON] Error parsing new-resources-to-workspace.json")
 	}
 
+	newResourceToWorkspace := NewResourceToWorkspace{}
+	if !noNewResources {
+		for resource, workspaceName := range parsedNewResourceToWorkspace.ChildrenMap() {
+			newResourceToWorkspace[resource] = workspaceName.Data().(string)
+		}
+
+		err = h.generateResourceNames(newResourceToWorkspace, workspaceToDirectory)
+		if err != nil {
+			return fmt.Errorf("[h.generateResourceNames] %v", err)
+		}
+	}
+
+	terraformerResources, err := loadResourceDefinitions(newResourceToWorkspace, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[loadResourceDefinitions] %v", err)
+	}
+
+	idToReferenceTargets := IDToReferenceTargets{}
+	if !noNewResources {
+		idToReferenceTargets, err = h.loadReferenceTargets(parsedNewResourceToWorkspace, workspaceToDirectory)
+		if err != nil {
+			return fmt.Errorf("[h.loadReferenceTargets] %v", err)
+		}
+	}
+
+	resourceTypeToSchema := ResourceTypeToSchema{}
+	if !noNewResources {
+		resourceTypeToSchema = h.loadResourceSchemas()
+	}
+
 	completeWorkspaceToHCLFile, err := h.placeHCLIntoNewFileDef(
 		resourceActions,
 		costEstimates,
 		terraformerResources,
 		parsedNewResourceToWorkspace,
 		workspaceToHCLFile,
+		idToReferenceTargets,
+		resourceTypeToSchema,
 	)
 	if err != nil {
 		return fmt.Errorf("[h.placeHCLIntoNewFileDef] %v", err)
 	}
 
+	if h.config.ParameterizeResources && !noNewResources {
+		err = h.parameterizeWorkspaces(completeWorkspaceToHCLFile, workspaceToDirectory)
+		if err != nil {
+			return fmt.Errorf("[h.parameterizeWorkspaces] %v", err)
+		}
+	}
+
 	err = h.writeNewResourceFiles(
 		workspaceToDirectory,
 		completeWorkspaceToHCLFile,
@@ -104,9 +142,34 @@ func (h *hclCreate) ExtractResourceDefinitions(noNewResources bool, workspaceToD
 		return fmt.Errorf("[h.writeNewHCLFiles] %v", err)
 	}
 
+	err = h.writeLocalModules(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[h.writeLocalModules] %v", err)
+	}
+
 	return nil
 }
 
+// loadTerraformerResources parses the resource definitions generated by terraformer.
+func (h *hclCreate) loadTerraformerResources(_ NewResourceToWorkspace, _ map[string]string) (*hclwrite.File, error) {
+	hclBytes, err := os.ReadFile("current_cloud/resources.tf")
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile()] Error reading in resources.tf")
+	}
+
+	terraformerResources, hclDiagnostics := hclwrite.ParseConfig(
+		hclBytes,
+		"cloud-resources.tf",
+		hcl.Pos{Line: 0, Column: 0, Byte: 0},
+	)
+
+	if hclDiagnostics != nil {
+		return nil, fmt.Errorf("[hclwrite.ParseConfig]%v", hclDiagnostics)
+	}
+
+	return terraformerResources, nil
+}
+
 // cloudActionsToResourceActionMap takes in a gabs.Container, and converts to a ResourceActionMap
 func (h *hclCreate) cloudActionsToResourceActionMap(parsedCloudActions *gabs.Container) (
 	terraformValueObjects.ResourceActionMap, error,
@@ -135,21 +198,26 @@ func (h *hclCreate) cloudActionsToResourceActionMap(parsedCloudActions *gabs.Con
 }
 
 // placeHCLIntoNewFileDef transfers the relevant HCL created by terraformer
-// into the new file definition.
+// into the new file definition, removing computed and default-valued attributes, rewriting
+// hard-coded ids into references where possible, and writing resources matching a module pattern as module calls.
 func (h *hclCreate) placeHCLIntoNewFileDef(
 	cloudActions terraformValueObjects.ResourceActionMap,
 	costEstimates costs,
 	terraformerResources *hclwrite.File,
 	parsedNewResourceToWorkspace *gabs.Container,
 	workspaceToHCLFile WorkspaceToHCL,
+	idToReferenceTargets IDToReferenceTargets,
+	resourceTypeToSchema ResourceTypeToSchema,
 ) (WorkspaceToHCL, error) {
+	workspaceToDataSources := map[string][]*hclwrite.Block{}
+
 	for resource, workspaceName := range parsedNewResourceToWorkspace.ChildrenMap() {
 		resourceID := h.splitResourceIdentifier(resource)
 
 		cleanResourceName := ConvertTerraformerResourceName(resourceID.resourceName)
 		extractedBlock, err := h.extractResourceBlockDefinition(
 			terraformerResources,
-			cleanResourceName,
+			h.resourceName(resourceID),
 			resourceID,
 		)
 		if err != nil {
@@ -163,6 +231,27 @@ func (h *hclCreate) placeHCLIntoNewFileDef(
 		// place resource within the corresponding workspace's file.
 		workspaceNameString := workspaceName.Data().(string)
 
+		h.cleanResourceBlock(extractedBlock, resourceTypeToSchema)
+
+		if placement, ok := h.resourceToModulePlacement[resource]; ok {
+			h.addBlockToModulePlacement(placement, resource, append(cloudCostComment, cloudIdentifierComment...), extractedBlock)
+
+			// Resources within a new local module cannot reference the root module directly, so all references
+			// are resolved through data sources.
+			if placement.Pattern.Source == "" {
+				placement.dataSources = append(placement.dataSources, h.resolveReferences(extractedBlock, "", idToReferenceTargets)...)
+			} else {
+				dataSources := h.resolveReferences(extractedBlock, workspaceNameString, idToReferenceTargets)
+				workspaceToDataSources[workspaceNameString] = append(workspaceToDataSources[workspaceNameString], dataSources...)
+			}
+			continue
+		}
+
+		h.setResourceProvider(extractedBlock, resource)
+
+		dataSources := h.resolveReferences(extractedBlock, workspaceNameString, idToReferenceTargets)
+		workspaceToDataSources[workspaceNameString] = append(workspaceToDataSources[workspaceNameString], dataSources...)
+
 		workspaceToHCLFile[workspaceNameString] = h.writeBlockToWorkspaceHCL(
 			workspaceToHCLFile[workspaceNameString],
 			cloudIdentifierComment,
@@ -171,6 +260,13 @@ func (h *hclCreate) placeHCLIntoNewFileDef(
 		)
 	}
 
+	h.appendModuleCalls(workspaceToHCLFile)
+	h.appendProviderAliases(workspaceToHCLFile)
+
+	for workspaceName, dataSources := range workspaceToDataSources {
+		appendDataSources(workspaceToHCLFile[workspaceName], dataSources)
+	}
+
 	return workspaceToHCLFile, nil
 }
 
@@ -205,10 +301,10 @@ func (h *hclCreate) generateHCLCloudActorsComment(
 }
 
 // extractResourceBlockDefinition pulls the resource block from the specified hclFile and renames it
-// to have the name specified by the cleanResourceName variable.
+// to have the name specified by the resourceName variable.
 func (h *hclCreate) extractResourceBlockDefinition(
 	hclFile *hclwrite.File,
-	cleanResourceName string,
+	resourceName string,
 	resourceID ResourceIdentifier,
 ) (*hclwrite.Block, error) {
 	body := hclFile.Body()
@@ -222,7 +318,7 @@ func (h *hclCreate) extractResourceBlockDefinition(
 		return nil, fmt.Errorf("could not find block matching %v, although it was expected", resourceID)
 	}
 
-	labels[1] = cleanResourceName
+	labels[1] = resourceName
 
 	extractBlock.SetLabels(labels)
 
@@ -0,0 +1,864 @@
+package hclcreate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// NoCodeValidation opens the pull request without validating the generated code.
+	NoCodeValidation = "none"
+
+	// ValidateCodeValidation runs `terraform validate` against the generated code of each touched workspace.
+	ValidateCodeValidation = "validate"
+
+	// PlanCodeValidation runs `terraform validate`, and then `terraform plan` against a copy of the workspace's
+	// state to check that importing new resources results in no changes. New resources whose import plans changes are
+	// quarantined, while changes to other resources are only reported.
+	PlanCodeValidation = "plan"
+
+	// planStatusPath is the output file recording the outcome of validating each touched workspace.
+	planStatusPath = "outputs/plan-status.json"
+
+	// validationSandboxPath is the directory into which the repository is copied before running Terraform, so that
+	// neither Terraform's working files nor the copied state are committed.
+	validationSandboxPath = "outputs/validation"
+
+	// sandboxGeneratedFilePrefix prefixes the cloud-concierge import and removed block files copied into the root of
+	// a sandboxed workspace, as Terraform does not load configuration from the subdirectories they are written to.
+	sandboxGeneratedFilePrefix = "cloud_concierge_"
+
+	// quarantinePath is the file, relative to the workspace directory, to which new resources failing validation are
+	// moved. The .txt extension keeps Terraform from loading the file.
+	quarantinePath = "cloud-concierge/quarantine/new-resources.tf.txt"
+
+	// maxValidationAttempts is the number of times a workspace is validated, quarantining the failing new resources
+	// after each unsuccessful attempt.
+	maxValidationAttempts = 3
+)
+
+const (
+	// passedStatus indicates the command succeeded without errors.
+	passedStatus = "passed"
+
+	// failedStatus indicates the command reported errors.
+	failedStatus = "failed"
+
+	// skippedStatus indicates the command was not run because validation failed.
+	skippedStatus = "skipped"
+
+	// noChangesStatus indicates the plan proposes no changes beyond importing new resources.
+	noChangesStatus = "no changes"
+
+	// changesStatus indicates the plan proposes changes, such as a new resource whose configuration differs from
+	// the cloud resource being imported.
+	changesStatus = "changes"
+)
+
+// PlanStatus is the outcome of validating the generated code of a single workspace.
+type PlanStatus struct {
+	Workspace string
+
+	// Validate is the outcome of `terraform validate`.
+	Validate string
+
+	// Plan is the outcome of `terraform plan`, empty when only validating.
+	Plan string
+
+	// QuarantinedResources are the addresses of new resources moved to the quarantine file.
+	QuarantinedResources []string
+
+	// Message describes the errors or changes reported by Terraform.
+	Message string
+}
+
+// validateOutput is the subset of `terraform validate -json` output needed to attribute errors to generated code.
+type validateOutput struct {
+	Valid       bool                 `json:"valid"`
+	Diagnostics []validateDiagnostic `json:"diagnostics"`
+}
+
+// validateDiagnostic is a single diagnostic reported by `terraform validate -json`.
+type validateDiagnostic struct {
+	Severity string                   `json:"severity"`
+	Summary  string                   `json:"summary"`
+	Detail   string                   `json:"detail"`
+	Range    *validateDiagnosticRange `json:"range"`
+}
+
+// validateDiagnosticRange is the source location of a diagnostic, with Filename relative to the workspace directory.
+type validateDiagnosticRange struct {
+	Filename string `json:"filename"`
+	Start    struct {
+		Line int `json:"line"`
+	} `json:"start"`
+}
+
+// planResult is the outcome of planning a sandboxed workspace.
+type planResult struct {
+	// status is the plan status of the workspace.
+	status string
+
+	// message describes the errors or changes reported by Terraform.
+	message string
+
+	// changedNewResources is a map between the addresses of new resources whose import plans changes and the
+	// changes planned.
+	changedNewResources map[string]string
+}
+
+// planOutput is the subset of `terraform show -json` output for a saved plan needed to identify proposed changes.
+type planOutput struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// ValidateGeneratedCode runs `terraform init -backend=false` and `terraform validate`, and optionally `terraform plan`
+// against a copy of the workspace's state, within a sandboxed copy of each workspace touched by cloud-concierge. New
+// resources that fail validation are moved to a quarantine file, and their import blocks or tfmigrate import actions
+// are removed.
+func (h *hclCreate) ValidateGeneratedCode(workspaceToDirectory map[string]string) error {
+	if h.config.ValidateGeneratedCode != ValidateCodeValidation && h.config.ValidateGeneratedCode != PlanCodeValidation {
+		return nil
+	}
+
+	workspaces, err := touchedWorkspaces(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[touchedWorkspaces]%v", err)
+	}
+
+	if len(workspaces) == 0 {
+		return nil
+	}
+
+	err = os.RemoveAll(validationSandboxPath)
+	if err != nil {
+		return fmt.Errorf("[os.RemoveAll %v]%v", validationSandboxPath, err)
+	}
+
+	sandboxRepository := filepath.Join(validationSandboxPath, "repo")
+	err = copyDirectory("repo", sandboxRepository)
+	if err != nil {
+		return fmt.Errorf("[copyDirectory]%v", err)
+	}
+
+	planStatuses := []PlanStatus{}
+	for _, workspace := range workspaces {
+		status, err := h.validateWorkspace(workspace, workspaceToDirectory[workspace], sandboxRepository)
+		if err != nil {
+			return fmt.Errorf("[h.validateWorkspace %v]%v", workspace, err)
+		}
+		logrus.Infof("[validate_generated_code] workspace %v validate: %v, plan: %v", workspace, status.Validate, status.Plan)
+
+		planStatuses = append(planStatuses, status)
+	}
+
+	planStatusJSON, err := json.MarshalIndent(planStatuses, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%v", err)
+	}
+
+	return os.WriteFile(planStatusPath, planStatusJSON, 0o400)
+}
+
+// touchedWorkspaces returns the sorted names of workspaces whose configuration has been written to by cloud-concierge.
+func touchedWorkspaces(workspaceToDirectory map[string]string) ([]string, error) {
+	remediatedWorkspaces := map[string]bool{}
+	remediationsBytes, err := os.ReadFile(driftRemediationPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("[os.ReadFile] %v error: %v", driftRemediationPath, err)
+	}
+
+	if err == nil {
+		var remediations []DriftRemediation
+		err = json.Unmarshal(remediationsBytes, &remediations)
+		if err != nil {
+			return nil, fmt.Errorf("[json.Unmarshal] error unmarshalling `remediations`: %v", err)
+		}
+
+		for _, remediation := range remediations {
+			if remediation.Remediated {
+				remediatedWorkspaces[remediation.StateFileName] = true
+			}
+		}
+	}
+
+	workspaces := []string{}
+	for _, workspace := range sortedKeys(workspaceToDirectory) {
+		directory := fmt.Sprintf("repo%v", workspaceToDirectory[workspace])
+
+		touched := remediatedWorkspaces[workspace]
+		for _, path := range []string{"new-resources.tf", "cloud-concierge"} {
+			if _, err := os.Stat(filepath.Join(directory, path)); err == nil {
+				touched = true
+			}
+		}
+
+		if touched {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+
+	return workspaces, nil
+}
+
+// validateWorkspace validates the workspace's configuration within the sandbox, quarantining new resources that fail
+// validation, and plans the workspace when configured to.
+func (h *hclCreate) validateWorkspace(workspace string, directory string, sandboxRepository string) (PlanStatus, error) {
+	status := PlanStatus{Workspace: workspace, QuarantinedResources: []string{}}
+	repoDirectory := fmt.Sprintf("repo%v", directory)
+	sandboxDirectory := filepath.Join(sandboxRepository, directory)
+	binary := h.config.Runtime.Binary()
+
+	sandboxToOriginal, err := copyGeneratedFilesToSandbox(repoDirectory, sandboxDirectory)
+	if err != nil {
+		return status, fmt.Errorf("[copyGeneratedFilesToSandbox]%v", err)
+	}
+
+	_, err = executeCommandInDirectory(sandboxDirectory, binary, "init", "-backend=false", "-input=false", "-no-color")
+	if err != nil {
+		status.Validate = failedStatus
+		status.Message = fmt.Sprintf("terraform init failed: %v", commandErrorMessage(err))
+		return h.skipPlan(status), nil
+	}
+
+	for attempt := 1; attempt <= maxValidationAttempts; attempt++ {
+		validateJSON, _ := executeCommandInDirectory(sandboxDirectory, binary, "validate", "-json", "-no-color")
+
+		var output validateOutput
+		err = json.Unmarshal([]byte(validateJSON), &output)
+		if err != nil {
+			return status, fmt.Errorf("[json.Unmarshal] error unmarshalling terraform validate output: %v", err)
+		}
+
+		if output.Valid {
+			status.Validate = passedStatus
+			break
+		}
+
+		status.Validate = failedStatus
+		status.Message = firstErrorMessage(output.Diagnostics)
+
+		addressToMessage, err := diagnosticAddresses(repoDirectory, output.Diagnostics, sandboxToOriginal)
+		if err != nil {
+			return status, fmt.Errorf("[diagnosticAddresses]%v", err)
+		}
+
+		if len(addressToMessage) == 0 || attempt == maxValidationAttempts {
+			break
+		}
+
+		err = quarantineNewResources(repoDirectory, "validate", addressToMessage)
+		if err != nil {
+			return status, fmt.Errorf("[quarantineNewResources]%v", err)
+		}
+		status.QuarantinedResources = append(status.QuarantinedResources, sortedKeys(addressToMessage)...)
+
+		sandboxToOriginal, err = recopyWorkspaceToSandbox(repoDirectory, sandboxDirectory)
+		if err != nil {
+			return status, fmt.Errorf("[recopyWorkspaceToSandbox]%v", err)
+		}
+	}
+
+	if status.Validate == passedStatus {
+		status.Message = ""
+	}
+
+	if status.Validate != passedStatus {
+		return h.skipPlan(status), nil
+	}
+
+	if h.config.ValidateGeneratedCode != PlanCodeValidation {
+		return status, nil
+	}
+
+	for attempt := 1; attempt <= maxValidationAttempts; attempt++ {
+		result, err := planWorkspace(binary, workspace, repoDirectory, sandboxDirectory)
+		if err != nil {
+			return status, fmt.Errorf("[planWorkspace]%v", err)
+		}
+		status.Plan, status.Message = result.status, result.message
+
+		if len(result.changedNewResources) == 0 || attempt == maxValidationAttempts {
+			break
+		}
+
+		err = quarantineNewResources(repoDirectory, "plan", result.changedNewResources)
+		if err != nil {
+			return status, fmt.Errorf("[quarantineNewResources]%v", err)
+		}
+		status.QuarantinedResources = append(status.QuarantinedResources, sortedKeys(result.changedNewResources)...)
+
+		_, err = recopyWorkspaceToSandbox(repoDirectory, sandboxDirectory)
+		if err != nil {
+			return status, fmt.Errorf("[recopyWorkspaceToSandbox]%v", err)
+		}
+	}
+
+	return status, nil
+}
+
+// copyGeneratedFilesToSandbox copies the cloud-concierge import and removed block files of the workspace into the
+// root of the sandboxed workspace, where Terraform loads them. Returns a map between the name of each copy and the
+// path of its original relative to the workspace directory.
+func copyGeneratedFilesToSandbox(repoDirectory string, sandboxDirectory string) (map[string]string, error) {
+	sandboxToOriginal := map[string]string{}
+	for _, generatedDirectory := range []string{"imports", "removed"} {
+		generatedFiles, err := filepath.Glob(filepath.Join(repoDirectory, "cloud-concierge", generatedDirectory, "*.tf"))
+		if err != nil {
+			return nil, fmt.Errorf("[filepath.Glob]%v", err)
+		}
+
+		for _, generatedFile := range generatedFiles {
+			fileBytes, err := os.ReadFile(generatedFile)
+			if err != nil {
+				return nil, fmt.Errorf("[os.ReadFile %v]%v", generatedFile, err)
+			}
+
+			sandboxName := fmt.Sprintf("%v%v_%v", sandboxGeneratedFilePrefix, generatedDirectory, filepath.Base(generatedFile))
+			err = os.WriteFile(filepath.Join(sandboxDirectory, sandboxName), fileBytes, 0o600)
+			if err != nil {
+				return nil, fmt.Errorf("[os.WriteFile %v]%v", sandboxName, err)
+			}
+
+			sandboxToOriginal[sandboxName] = fmt.Sprintf("cloud-concierge/%v/%v", generatedDirectory, filepath.Base(generatedFile))
+		}
+	}
+
+	return sandboxToOriginal, nil
+}
+
+// recopyWorkspaceToSandbox copies the workspace into the sandbox again after new resources are quarantined, along
+// with its cloud-concierge import and removed block files.
+func recopyWorkspaceToSandbox(repoDirectory string, sandboxDirectory string) (map[string]string, error) {
+	err := copyDirectory(repoDirectory, sandboxDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("[copyDirectory]%v", err)
+	}
+
+	return copyGeneratedFilesToSandbox(repoDirectory, sandboxDirectory)
+}
+
+// skipPlan marks the plan as skipped when configured to plan.
+func (h *hclCreate) skipPlan(status PlanStatus) PlanStatus {
+	if h.config.ValidateGeneratedCode == PlanCodeValidation {
+		status.Plan = skippedStatus
+	}
+	return status
+}
+
+// planWorkspace plans the sandboxed workspace with the binary against a copy of its state using a local backend.
+// New resources imported by tfmigrate are planned as created, as tfmigrate imports them outside of the plan, so are
+// not checked.
+func planWorkspace(binary string, workspace string, repoDirectory string, sandboxDirectory string) (planResult, error) {
+	err := useLocalStateCopy(workspace, sandboxDirectory)
+	if err != nil {
+		return planResult{}, fmt.Errorf("[useLocalStateCopy]%v", err)
+	}
+
+	_, err = executeCommandInDirectory(sandboxDirectory, binary, "init", "-reconfigure", "-input=false", "-no-color")
+	if err != nil {
+		return planResult{status: failedStatus, message: fmt.Sprintf("terraform init failed: %v", commandErrorMessage(err))}, nil
+	}
+
+	_, err = executeCommandInDirectory(sandboxDirectory, binary, "plan", "-input=false", "-lock=false", "-no-color", "-out=cloud-concierge-validation.tfplan")
+	if err != nil {
+		return planResult{status: failedStatus, message: fmt.Sprintf("terraform plan failed: %v", commandErrorMessage(err))}, nil
+	}
+
+	showJSON, err := executeCommandInDirectory(sandboxDirectory, binary, "show", "-json", "cloud-concierge-validation.tfplan")
+	if err != nil {
+		return planResult{}, fmt.Errorf("[terraform show -json]%v", err)
+	}
+
+	var plan planOutput
+	err = json.Unmarshal([]byte(showJSON), &plan)
+	if err != nil {
+		return planResult{}, fmt.Errorf("[json.Unmarshal] error unmarshalling terraform show output: %v", err)
+	}
+
+	newResources, err := newResourceAddresses(repoDirectory)
+	if err != nil {
+		return planResult{}, fmt.Errorf("[newResourceAddresses]%v", err)
+	}
+
+	tfmigrateImports, err := tfmigrateImportAddresses(repoDirectory)
+	if err != nil {
+		return planResult{}, fmt.Errorf("[tfmigrateImportAddresses]%v", err)
+	}
+
+	return summarizePlan(plan, newResources, tfmigrateImports), nil
+}
+
+// summarizePlan returns the plan status of a workspace's plan, with the changes planned for new resources.
+func summarizePlan(plan planOutput, newResources map[string]bool, tfmigrateImports map[string]bool) planResult {
+	result := planResult{status: noChangesStatus, changedNewResources: map[string]string{}}
+
+	changedAddresses := []string{}
+	uncheckedImports := 0
+	for _, resourceChange := range plan.ResourceChanges {
+		actions := resourceChange.Change.Actions
+		if len(actions) == 1 && (actions[0] == "no-op" || actions[0] == "read") {
+			continue
+		}
+
+		if len(actions) == 1 && actions[0] == "create" && tfmigrateImports[resourceChange.Address] {
+			uncheckedImports++
+			continue
+		}
+
+		changedAddresses = append(changedAddresses, fmt.Sprintf("%v (%v)", resourceChange.Address, strings.Join(actions, ", ")))
+		if newResources[resourceChange.Address] {
+			result.changedNewResources[resourceChange.Address] = fmt.Sprintf("proposed %v", strings.Join(actions, ", "))
+		}
+	}
+
+	messages := []string{}
+	if len(changedAddresses) > 0 {
+		result.status = changesStatus
+		messages = append(messages, fmt.Sprintf("plan proposes changes to %v", strings.Join(changedAddresses, "; ")))
+	}
+	if uncheckedImports > 0 {
+		messages = append(messages, fmt.Sprintf("%d new resources imported by tfmigrate were not checked", uncheckedImports))
+	}
+	result.message = strings.Join(messages, ". ")
+
+	return result
+}
+
+// newResourceAddresses returns the addresses of the resource blocks within the workspace's new-resources.tf.
+func newResourceAddresses(directory string) (map[string]bool, error) {
+	addresses := map[string]bool{}
+	newResourcesFile, err := parseHCLFile(filepath.Join(directory, "new-resources.tf"))
+	if errors.Is(err, os.ErrNotExist) {
+		return addresses, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, block := range newResourcesFile.Body().Blocks() {
+		if block.Type() == "resource" && len(block.Labels()) == 2 {
+			addresses[fmt.Sprintf("%v.%v", block.Labels()[0], block.Labels()[1])] = true
+		}
+	}
+	return addresses, nil
+}
+
+// tfmigrateImportAddresses returns the addresses imported by the workspace's cloud-concierge tfmigrate migrations.
+func tfmigrateImportAddresses(directory string) (map[string]bool, error) {
+	addresses := map[string]bool{}
+	migrationFiles, err := filepath.Glob(filepath.Join(directory, "cloud-concierge", "tfmigrate", "*_migrations.hcl"))
+	if err != nil {
+		return nil, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	for _, migrationFile := range migrationFiles {
+		hclFile, err := parseHCLFile(migrationFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, block := range hclFile.Body().Blocks() {
+			actionsAttribute := block.Body().GetAttribute("actions")
+			if block.Type() != "migration" || actionsAttribute == nil {
+				continue
+			}
+
+			actions, ok := literalValue(actionsAttribute)
+			if !ok || !(actions.Type().IsListType() || actions.Type().IsTupleType()) {
+				continue
+			}
+
+			for _, action := range actions.AsValueSlice() {
+				fields := strings.Fields(action.AsString())
+				if len(fields) == 3 && fields[0] == "import" {
+					addresses[fields[1]] = true
+				}
+			}
+		}
+	}
+
+	return addresses, nil
+}
+
+// useLocalStateCopy copies the workspace's state into the sandboxed workspace directory and overrides the
+// workspace's backend with a local backend using that copy.
+func useLocalStateCopy(workspace string, sandboxDirectory string) error {
+	stateBytes, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", workspace))
+	if err != nil {
+		return fmt.Errorf("[os.ReadFile state_files/%v.json]%v", workspace, err)
+	}
+
+	err = os.WriteFile(filepath.Join(sandboxDirectory, "terraform.tfstate"), stateBytes, 0o600)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile terraform.tfstate]%v", err)
+	}
+
+	backendOverride := []byte("terraform {\n  backend \"local\" {\n    path = \"terraform.tfstate\"\n  }\n}\n")
+	err = os.WriteFile(filepath.Join(sandboxDirectory, "cloud_concierge_backend_override.tf"), backendOverride, 0o600)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile cloud_concierge_backend_override.tf]%v", err)
+	}
+
+	return nil
+}
+
+// diagnosticAddresses returns a map between the addresses of new resources and the error attributed to them. Errors
+// within new-resources.tf are attributed to the enclosing resource block, and errors within the sandboxed copies of
+// cloud-concierge import files, named within sandboxToOriginal, to the resource targeted by the enclosing import block
+// of the original file.
+func diagnosticAddresses(directory string, diagnostics []validateDiagnostic, sandboxToOriginal map[string]string) (map[string]string, error) {
+	addressToMessage := map[string]string{}
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != "error" || diagnostic.Range == nil {
+			continue
+		}
+
+		filename := filepath.ToSlash(diagnostic.Range.Filename)
+		if original, ok := sandboxToOriginal[filename]; ok && strings.HasPrefix(original, "cloud-concierge/imports/") {
+			filename = original
+		} else if filename != "new-resources.tf" {
+			continue
+		}
+
+		address, err := enclosingBlockAddress(filepath.Join(directory, filename), diagnostic.Range.Start.Line)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := addressToMessage[address]; address != "" && !ok {
+			addressToMessage[address] = diagnosticMessage(diagnostic)
+		}
+	}
+
+	return addressToMessage, nil
+}
+
+// enclosingBlockAddress returns the address of the resource block, or the target of the import block, containing
+// line within the file, and an empty string if neither contains it.
+func enclosingBlockAddress(path string, line int) (string, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("[os.ReadFile %v]%v", path, err)
+	}
+
+	file, diagnostics := hclsyntax.ParseConfig(fileBytes, path, hcl.Pos{Line: 1, Column: 1})
+	if diagnostics.HasErrors() {
+		return "", nil
+	}
+
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		blockRange := block.Range()
+		if line < blockRange.Start.Line || line > blockRange.End.Line {
+			continue
+		}
+
+		switch {
+		case block.Type == "resource" && len(block.Labels) == 2:
+			return fmt.Sprintf("%v.%v", block.Labels[0], block.Labels[1]), nil
+		case block.Type == "import":
+			if to, ok := block.Body.Attributes["to"]; ok {
+				return importTarget(to.Expr.Range().SliceBytes(fileBytes)), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// importTarget returns the resource address of an import block's `to` expression, written either as a reference or
+// a quoted string.
+func importTarget(expression []byte) string {
+	return strings.Trim(strings.TrimSpace(string(expression)), `"`)
+}
+
+// quarantineNewResources moves the resource blocks of new resources from new-resources.tf to the quarantine file,
+// annotated with the Terraform command that failed and its error, and removes their import blocks and tfmigrate import
+// actions.
+func quarantineNewResources(directory string, command string, addressToMessage map[string]string) error {
+	newResourcesPath := filepath.Join(directory, "new-resources.tf")
+	quarantined := []string{}
+
+	newResourcesFile, err := parseHCLFile(newResourcesPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if err == nil {
+		for _, block := range newResourcesFile.Body().Blocks() {
+			if block.Type() != "resource" || len(block.Labels()) != 2 {
+				continue
+			}
+
+			address := fmt.Sprintf("%v.%v", block.Labels()[0], block.Labels()[1])
+			message, ok := addressToMessage[address]
+			if !ok {
+				continue
+			}
+
+			quarantined = append(quarantined, fmt.Sprintf("# %v failed terraform %v: %v\n%v", address, command, message, strings.TrimLeft(string(block.BuildTokens(nil).Bytes()), "\n")))
+			newResourcesFile.Body().RemoveBlock(block)
+		}
+
+		err = os.WriteFile(newResourcesPath, formatRemainingBlocks(newResourcesFile), 0o400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile %v]%v", newResourcesPath, err)
+		}
+	}
+
+	err = removeImportBlocks(directory, addressToMessage)
+	if err != nil {
+		return fmt.Errorf("[removeImportBlocks]%v", err)
+	}
+
+	err = removeTFMigrateImportActions(directory, addressToMessage)
+	if err != nil {
+		return fmt.Errorf("[removeTFMigrateImportActions]%v", err)
+	}
+
+	if len(quarantined) == 0 {
+		return nil
+	}
+
+	outputPath := filepath.Join(directory, quarantinePath)
+	err = os.MkdirAll(filepath.Dir(outputPath), 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.MkdirAll] error making directory: %v", err)
+	}
+
+	existingBytes, err := os.ReadFile(outputPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[os.ReadFile %v]%v", outputPath, err)
+	}
+
+	quarantineBytes := append(existingBytes, []byte(strings.Join(quarantined, "\n"))...)
+	err = os.WriteFile(outputPath, quarantineBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile %v]%v", outputPath, err)
+	}
+
+	return nil
+}
+
+// removeImportBlocks removes the import blocks targeting any of the addresses from the cloud-concierge import files.
+func removeImportBlocks(directory string, addressToMessage map[string]string) error {
+	importFiles, err := filepath.Glob(filepath.Join(directory, "cloud-concierge", "imports", "*.tf"))
+	if err != nil {
+		return fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	for _, importFile := range importFiles {
+		hclFile, err := parseHCLFile(importFile)
+		if err != nil {
+			return err
+		}
+
+		removed := false
+		for _, block := range hclFile.Body().Blocks() {
+			to := block.Body().GetAttribute("to")
+			if block.Type() != "import" || to == nil {
+				continue
+			}
+
+			if _, ok := addressToMessage[importTarget(to.Expr().BuildTokens(nil).Bytes())]; ok {
+				hclFile.Body().RemoveBlock(block)
+				removed = true
+			}
+		}
+
+		if !removed {
+			continue
+		}
+
+		err = os.WriteFile(importFile, formatRemainingBlocks(hclFile), 0o400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile %v]%v", importFile, err)
+		}
+	}
+
+	return nil
+}
+
+// removeTFMigrateImportActions removes the import actions targeting any of the addresses from the cloud-concierge
+// tfmigrate migrations, deleting migrations left without actions.
+func removeTFMigrateImportActions(directory string, addressToMessage map[string]string) error {
+	migrationFiles, err := filepath.Glob(filepath.Join(directory, "cloud-concierge", "tfmigrate", "*_migrations.hcl"))
+	if err != nil {
+		return fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	for _, migrationFile := range migrationFiles {
+		hclFile, err := parseHCLFile(migrationFile)
+		if err != nil {
+			return err
+		}
+
+		removed := false
+		for _, block := range hclFile.Body().Blocks() {
+			actionsAttribute := block.Body().GetAttribute("actions")
+			if block.Type() != "migration" || actionsAttribute == nil {
+				continue
+			}
+
+			actions, ok := literalValue(actionsAttribute)
+			if !ok || !(actions.Type().IsListType() || actions.Type().IsTupleType()) {
+				continue
+			}
+
+			remainingActions := []cty.Value{}
+			for _, action := range actions.AsValueSlice() {
+				fields := strings.Fields(action.AsString())
+				if len(fields) == 3 && fields[0] == "import" {
+					if _, ok := addressToMessage[fields[1]]; ok {
+						removed = true
+						continue
+					}
+				}
+				remainingActions = append(remainingActions, action)
+			}
+
+			if len(remainingActions) == 0 {
+				hclFile.Body().RemoveBlock(block)
+			} else if len(remainingActions) < actions.LengthInt() {
+				block.Body().SetAttributeValue("actions", cty.ListVal(remainingActions))
+			}
+		}
+
+		if !removed {
+			continue
+		}
+
+		fileBytes := formatRemainingBlocks(hclFile)
+		if len(fileBytes) == 0 {
+			err = os.Remove(migrationFile)
+			if err != nil {
+				return fmt.Errorf("[os.Remove %v]%v", migrationFile, err)
+			}
+			continue
+		}
+
+		err = os.WriteFile(migrationFile, fileBytes, 0o400)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile %v]%v", migrationFile, err)
+		}
+	}
+
+	return nil
+}
+
+// formatRemainingBlocks returns the formatted contents of a file from which blocks have been removed, without the
+// blank lines left around them.
+func formatRemainingBlocks(hclFile *hclwrite.File) []byte {
+	fileBytes := bytes.TrimSpace(hclwrite.Format(hclFile.Bytes()))
+	if len(fileBytes) == 0 {
+		return fileBytes
+	}
+	return append(fileBytes, '\n')
+}
+
+// firstErrorMessage returns the message of the first error diagnostic.
+func firstErrorMessage(diagnostics []validateDiagnostic) string {
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == "error" {
+			return diagnosticMessage(diagnostic)
+		}
+	}
+	return ""
+}
+
+// diagnosticMessage returns the summary of a diagnostic, followed by the first line of its detail.
+func diagnosticMessage(diagnostic validateDiagnostic) string {
+	if diagnostic.Detail == "" {
+		return diagnostic.Summary
+	}
+	return fmt.Sprintf("%v: %v", diagnostic.Summary, firstLine(diagnostic.Detail))
+}
+
+// firstLine returns the first non-empty line of text.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) != "" {
+			return strings.TrimSpace(line)
+		}
+	}
+	return ""
+}
+
+// commandErrorMessage returns the first error reported by a failed Terraform command, or the first line of the error
+// when Terraform did not report one.
+func commandErrorMessage(err error) string {
+	for _, line := range strings.Split(err.Error(), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "Error: ") {
+			return strings.TrimPrefix(strings.TrimSpace(line), "Error: ")
+		}
+	}
+	return firstLine(err.Error())
+}
+
+// copyDirectory recursively copies the files within source to destination, overwriting existing files and skipping
+// git metadata and Terraform working directories.
+func copyDirectory(source string, destination string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(destination, relativePath)
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" || entry.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(destinationPath, 0o700)
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		fileBytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(destinationPath, fileBytes, 0o600)
+	})
+}
+
+// executeCommandInDirectory wraps os.exec.Command with capturing of std output and errors, running the
+// command within the specified directory. Std output is returned even when the command fails.
+func executeCommandInDirectory(directory string, command string, args ...string) (string, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Dir = directory
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return out.String(), fmt.Errorf("%v\n\n%v", err, stderr.String()+out.String())
+	}
+	return out.String(), nil
+}
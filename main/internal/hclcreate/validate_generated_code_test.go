@@ -0,0 +1,262 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGeneratedCode(t *testing.T) string {
+	directory := filepath.Join("repo", "dev")
+	require.NoError(t, os.MkdirAll(filepath.Join(directory, "cloud-concierge", "imports"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "new-resources.tf"), []byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "assets" {
+  bucket = "assets"
+  acl    = "private"
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "cloud-concierge", "imports", "id_imports.tf"), []byte(`import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+import {
+  to = "aws_s3_bucket.assets"
+  id = "assets"
+}
+`), 0o600))
+	return directory
+}
+
+func TestDiagnosticAddresses(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	directory := writeGeneratedCode(t)
+	sandboxDirectory := filepath.Join(validationSandboxPath, "repo", "dev")
+	require.NoError(t, copyDirectory("repo", filepath.Join(validationSandboxPath, "repo")))
+
+	sandboxToOriginal, err := copyGeneratedFilesToSandbox(directory, sandboxDirectory)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"cloud_concierge_imports_id_imports.tf": "cloud-concierge/imports/id_imports.tf"}, sandboxToOriginal)
+
+	sandboxImports, err := os.ReadFile(filepath.Join(sandboxDirectory, "cloud_concierge_imports_id_imports.tf"))
+	require.NoError(t, err)
+	originalImports, err := os.ReadFile(filepath.Join(directory, "cloud-concierge", "imports", "id_imports.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, originalImports, sandboxImports)
+
+	// terraform validate -json output, with filenames relative to the sandboxed workspace directory.
+	validateJSON := `{
+  "format_version": "1.0",
+  "valid": false,
+  "error_count": 3,
+  "warning_count": 1,
+  "diagnostics": [
+    {
+      "severity": "error",
+      "summary": "Unsupported argument",
+      "detail": "An argument named \"acl\" is not expected here.",
+      "range": {
+        "filename": "new-resources.tf",
+        "start": {"line": 7, "column": 3, "byte": 101},
+        "end": {"line": 7, "column": 6, "byte": 104}
+      },
+      "snippet": {
+        "context": "resource \"aws_s3_bucket\" \"assets\"",
+        "code": "  acl    = \"private\"",
+        "start_line": 7,
+        "highlight_start_offset": 2,
+        "highlight_end_offset": 5,
+        "values": []
+      }
+    },
+    {
+      "severity": "error",
+      "summary": "Invalid import id argument",
+      "detail": "The import ID cannot be null.",
+      "range": {
+        "filename": "cloud_concierge_imports_id_imports.tf",
+        "start": {"line": 3, "column": 8, "byte": 40},
+        "end": {"line": 3, "column": 14, "byte": 46}
+      }
+    },
+    {
+      "severity": "warning",
+      "summary": "Argument is deprecated",
+      "detail": "Use the aws_s3_bucket_acl resource instead",
+      "range": {
+        "filename": "new-resources.tf",
+        "start": {"line": 2, "column": 3, "byte": 36},
+        "end": {"line": 2, "column": 9, "byte": 42}
+      }
+    },
+    {
+      "severity": "error",
+      "summary": "Missing required argument",
+      "detail": "The argument \"region\" is required, but was not set.",
+      "range": {
+        "filename": "main.tf",
+        "start": {"line": 1, "column": 1, "byte": 0},
+        "end": {"line": 1, "column": 15, "byte": 14}
+      }
+    }
+  ]
+}`
+	var output validateOutput
+	require.NoError(t, json.Unmarshal([]byte(validateJSON), &output))
+
+	// When
+	addressToMessage, err := diagnosticAddresses(directory, output.Diagnostics, sandboxToOriginal)
+
+	// Then
+	require.NoError(t, err)
+	assert.False(t, output.Valid)
+	assert.Equal(t, map[string]string{
+		"aws_s3_bucket.assets": "Unsupported argument: An argument named \"acl\" is not expected here.",
+		"aws_s3_bucket.logs":   "Invalid import id argument: The import ID cannot be null.",
+	}, addressToMessage)
+}
+
+func TestSummarizePlan(t *testing.T) {
+	// Given
+	showJSON := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "name": "logs", "change": {"actions": ["no-op"], "importing": {"id": "logs"}}},
+    {"address": "aws_s3_bucket.assets", "type": "aws_s3_bucket", "name": "assets", "change": {"actions": ["update"], "importing": {"id": "assets"}}},
+    {"address": "aws_instance.web", "type": "aws_instance", "name": "web", "change": {"actions": ["create"]}},
+    {"address": "aws_vpc.main", "type": "aws_vpc", "name": "main", "change": {"actions": ["update"]}}
+  ]
+}`
+	var plan planOutput
+	require.NoError(t, json.Unmarshal([]byte(showJSON), &plan))
+
+	newResources := map[string]bool{"aws_s3_bucket.logs": true, "aws_s3_bucket.assets": true, "aws_instance.web": true}
+	tfmigrateImports := map[string]bool{"aws_instance.web": true}
+
+	// When
+	result := summarizePlan(plan, newResources, tfmigrateImports)
+
+	// Then
+	assert.Equal(t, planResult{
+		status: changesStatus,
+		message: "plan proposes changes to aws_s3_bucket.assets (update); aws_vpc.main (update). " +
+			"1 new resources imported by tfmigrate were not checked",
+		changedNewResources: map[string]string{"aws_s3_bucket.assets": "proposed update"},
+	}, result)
+}
+
+func TestQuarantineNewResources(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	directory := writeGeneratedCode(t)
+
+	// When
+	err = quarantineNewResources(directory, "validate", map[string]string{"aws_s3_bucket.assets": "Unsupported argument"})
+
+	// Then
+	require.NoError(t, err)
+
+	newResources, err := os.ReadFile(filepath.Join(directory, "new-resources.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`, string(newResources))
+
+	imports, err := os.ReadFile(filepath.Join(directory, "cloud-concierge", "imports", "id_imports.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+`, string(imports))
+
+	quarantined, err := os.ReadFile(filepath.Join(directory, quarantinePath))
+	require.NoError(t, err)
+	assert.Equal(t, `# aws_s3_bucket.assets failed terraform validate: Unsupported argument
+resource "aws_s3_bucket" "assets" {
+  bucket = "assets"
+  acl    = "private"
+}
+`, string(quarantined))
+}
+
+func TestQuarantineNewResources_TFMigrate(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	directory := writeGeneratedCode(t)
+	migrationsDirectory := filepath.Join(directory, "cloud-concierge", "tfmigrate")
+	require.NoError(t, os.MkdirAll(migrationsDirectory, 0o700))
+
+	err = os.WriteFile(filepath.Join(migrationsDirectory, "abc_migrations.hcl"), []byte(`migration "state" "import" {
+  dir       = "/github/workspace/dev/"
+  workspace = "dev"
+  actions   = ["import aws_s3_bucket.assets assets", "import aws_s3_bucket.logs logs"]
+}
+`), 0o600)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(migrationsDirectory, "def_migrations.hcl"), []byte(`migration "state" "import" {
+  dir       = "/github/workspace/dev/"
+  workspace = "dev"
+  actions   = ["import aws_s3_bucket.assets assets"]
+}
+`), 0o600)
+	require.NoError(t, err)
+
+	// When
+	err = quarantineNewResources(directory, "validate", map[string]string{"aws_s3_bucket.assets": "Unsupported argument"})
+
+	// Then
+	require.NoError(t, err)
+
+	migrations, err := os.ReadFile(filepath.Join(migrationsDirectory, "abc_migrations.hcl"))
+	require.NoError(t, err)
+	assert.Equal(t, `migration "state" "import" {
+  dir       = "/github/workspace/dev/"
+  workspace = "dev"
+  actions   = ["import aws_s3_bucket.logs logs"]
+}
+`, string(migrations))
+
+	_, err = os.Stat(filepath.Join(migrationsDirectory, "def_migrations.hcl"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestValidateGeneratedCode_None(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	writeGeneratedCode(t)
+	h := hclCreate{config: Config{ValidateGeneratedCode: NoCodeValidation}}
+
+	// When
+	err = h.ValidateGeneratedCode(map[string]string{"dev": "/dev/"})
+
+	// Then
+	require.NoError(t, err)
+	_, err = os.Stat(planStatusPath)
+	assert.True(t, os.IsNotExist(err))
+}
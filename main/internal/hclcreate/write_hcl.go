@@ -9,16 +9,18 @@ import (
 )
 
 // CreateMainTF outputs a bytes slice which defines a baseline main.tf file.
-func (h *hclCreate) CreateMainTF(providers map[string]string) ([]byte, error) {
-	logrus.Debugf("[hclcreate][write_hcl] CreateMainTF: %v", providers)
+func (h *hclCreate) CreateMainTF(terraformVersion string, providers map[string]string) ([]byte, error) {
+	logrus.Debugf("[hclcreate][write_hcl] CreateMainTF: %v, %v", terraformVersion, providers)
 
 	f := hclwrite.NewEmptyFile()
 	rootBody := f.Body()
 
 	terraformBlock := rootBody.AppendNewBlock("terraform", nil)
 	terraformBody := terraformBlock.Body()
-	terraformBody.SetAttributeValue("required_version", cty.StringVal(h.config.TerraformVersion))
-	terraformBody.AppendNewline()
+	if terraformVersion != "" {
+		terraformBody.SetAttributeValue("required_version", cty.StringVal(terraformVersion))
+		terraformBody.AppendNewline()
+	}
 
 	requiredProvidersBlock := terraformBody.AppendNewBlock("required_providers", nil)
 	requiredProvidersBody := requiredProvidersBlock.Body()
@@ -42,10 +44,14 @@ func (h *hclCreate) CreateMainTF(providers map[string]string) ([]byte, error) {
 // requiredProviderSubBlock creates a sub-chunk of hcl within the passed body for a required provider
 // and version.
 func requiredProviderSubBlock(body *hclwrite.Body, provider string, version string) error {
-	body.SetAttributeValue(string(provider), cty.ObjectVal(map[string]cty.Value{
-		"source":  cty.StringVal(fmt.Sprintf("hashicorp/%v", string(provider))),
-		"version": cty.StringVal(string(version)),
-	}))
+	requirement := map[string]cty.Value{
+		"source": cty.StringVal(fmt.Sprintf("hashicorp/%v", string(provider))),
+	}
+	if version != "" {
+		requirement["version"] = cty.StringVal(string(version))
+	}
+
+	body.SetAttributeValue(string(provider), cty.ObjectVal(requirement))
 	body.AppendNewline()
 
 	return nil
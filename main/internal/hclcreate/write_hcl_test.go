@@ -16,13 +16,8 @@ func TestCreateMainTF(t *testing.T) {
 		"\n    tfe = {\n      source  = \"hashicorp/tfe\"\n      version = \"~>0.33.0\"\n    }\n\n" +
 		"    google = {\n      source  = \"hashicorp/google\"\n      version = \"~>4.27.0\"\n    }\n\n  }\n}\n"
 
-	hclCreate, _ := NewHCLCreate(
-		Config{
-			TerraformVersion: "~>1.2.4",
-		},
-		"",
-	)
-	f, err := hclCreate.CreateMainTF(inputProvidersMap)
+	hclCreate, _ := NewHCLCreate(Config{}, "")
+	f, err := hclCreate.CreateMainTF("~>1.2.4", inputProvidersMap)
 	if err != nil {
 		t.Errorf("unexpected error in createMainTF: %v", err)
 	}
@@ -94,15 +94,27 @@ func (h *hclCreate) generateImportBlockFile(
 			currentResource := h.resourceToIdentifierStruct(resource)
 			resourceID := fmt.Sprintf("%v.%v", currentResource.resourceType, currentResource.resourceName)
 			currentImportDataPair := resourceToImportLocation[resourceID]
-			fBody = h.hclImportBlock(fBody, currentImportDataPair)
+			currentImportDataPair.TerraformConfigLocation = h.resourceAddress(currentResource)
+			fBody = h.hclImportBlock(fBody, currentImportDataPair, h.resourceImportProvider(resource))
 		}
 	}
 
 	return f.Bytes(), nil
 }
 
-// hclImportBlock writes an import block to the passed-in hclwrite body.
-func (h *hclCreate) hclImportBlock(body *hclwrite.Body, importDataPair ImportDataPair) *hclwrite.Body {
+// resourceImportProvider returns the aliased provider configuration used to import a resource. Resources placed
+// within a module receive their provider configuration through the module call instead.
+func (h *hclCreate) resourceImportProvider(resource string) string {
+	generatedName := h.resourceNames[resource]
+	if strings.HasPrefix(generatedName.Address, "module.") {
+		return ""
+	}
+	return generatedName.Provider
+}
+
+// hclImportBlock writes an import block to the passed-in hclwrite body, using the aliased provider configuration
+// when provider is not empty.
+func (h *hclCreate) hclImportBlock(body *hclwrite.Body, importDataPair ImportDataPair, provider string) *hclwrite.Body {
 	importBlock := body.AppendNewBlock(
 		"import", nil)
 	importBlock.Body().SetAttributeValue(
@@ -110,5 +122,8 @@ func (h *hclCreate) hclImportBlock(body *hclwrite.Body, importDataPair ImportDat
 		cty.StringVal(strings.Replace(importDataPair.TerraformConfigLocation, "tfer--", "", -1)),
 	)
 	importBlock.Body().SetAttributeValue("id", cty.StringVal(importDataPair.RemoteCloudReference))
+	if provider != "" {
+		importBlock.Body().SetAttributeRaw("provider", hclwrite.TokensForIdentifier(provider))
+	}
 	return body
 }
@@ -0,0 +1,227 @@
+package hclcreate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// WorkspaceToRemovedAddresses is a map between workspace names and the resource addresses that should be removed
+// from that workspace's state without destroying the underlying cloud resource.
+type WorkspaceToRemovedAddresses map[string][]string
+
+// CreateDuplicatedResourceRemovals creates either removed blocks or tfmigrate state rm migrations to resolve
+// resources managed by more than one state file.
+func (h *hclCreate) CreateDuplicatedResourceRemovals(uniqueID string, workspaceToDirectory map[string]string) error {
+	duplicatedBytes, err := os.ReadFile("outputs/drift-resources-duplicated.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("[os.ReadFile] outputs/drift-resources-duplicated.json error: %v", err)
+	}
+
+	var duplicated []driftDetector.DuplicatedResource
+	err = json.Unmarshal(duplicatedBytes, &duplicated)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `duplicated`: %v", err)
+	}
+
+	workspaceToRemovedAddresses := WorkspaceToRemovedAddresses{}
+	for _, resource := range duplicated {
+		for _, claim := range resource.Claims {
+			if claim.ShouldResolve {
+				workspace := string(claim.StateFileName)
+				workspaceToRemovedAddresses[workspace] = append(workspaceToRemovedAddresses[workspace], claim.Address)
+			}
+		}
+	}
+
+	return h.createStateRemovals(uniqueID, "duplicated", workspaceToRemovedAddresses, workspaceToDirectory)
+}
+
+// CreateDeletedResourceRemovals creates either removed blocks or tfmigrate state rm migrations for resources
+// whose cloud resource no longer exists, and handles their now-orphaned resource blocks.
+func (h *hclCreate) CreateDeletedResourceRemovals(uniqueID string, workspaceToDirectory map[string]string) error {
+	deletedBytes, err := os.ReadFile("outputs/drift-resources-deleted.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("[os.ReadFile] outputs/drift-resources-deleted.json error: %v", err)
+	}
+
+	var deleted []driftDetector.DeletedResource
+	err = json.Unmarshal(deletedBytes, &deleted)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal] error unmarshalling `deleted`: %v", err)
+	}
+
+	workspaceToRemovedAddresses := WorkspaceToRemovedAddresses{}
+	workspaceToOrphanedResources := map[string][]string{}
+	for _, resource := range deleted {
+		workspace := string(resource.StateFileName)
+		address := fmt.Sprintf("%v.%v", resource.ResourceType, resource.ResourceName)
+		if resource.ModuleName != "" {
+			address = fmt.Sprintf("%v.%v", resource.ModuleName, address)
+		} else {
+			workspaceToOrphanedResources[workspace] = append(workspaceToOrphanedResources[workspace], address)
+		}
+		workspaceToRemovedAddresses[workspace] = append(workspaceToRemovedAddresses[workspace], address)
+	}
+
+	err = h.createStateRemovals(uniqueID, "deleted", workspaceToRemovedAddresses, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[h.createStateRemovals]%v", err)
+	}
+
+	err = h.updateOrphanedResourceBlocks(workspaceToOrphanedResources, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[h.updateOrphanedResourceBlocks]%v", err)
+	}
+
+	return nil
+}
+
+// createStateRemovals writes removed blocks for workspaces whose Terraform version supports them, and tfmigrate
+// state rm migrations otherwise.
+func (h *hclCreate) createStateRemovals(
+	uniqueID string,
+	removalName string,
+	workspaceToRemovedAddresses WorkspaceToRemovedAddresses,
+	workspaceToDirectory map[string]string,
+) error {
+	for workspace, directory := range workspaceToDirectory {
+		addresses := workspaceToRemovedAddresses[workspace]
+		if len(addresses) == 0 {
+			continue
+		}
+		sort.Strings(addresses)
+
+		version, err := h.workspaceTerraformVersion(directory)
+		if err != nil {
+			return fmt.Errorf("[h.workspaceTerraformVersion]%v", err)
+		}
+
+		if version.Supports(RemovedBlocksCapability) {
+			err = h.writeRemovedBlocks(uniqueID, removalName, directory, addresses)
+			if err != nil {
+				return fmt.Errorf("[h.writeRemovedBlocks]%v", err)
+			}
+			continue
+		}
+
+		err = h.writeTFMigrateStateRemovals(uniqueID, removalName, workspace, directory, addresses)
+		if err != nil {
+			return fmt.Errorf("[h.writeTFMigrateStateRemovals]%v", err)
+		}
+	}
+
+	return nil
+}
+
+// writeRemovedBlocks writes a .tf file of removed blocks for the specified workspace directory.
+func (h *hclCreate) writeRemovedBlocks(uniqueID string, removalName string, directory string, addresses []string) error {
+	removedFileBytes, err := h.generateRemovedBlockFile(addresses)
+	if err != nil {
+		return fmt.Errorf("[h.generateRemovedBlockFile]%v", err)
+	}
+
+	err = os.MkdirAll(fmt.Sprintf("repo%vcloud-concierge/removed", directory), 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.MkdirAll] error making directory: %v", err)
+	}
+
+	outputPath := fmt.Sprintf("repo%vcloud-concierge/removed/%v_%v_removed.tf", directory, uniqueID, removalName)
+	err = os.WriteFile(outputPath, removedFileBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+	}
+
+	return nil
+}
+
+// generateRemovedBlockFile generates a .tf file containing removed blocks which drop each address from state
+// without destroying the cloud resource. Removed blocks cannot target individual resource instances, so instance
+// addresses are written as comments with the equivalent `terraform state rm` command.
+func (h *hclCreate) generateRemovedBlockFile(addresses []string) ([]byte, error) {
+	f := hclwrite.NewEmptyFile()
+	fBody := f.Body()
+
+	for _, address := range addresses {
+		if strings.HasSuffix(address, "]") {
+			fBody.AppendUnstructuredTokens(hclwrite.Tokens{
+				{
+					Type:  hclsyntax.TokenComment,
+					Bytes: []byte(fmt.Sprintf("# %v is a resource instance, remove it with: terraform state rm '%v'\n", address, address)),
+				},
+			})
+			continue
+		}
+
+		traversal, diagnostics := hclsyntax.ParseTraversalAbs([]byte(address), "", hcl.Pos{Line: 1, Column: 1})
+		if diagnostics.HasErrors() {
+			return nil, fmt.Errorf("[hclsyntax.ParseTraversalAbs] invalid address %v: %v", address, diagnostics.Error())
+		}
+
+		removedBlock := fBody.AppendNewBlock("removed", nil)
+		removedBlock.Body().SetAttributeTraversal("from", traversal)
+
+		lifecycleBlock := removedBlock.Body().AppendNewBlock("lifecycle", nil)
+		lifecycleBlock.Body().SetAttributeValue("destroy", cty.False)
+	}
+
+	return f.Bytes(), nil
+}
+
+// writeTFMigrateStateRemovals writes a tfmigrate migration of state rm actions for the specified workspace,
+// creating the workspace's tfmigrate configuration if it does not yet exist.
+func (h *hclCreate) writeTFMigrateStateRemovals(uniqueID string, removalName string, workspace string, directory string, addresses []string) error {
+	configPath := fmt.Sprintf("repo%vcloud-concierge/tfmigrate/.tfmigrate.hcl", directory)
+	if _, err := os.Stat(configPath); errors.Is(err, os.ErrNotExist) {
+		err = h.CreateTFMigrateConfiguration(map[string]string{workspace: directory})
+		if err != nil {
+			return fmt.Errorf("[h.CreateTFMigrateConfiguration] %v", err)
+		}
+	}
+
+	migrationFileBytes := h.individualTFMigrateStateRemoval(directory, workspace, removalName, addresses)
+
+	outputPath := fmt.Sprintf("repo%vcloud-concierge/tfmigrate/%v_%v_removals.hcl", directory, uniqueID, removalName)
+	err := os.WriteFile(outputPath, migrationFileBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile] Error writing %v: %v", outputPath, err)
+	}
+
+	return nil
+}
+
+// individualTFMigrateStateRemoval creates a tfmigrate migration removing each address from the workspace's state.
+func (h *hclCreate) individualTFMigrateStateRemoval(directory string, workspace string, removalName string, addresses []string) []byte {
+	f := hclwrite.NewEmptyFile()
+	fBody := f.Body()
+
+	migrationBlock := fBody.AppendNewBlock("migration", []string{"state", fmt.Sprintf("remove_%v", removalName)})
+	migrationBlockBody := migrationBlock.Body()
+
+	dirVal := fmt.Sprintf("/github/workspace%v", directory)
+	migrationBlockBody.SetAttributeValue("dir", cty.StringVal(dirVal))
+	migrationBlockBody.SetAttributeValue("workspace", cty.StringVal(workspace))
+
+	var removeStatementSlice []cty.Value
+	for _, address := range addresses {
+		removeStatementSlice = append(removeStatementSlice, cty.StringVal(fmt.Sprintf("rm '%v'", address)))
+	}
+
+	migrationBlockBody.SetAttributeValue("actions", cty.ListVal(removeStatementSlice))
+
+	return f.Bytes()
+}
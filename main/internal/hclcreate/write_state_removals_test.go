@@ -0,0 +1,190 @@
+package hclcreate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRemovedBlockFile(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	addresses := []string{
+		"aws_s3_bucket.logs",
+		"module.storage.aws_s3_bucket.archive",
+		`aws_s3_bucket.buckets["logs"]`,
+	}
+
+	expectedOutput := `removed {
+  from = aws_s3_bucket.logs
+  lifecycle {
+    destroy = false
+  }
+}
+removed {
+  from = module.storage.aws_s3_bucket.archive
+  lifecycle {
+    destroy = false
+  }
+}
+# aws_s3_bucket.buckets["logs"] is a resource instance, remove it with: terraform state rm 'aws_s3_bucket.buckets["logs"]'
+`
+
+	// When
+	output, err := h.generateRemovedBlockFile(addresses)
+	if err != nil {
+		t.Errorf("unexpected error in h.generateRemovedBlockFile: %v", err)
+	}
+
+	// Then
+	if string(output) != expectedOutput {
+		t.Errorf("expected:\n%v\ngot:\n%v", expectedOutput, string(output))
+	}
+}
+
+func TestIndividualTFMigrateStateRemoval(t *testing.T) {
+	// Given
+	h := hclCreate{}
+	addresses := []string{"aws_s3_bucket.logs", `aws_s3_bucket.buckets["logs"]`}
+
+	expectedOutput := `migration "state" "remove_duplicated" {
+  dir       = "/github/workspace/dev/"
+  workspace = "dev"
+  actions   = ["rm 'aws_s3_bucket.logs'", "rm 'aws_s3_bucket.buckets[\"logs\"]'"]
+}
+`
+
+	// When
+	output := h.individualTFMigrateStateRemoval("/dev/", "dev", "duplicated", addresses)
+
+	// Then
+	if string(output) != expectedOutput {
+		t.Errorf("expected:\n%v\ngot:\n%v", expectedOutput, string(output))
+	}
+}
+
+func TestCreateDeletedResourceRemovals(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	directory := filepath.Join("repo", "dev")
+	require.NoError(t, os.MkdirAll(directory, 0o700))
+	require.NoError(t, os.MkdirAll("outputs", 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "main.tf"), []byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "archive" {
+  bucket = "archive"
+}
+`), 0o600))
+	require.NoError(t, os.WriteFile("outputs/drift-resources-deleted.json", []byte(`[
+  {"InstanceID": "logs", "StateFileName": "dev", "ModuleName": "", "ResourceType": "aws_s3_bucket", "ResourceName": "logs"},
+  {"InstanceID": "data", "StateFileName": "dev", "ModuleName": "module.storage", "ResourceType": "aws_s3_bucket", "ResourceName": "this"}
+]`), 0o600))
+
+	h := hclCreate{config: Config{TerraformVersion: "1.7.0", OrphanedResourceBlocks: CommentOrphanedResourceBlocks}}
+
+	// When
+	err = h.CreateDeletedResourceRemovals("abc", map[string]string{"dev": "/dev/"})
+
+	// Then
+	require.NoError(t, err)
+
+	removedBlocks, err := os.ReadFile(filepath.Join(directory, "cloud-concierge", "removed", "abc_deleted_removed.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `removed {
+  from = aws_s3_bucket.logs
+  lifecycle {
+    destroy = false
+  }
+}
+removed {
+  from = module.storage.aws_s3_bucket.this
+  lifecycle {
+    destroy = false
+  }
+}
+`, string(removedBlocks))
+
+	configuration, err := os.ReadFile(filepath.Join(directory, "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `# The cloud resource below no longer exists and has been removed from state by cloud-concierge.
+# resource "aws_s3_bucket" "logs" {
+#   bucket = "logs"
+# }
+
+resource "aws_s3_bucket" "archive" {
+  bucket = "archive"
+}
+`, string(configuration))
+}
+
+func TestRemoveOrphanedBlocks_Delete(t *testing.T) {
+	// Given
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "archive" {
+  bucket = "archive"
+}
+`), "main.tf", hcl.Pos{Line: 1, Column: 1})
+	require.False(t, diagnostics.HasErrors())
+
+	// When
+	output := removeOrphanedBlocks(hclFile, hclFile.Body().Blocks()[:1], false)
+
+	// Then
+	assert.Equal(t, `resource "aws_s3_bucket" "archive" {
+  bucket = "archive"
+}
+`, string(output))
+}
+
+func TestRemoveOrphanedBlocks_Comment(t *testing.T) {
+	// Given
+	hclFile, diagnostics := hclwrite.ParseConfig([]byte(`# Application logs, retained for 90 days.
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs" # shared with analytics
+}
+
+resource "aws_s3_bucket" "archive" {
+  bucket = "archive"
+}
+
+resource "aws_s3_bucket" "backup" {
+  bucket = "backup"
+}
+`), "main.tf", hcl.Pos{Line: 1, Column: 1})
+	require.False(t, diagnostics.HasErrors())
+	blocks := hclFile.Body().Blocks()
+
+	// When
+	output := removeOrphanedBlocks(hclFile, []*hclwrite.Block{blocks[0], blocks[2]}, true)
+
+	// Then
+	assert.Equal(t, `# The cloud resource below no longer exists and has been removed from state by cloud-concierge.
+# # Application logs, retained for 90 days.
+# resource "aws_s3_bucket" "logs" {
+#   bucket = "logs" # shared with analytics
+# }
+
+resource "aws_s3_bucket" "archive" {
+  bucket = "archive"
+}
+
+# The cloud resource below no longer exists and has been removed from state by cloud-concierge.
+# resource "aws_s3_bucket" "backup" {
+#   bucket = "backup"
+# }
+`, string(output))
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
@@ -68,7 +69,7 @@ func (h *hclCreate) CreateTFMigrateConfiguration(workspaceToDirectory map[string
 
 		newFilePath := fmt.Sprintf("repo%vcloud-concierge/tfmigrate/.tfmigrate.hcl", directory)
 
-		currentTfMigrateConfig, err := h.individualTFMigrateConfig(workspace)
+		currentTfMigrateConfig, err := h.individualTFMigrateConfig(workspace, directory)
 		if err != nil {
 			return fmt.Errorf("[h.individualTFMigrateConfig] %v", err)
 		}
@@ -82,8 +83,22 @@ func (h *hclCreate) CreateTFMigrateConfiguration(workspaceToDirectory map[string
 	return nil
 }
 
-// individualTFMigrateConfig creates the []byte representing a tf migrate configuration for an individual
-func (h *hclCreate) individualTFMigrateConfig(workspace string) ([]byte, error) {
+// individualTFMigrateConfig creates the []byte representing a tf migrate configuration for an individual workspace,
+// inferring the history storage from the workspace's backend when no storage type is configured.
+func (h *hclCreate) individualTFMigrateConfig(workspace string, directory string) ([]byte, error) {
+	backend, err := loadBackend(fmt.Sprintf("repo%v", directory))
+	if err != nil {
+		return nil, fmt.Errorf("[loadBackend]%v", err)
+	}
+
+	migrationHistory := h.config.MigrationHistoryStorage
+	if migrationHistory.StorageType == "" {
+		migrationHistory, err = inferMigrationHistory(backend)
+		if err != nil {
+			return nil, fmt.Errorf("[inferMigrationHistory]%v", err)
+		}
+	}
+
 	f := hclwrite.NewEmptyFile()
 	body := f.Body()
 
@@ -92,12 +107,14 @@ func (h *hclCreate) individualTFMigrateConfig(workspace string) ([]byte, error)
 	tfmigrateBlockBody := tfmigrateBlock.Body()
 
 	tfmigrateBlockBody.SetAttributeValue("migration_dir", cty.StringVal("./cloud-concierge/tfmigrate/"))
-	tfmigrateBlockBody.SetAttributeValue("is_backend_terraform_cloud", cty.BoolVal(true))
+	if backend.backendType == "remote" || backend.backendType == "cloud" {
+		tfmigrateBlockBody.SetAttributeValue("is_backend_terraform_cloud", cty.BoolVal(true))
+	}
 
 	historyBlock := tfmigrateBlockBody.AppendNewBlock("history", nil)
 	historyBlockBody := historyBlock.Body()
 
-	storageType := h.config.MigrationHistoryStorage.StorageType
+	storageType := migrationHistory.StorageType
 	storageBlock := historyBlockBody.AppendNewBlock(
 		"storage", []string{storageType},
 	)
@@ -107,19 +124,103 @@ func (h *hclCreate) individualTFMigrateConfig(workspace string) ([]byte, error)
 
 	switch storageType {
 	case "gcs":
-		storageBlockBody.SetAttributeValue("bucket", cty.StringVal(h.config.MigrationHistoryStorage.Bucket))
+		storageBlockBody.SetAttributeValue("bucket", cty.StringVal(migrationHistory.Bucket))
 		storageBlockBody.SetAttributeValue("name", cty.StringVal(historyKey))
 	case "s3":
-		storageBlockBody.SetAttributeValue("bucket", cty.StringVal(h.config.MigrationHistoryStorage.Bucket))
+		storageBlockBody.SetAttributeValue("bucket", cty.StringVal(migrationHistory.Bucket))
 		storageBlockBody.SetAttributeValue("key", cty.StringVal(historyKey))
-		storageBlockBody.SetAttributeValue("region", cty.StringVal(h.config.MigrationHistoryStorage.Region))
+		storageBlockBody.SetAttributeValue("region", cty.StringVal(migrationHistory.Region))
+	case "azurerm":
+		storageBlockBody.SetAttributeValue("storage_account_name", cty.StringVal(migrationHistory.StorageAccountName))
+		storageBlockBody.SetAttributeValue("container_name", cty.StringVal(migrationHistory.Bucket))
+		storageBlockBody.SetAttributeValue("blob_name", cty.StringVal(historyKey))
+	case "local":
+		historyDirectory := "./cloud-concierge/tfmigrate"
+		if migrationHistory.Path != "" {
+			historyDirectory = strings.TrimSuffix(migrationHistory.Path, "/")
+		}
+		storageBlockBody.SetAttributeValue("path", cty.StringVal(fmt.Sprintf("%v/%v", historyDirectory, historyKey)))
 	default:
-		return nil, fmt.Errorf("tfmigrate storage type of %v passed, only s3 is currently supported", storageType)
+		return nil, fmt.Errorf("tfmigrate storage type of %v passed, only s3, gcs, azurerm and local are currently supported", storageType)
 	}
 
 	return f.Bytes(), nil
 }
 
+// workspaceBackend is the backend configured within a workspace directory's terraform block.
+type workspaceBackend struct {
+	// backendType is the label of the backend block, "cloud" for a cloud block, or empty when none is configured.
+	backendType string
+
+	// body is the body of the backend or cloud block.
+	body *hclwrite.Body
+
+	// configFile is the path of the file configuring the backend.
+	configFile string
+}
+
+// loadBackend returns the backend configured within the .tf files of a workspace directory.
+func loadBackend(directory string) (workspaceBackend, error) {
+	configFiles, err := filepath.Glob(filepath.Join(directory, "*.tf"))
+	if err != nil {
+		return workspaceBackend{}, fmt.Errorf("[filepath.Glob]%v", err)
+	}
+
+	for _, configFile := range configFiles {
+		hclFile, err := parseHCLFile(configFile)
+		if err != nil {
+			return workspaceBackend{}, err
+		}
+
+		for _, block := range hclFile.Body().Blocks() {
+			if block.Type() != "terraform" {
+				continue
+			}
+
+			for _, backend := range block.Body().Blocks() {
+				switch {
+				case backend.Type() == "backend" && len(backend.Labels()) == 1:
+					return workspaceBackend{backendType: backend.Labels()[0], body: backend.Body(), configFile: configFile}, nil
+				case backend.Type() == "cloud":
+					return workspaceBackend{backendType: "cloud", body: backend.Body(), configFile: configFile}, nil
+				}
+			}
+		}
+	}
+
+	return workspaceBackend{}, nil
+}
+
+// inferMigrationHistory infers tfmigrate history storage from a workspace's backend, storing history alongside
+// state. Workspaces without a backend, or with a local backend, store history locally.
+func inferMigrationHistory(backend workspaceBackend) (MigrationHistory, error) {
+	if backend.backendType == "" {
+		return MigrationHistory{StorageType: "local"}, nil
+	}
+
+	migrationHistory := MigrationHistory{StorageType: backend.backendType}
+	switch migrationHistory.StorageType {
+	case "s3":
+		migrationHistory.Bucket, _ = stringAttributeValue(backend.body, "bucket")
+		migrationHistory.Region, _ = stringAttributeValue(backend.body, "region")
+	case "gcs":
+		migrationHistory.Bucket, _ = stringAttributeValue(backend.body, "bucket")
+	case "azurerm":
+		migrationHistory.StorageAccountName, _ = stringAttributeValue(backend.body, "storage_account_name")
+		migrationHistory.Bucket, _ = stringAttributeValue(backend.body, "container_name")
+	case "local":
+	default:
+		return MigrationHistory{}, fmt.Errorf("cannot infer migration history storage from the %v backend in %v, MigrationHistoryStorage must be set", migrationHistory.StorageType, backend.configFile)
+	}
+
+	err := validateMigrationHistory(migrationHistory)
+	if err != nil {
+		return MigrationHistory{}, fmt.Errorf("[validateMigrationHistory %v backend in %v]%v", migrationHistory.StorageType, backend.configFile, err)
+	}
+
+	return migrationHistory, nil
+}
+
 // CreateTFMigrateMigration saves HCL which defines a TFMigrate migration.
 func (h *hclCreate) CreateTFMigrateMigration(
 	uniqueID string,
@@ -214,9 +315,7 @@ func (h *hclCreate) generateImportStatement(
 // generateImportStatementText generates the final input statement text for a given cloud resource needing to be
 // imported into terraform control
 func (h *hclCreate) generateImportStatementText(remoteCloudReference string, resourceIDStruct ResourceIdentifier) string {
-	cleanedResourceName := ConvertTerraformerResourceName(resourceIDStruct.resourceName)
-
-	return fmt.Sprintf("import %v.%v %v", resourceIDStruct.resourceType, cleanedResourceName, remoteCloudReference)
+	return fmt.Sprintf("import %v %v", h.resourceAddress(resourceIDStruct), remoteCloudReference)
 }
 
 // resourceToIdentifierStruct structures the information found within the resource string
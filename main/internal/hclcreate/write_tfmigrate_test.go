@@ -1,6 +1,8 @@
 package hclcreate
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"testing"
@@ -83,13 +85,13 @@ func TestIndividualTFMigrateConfigS3(t *testing.T) {
 		},
 	}
 
-	expectedOutput := "tfmigrate {\n  migration_dir              = \"./cloud-concierge/tfmigrate/\"\n  " +
-		"is_backend_terraform_cloud = true\n  " +
+	expectedOutput := "tfmigrate {\n  migration_dir = \"./cloud-concierge/tfmigrate/\"\n  " +
 		"history {\n    storage \"s3\" {\n      bucket = \"example-bucket\"\n      " +
 		"key    = \"exampleWorkspace/history.json\"\n      region = \"us-east1\"\n    }\n  }\n}\n"
 
 	output, err := h.individualTFMigrateConfig(
 		"exampleWorkspace",
+		"/example/",
 	)
 	if err != nil {
 		t.Errorf("unexpected error in h.IndividualTFMigrateConfig: %v", err)
@@ -117,11 +119,45 @@ func TestIndividualTFMigrateConfigGCS(t *testing.T) {
 		},
 	}
 
-	expectedOutput := "tfmigrate {\n  migration_dir              = \"./cloud-concierge/tfmigrate/\"\n  is_backend_terraform_cloud = true\n  " +
+	expectedOutput := "tfmigrate {\n  migration_dir = \"./cloud-concierge/tfmigrate/\"\n  " +
 		"history {\n    storage \"gcs\" {\n      bucket = \"example-bucket\"\n      " +
 		"name   = \"exampleWorkspace/history.json\"\n    }\n  }\n}\n"
 	output, err := h.individualTFMigrateConfig(
 		"exampleWorkspace",
+		"/example/",
+	)
+	if err != nil {
+		t.Errorf("unexpected error in h.IndividualTFMigrateConfig: %v", err)
+	}
+
+	outputString := string(output)
+
+	if expectedOutput != outputString {
+		t.Errorf(
+			"got:\n%v\n\nexpected:\n%v",
+			strconv.Quote(outputString),
+			strconv.Quote(expectedOutput),
+		)
+	}
+}
+
+func TestIndividualTFMigrateConfigAzurerm(t *testing.T) {
+	h := hclCreate{
+		config: Config{
+			MigrationHistoryStorage: MigrationHistory{
+				StorageType:        "azurerm",
+				Bucket:             "example-container",
+				StorageAccountName: "exampleaccount",
+			},
+		},
+	}
+
+	expectedOutput := "tfmigrate {\n  migration_dir = \"./cloud-concierge/tfmigrate/\"\n  " +
+		"history {\n    storage \"azurerm\" {\n      storage_account_name = \"exampleaccount\"\n      " +
+		"container_name       = \"example-container\"\n      blob_name            = \"exampleWorkspace/history.json\"\n    }\n  }\n}\n"
+	output, err := h.individualTFMigrateConfig(
+		"exampleWorkspace",
+		"/example/",
 	)
 	if err != nil {
 		t.Errorf("unexpected error in h.IndividualTFMigrateConfig: %v", err)
@@ -138,6 +174,147 @@ func TestIndividualTFMigrateConfigGCS(t *testing.T) {
 	}
 }
 
+func TestIndividualTFMigrateConfigInferredLocal(t *testing.T) {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error in os.Getwd: %v", err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("unexpected error in os.Chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(workingDirectory) }()
+
+	if err = os.MkdirAll(filepath.Join("repo", "example"), 0o700); err != nil {
+		t.Fatalf("unexpected error in os.MkdirAll: %v", err)
+	}
+
+	h := hclCreate{}
+
+	expectedOutput := "tfmigrate {\n  migration_dir = \"./cloud-concierge/tfmigrate/\"\n  " +
+		"history {\n    storage \"local\" {\n      path = \"./cloud-concierge/tfmigrate/exampleWorkspace/history.json\"\n    }\n  }\n}\n"
+	output, err := h.individualTFMigrateConfig(
+		"exampleWorkspace",
+		"/example/",
+	)
+	if err != nil {
+		t.Errorf("unexpected error in h.IndividualTFMigrateConfig: %v", err)
+	}
+
+	outputString := string(output)
+
+	if expectedOutput != outputString {
+		t.Errorf(
+			"got:\n%v\n\nexpected:\n%v",
+			strconv.Quote(outputString),
+			strconv.Quote(expectedOutput),
+		)
+	}
+}
+
+func TestIndividualTFMigrateConfigTerraformCloud(t *testing.T) {
+	workingDirectory, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error in os.Getwd: %v", err)
+	}
+	if err = os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("unexpected error in os.Chdir: %v", err)
+	}
+	defer func() { _ = os.Chdir(workingDirectory) }()
+
+	if err = os.MkdirAll(filepath.Join("repo", "example"), 0o700); err != nil {
+		t.Fatalf("unexpected error in os.MkdirAll: %v", err)
+	}
+
+	cloudBackend := "terraform {\n  cloud {\n    organization = \"example\"\n  }\n}\n"
+	if err = os.WriteFile(filepath.Join("repo", "example", "backend.tf"), []byte(cloudBackend), 0o600); err != nil {
+		t.Fatalf("unexpected error in os.WriteFile: %v", err)
+	}
+
+	h := hclCreate{
+		config: Config{
+			MigrationHistoryStorage: MigrationHistory{
+				StorageType: "local",
+				Path:        "./history",
+			},
+		},
+	}
+
+	expectedOutput := "tfmigrate {\n  migration_dir              = \"./cloud-concierge/tfmigrate/\"\n  is_backend_terraform_cloud = true\n  " +
+		"history {\n    storage \"local\" {\n      path = \"./history/exampleWorkspace/history.json\"\n    }\n  }\n}\n"
+	output, err := h.individualTFMigrateConfig(
+		"exampleWorkspace",
+		"/example/",
+	)
+	if err != nil {
+		t.Errorf("unexpected error in h.IndividualTFMigrateConfig: %v", err)
+	}
+
+	outputString := string(output)
+
+	if expectedOutput != outputString {
+		t.Errorf(
+			"got:\n%v\n\nexpected:\n%v",
+			strconv.Quote(outputString),
+			strconv.Quote(expectedOutput),
+		)
+	}
+}
+
+func TestInferMigrationHistory(t *testing.T) {
+	directory := t.TempDir()
+	backend := `terraform {
+  backend "azurerm" {
+    resource_group_name  = "state"
+    storage_account_name = "exampleaccount"
+    container_name       = "tfstate"
+    key                  = "prod.terraform.tfstate"
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(directory, "backend.tf"), []byte(backend), 0o600); err != nil {
+		t.Fatalf("unexpected error in os.WriteFile: %v", err)
+	}
+
+	configuredBackend, err := loadBackend(directory)
+	if err != nil {
+		t.Fatalf("unexpected error in loadBackend: %v", err)
+	}
+
+	output, err := inferMigrationHistory(configuredBackend)
+	if err != nil {
+		t.Errorf("unexpected error in inferMigrationHistory: %v", err)
+	}
+
+	expectedOutput := MigrationHistory{
+		StorageType:        "azurerm",
+		Bucket:             "tfstate",
+		StorageAccountName: "exampleaccount",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Errorf("got %v, expected %v", output, expectedOutput)
+	}
+
+	remoteBackend := `terraform {
+  backend "remote" {
+    organization = "example"
+  }
+}
+`
+	if err = os.WriteFile(filepath.Join(directory, "backend.tf"), []byte(remoteBackend), 0o600); err != nil {
+		t.Fatalf("unexpected error in os.WriteFile: %v", err)
+	}
+
+	configuredBackend, err = loadBackend(directory)
+	if err != nil {
+		t.Fatalf("unexpected error in loadBackend: %v", err)
+	}
+
+	_, err = inferMigrationHistory(configuredBackend)
+	if err == nil {
+		t.Errorf("expected an error inferring migration history from a remote backend")
+	}
+}
+
 func TestIndividualTFMigrateMigration(t *testing.T) {
 	h := hclCreate{}
 
@@ -0,0 +1,27 @@
+package identitymapping
+
+import (
+	"context"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// Factory is a struct that generates implementations of interfaces.IdentityMapper.
+type Factory struct{}
+
+// Instantiate returns an implementation of interfaces.IdentityMapper depending on the passed
+// environment specification.
+func (f *Factory) Instantiate(_ context.Context, environment string, vcs interfaces.VCS, config Config) (interfaces.IdentityMapper, error) {
+	switch environment {
+	case "isolated":
+		return NewIsolatedIdentityMapper(), nil
+	default:
+		return f.bootstrappedIdentityMapper(vcs, config)
+	}
+}
+
+// bootstrappedIdentityMapper creates a complete implementation of the interfaces.IdentityMapper interface with
+// configuration specified via environment variables.
+func (f *Factory) bootstrappedIdentityMapper(vcs interfaces.VCS, config Config) (interfaces.IdentityMapper, error) {
+	return NewIdentityMapper(config, vcs), nil
+}
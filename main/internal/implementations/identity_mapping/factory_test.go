@@ -0,0 +1,36 @@
+package identitymapping
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+func TestCreateIdentityMapper(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	factory := new(Factory)
+
+	// When
+	mapper, err := factory.Instantiate(ctx, "not_isolated", new(interfaces.VCSMock), Config{Notification: NoNotification})
+
+	// Then
+	assert.Nil(t, err)
+	assert.NotNil(t, mapper)
+}
+
+func TestCreateIsolatedIdentityMapper(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	factory := new(Factory)
+
+	// When
+	mapper, err := factory.Instantiate(ctx, "isolated", new(interfaces.VCSMock), Config{})
+
+	// Then
+	assert.Nil(t, err)
+	assert.NotNil(t, mapper)
+}
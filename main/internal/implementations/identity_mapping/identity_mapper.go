@@ -0,0 +1,227 @@
+package identitymapping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+const (
+	// NoNotification does not notify the people responsible for changes outside of Terraform control.
+	NoNotification = "none"
+
+	// MentionNotification @-mentions the people responsible for changes outside of Terraform control within the
+	// pull request's report.
+	MentionNotification = "mention"
+
+	// ReviewNotification requests review of the pull request from the people responsible for changes outside of
+	// Terraform control.
+	ReviewNotification = "review"
+)
+
+// emailRegex matches an email address within a cloud principal, such as the session name of an assumed AWS role.
+var emailRegex = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// serviceAccountEmailSuffixes are the domains of cloud service account email addresses, which never belong to
+// version control system users.
+var serviceAccountEmailSuffixes = []string{".gserviceaccount.com"}
+
+// Config is a collection of values that parameterizes an IdentityMapper instance.
+type Config struct {
+	// IdentityMappingFile is the path, relative to the repository root, of a JSON object mapping cloud principals or
+	// email addresses to version control system usernames. An empty username never notifies that principal.
+	IdentityMappingFile string
+
+	// EmailMatching resolves cloud principals containing an email address, when not within the mapping file, to the
+	// version control system user with that email address.
+	EmailMatching bool
+
+	// Notification is how the people responsible for changes outside of Terraform control are notified, one of
+	// NoNotification, MentionNotification or ReviewNotification.
+	Notification string
+}
+
+// CloudActorIdentities is the version control system username of each resolved cloud actor, along with how they
+// are notified.
+type CloudActorIdentities struct {
+	// Notification is how the people responsible for changes outside of Terraform control are notified.
+	Notification string `json:"notification"`
+
+	// ActorToUsername is a map between cloud actors and their version control system username.
+	ActorToUsername map[string]string `json:"actorToUsername"`
+}
+
+// IdentityMapper implements the interfaces.IdentityMapper interface.
+type IdentityMapper struct {
+	// config is a collection of values that parameterizes an IdentityMapper instance.
+	config Config
+
+	// vcs is used to find the version control system users with a given email address.
+	vcs interfaces.VCS
+}
+
+// NewIdentityMapper returns a new instance of IdentityMapper.
+func NewIdentityMapper(config Config, vcs interfaces.VCS) interfaces.IdentityMapper {
+	return &IdentityMapper{config: config, vcs: vcs}
+}
+
+// Execute resolves each cloud actor responsible for creating an unmanaged resource or causing drift to a version
+// control system username, saving the result to outputs/cloud-actor-identities.json.
+func (im *IdentityMapper) Execute(_ context.Context) error {
+	identities := CloudActorIdentities{
+		Notification:    im.config.Notification,
+		ActorToUsername: map[string]string{},
+	}
+
+	if im.config.Notification != NoNotification {
+		resourceActions, err := readResourceActions("outputs/resources-to-cloud-actions.json")
+		if err != nil {
+			return fmt.Errorf("[readResourceActions]%v", err)
+		}
+
+		mapping, err := readIdentityMapping(im.config.IdentityMappingFile)
+		if err != nil {
+			return fmt.Errorf("[readIdentityMapping]%v", err)
+		}
+
+		emailToUsername := map[string]string{}
+		for _, actor := range cloudActors(resourceActions) {
+			username := im.resolve(actor, mapping, emailToUsername)
+			if username != "" {
+				identities.ActorToUsername[actor] = username
+			}
+		}
+	}
+
+	logrus.Debugf("[identity_mapper] resolved cloud actors: %v", identities.ActorToUsername)
+
+	jsonBytes, err := json.MarshalIndent(identities, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%v", err)
+	}
+
+	err = os.WriteFile("outputs/cloud-actor-identities.json", jsonBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile outputs/cloud-actor-identities.json]%v", err)
+	}
+	return nil
+}
+
+// resolve returns the version control system username of a cloud actor, first from the mapping of principals and
+// email addresses, then by email address when configured to. Returns an empty string when the actor is unresolved.
+func (im *IdentityMapper) resolve(actor string, mapping map[string]string, emailToUsername map[string]string) string {
+	if username, ok := mapping[strings.ToLower(actor)]; ok {
+		return username
+	}
+
+	email := strings.ToLower(emailRegex.FindString(actor))
+	if email == "" || isServiceAccountEmail(email) {
+		return ""
+	}
+
+	if username, ok := mapping[email]; ok {
+		return username
+	}
+
+	if !im.config.EmailMatching {
+		return ""
+	}
+
+	if username, ok := emailToUsername[email]; ok {
+		return username
+	}
+
+	username, err := im.vcs.GetUsernameByEmail(email)
+	if err != nil {
+		logrus.Warnf("[identity_mapper] unable to find the version control system user of %v: %v", email, err)
+	}
+	emailToUsername[email] = username
+	return username
+}
+
+// readResourceActions reads the cloud actions of each resource identified by IdentifyCloudActors.
+func readResourceActions(path string) (terraformValueObjects.ResourceActionMap, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile %v]%v", path, err)
+	}
+
+	resourceActions := terraformValueObjects.ResourceActionMap{}
+	err = json.Unmarshal(content, &resourceActions)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal %v]%v", path, err)
+	}
+	return resourceActions, nil
+}
+
+// readIdentityMapping reads the identity mapping file within the cloned repository, with lower-cased keys. Returns
+// an empty mapping when no file is configured.
+func readIdentityMapping(identityMappingFile string) (map[string]string, error) {
+	mapping := map[string]string{}
+	if identityMappingFile == "" {
+		return mapping, nil
+	}
+
+	path := filepath.Join("repo", filepath.FromSlash(strings.TrimPrefix(identityMappingFile, "/")))
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("identity mapping file %v not found within the repository", identityMappingFile)
+	} else if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile %v]%v", path, err)
+	}
+
+	fileMapping := map[string]string{}
+	err = json.Unmarshal(content, &fileMapping)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal %v]%v", path, err)
+	}
+
+	for principal, username := range fileMapping {
+		mapping[strings.ToLower(principal)] = strings.TrimPrefix(username, "@")
+	}
+	return mapping, nil
+}
+
+// cloudActors returns the sorted, distinct actors who created or last modified each resource.
+func cloudActors(resourceActions terraformValueObjects.ResourceActionMap) []string {
+	actorSet := map[string]bool{}
+	for _, actions := range resourceActions {
+		if actions == nil {
+			continue
+		}
+		for _, action := range []*terraformValueObjects.CloudActorTimeStamp{actions.Creator, actions.Modifier} {
+			if action != nil && action.Actor != "" {
+				actorSet[string(action.Actor)] = true
+			}
+		}
+	}
+
+	actors := []string{}
+	for actor := range actorSet {
+		actors = append(actors, actor)
+	}
+	sort.Strings(actors)
+	return actors
+}
+
+// isServiceAccountEmail returns whether an email address belongs to a cloud service account.
+func isServiceAccountEmail(email string) bool {
+	for _, suffix := range serviceAccountEmailSuffixes {
+		if strings.HasSuffix(email, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package identitymapping
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+func TestIdentityMapper_Execute(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	require.NoError(t, os.MkdirAll("outputs", 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join("outputs", "resources-to-cloud-actions.json"), []byte(`{
+  "aws_s3_bucket.logs": {"creation": {"actor": "arn:aws:sts::123:assumed-role/Admin/jane@corp.com", "timestamp": "2023-01-01"}},
+  "dev.aws_vpc.main.123": {"modified": {"actor": "arn:aws:iam::123:user/deploy-bot", "timestamp": "2023-01-02"}},
+  "dev.aws_subnet.a.123": {
+    "creation": {"actor": "arn:aws:sts::123:assumed-role/Admin/john@corp.com", "timestamp": "2023-01-03"},
+    "modified": {"actor": "terraform@project.iam.gserviceaccount.com", "timestamp": "2023-01-04"}
+  },
+  "dev.aws_subnet.b.123": {"modified": {"actor": "arn:aws:sts::123:assumed-role/Admin/unknown@corp.com", "timestamp": "2023-01-05"}}
+}`), 0o644))
+
+	require.NoError(t, os.MkdirAll(filepath.Join("repo", ".github"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join("repo", ".github", "cloud-concierge-identities.json"), []byte(`{
+  "Jane@Corp.com": "@jane-gh",
+  "arn:aws:iam::123:user/deploy-bot": ""
+}`), 0o644))
+
+	vcs := new(interfaces.VCSMock)
+	vcs.On("GetUsernameByEmail", "john@corp.com").Return("john-gh", nil)
+	vcs.On("GetUsernameByEmail", "unknown@corp.com").Return("", nil)
+
+	mapper := NewIdentityMapper(Config{
+		IdentityMappingFile: ".github/cloud-concierge-identities.json",
+		EmailMatching:       true,
+		Notification:        MentionNotification,
+	}, vcs)
+
+	// When
+	err = mapper.Execute(context.Background())
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join("outputs", "cloud-actor-identities.json"))
+	require.NoError(t, err)
+
+	var identities CloudActorIdentities
+	require.NoError(t, json.Unmarshal(content, &identities))
+	assert.Equal(t, CloudActorIdentities{
+		Notification: MentionNotification,
+		ActorToUsername: map[string]string{
+			"arn:aws:sts::123:assumed-role/Admin/jane@corp.com": "jane-gh",
+			"arn:aws:sts::123:assumed-role/Admin/john@corp.com": "john-gh",
+		},
+	}, identities)
+	vcs.AssertNumberOfCalls(t, "GetUsernameByEmail", 2)
+}
+
+func TestIdentityMapper_Execute_NoNotification(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	require.NoError(t, os.MkdirAll("outputs", 0o755))
+	vcs := new(interfaces.VCSMock)
+	mapper := NewIdentityMapper(Config{EmailMatching: true, Notification: NoNotification}, vcs)
+
+	// When
+	err = mapper.Execute(context.Background())
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join("outputs", "cloud-actor-identities.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"notification": "none", "actorToUsername": {}}`, string(content))
+	vcs.AssertNotCalled(t, "GetUsernameByEmail")
+}
+
+func TestReadIdentityMapping_MissingFile(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	// When
+	_, err = readIdentityMapping("identities.json")
+
+	// Then
+	assert.NotNil(t, err)
+}
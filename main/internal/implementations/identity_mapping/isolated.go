@@ -0,0 +1,24 @@
+package identitymapping
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// IsolatedIdentityMapper is a struct that implements interfaces.IdentityMapper
+// for the purpose of running end to end unit tests.
+type IsolatedIdentityMapper struct{}
+
+// NewIsolatedIdentityMapper returns an instance of IsolatedIdentityMapper
+func NewIsolatedIdentityMapper() interfaces.IdentityMapper {
+	return &IsolatedIdentityMapper{}
+}
+
+// Execute resolves each identified cloud actor to a version control system username.
+func (m *IsolatedIdentityMapper) Execute(_ context.Context) error {
+	log.Debug("Executing identity mapper")
+	return nil
+}
@@ -0,0 +1,15 @@
+package identitymapping
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIsolatedIdentityMapper(t *testing.T) {
+	// When
+	mapper := NewIsolatedIdentityMapper()
+
+	// Then
+	assert.NotNil(t, mapper)
+}
@@ -0,0 +1,39 @@
+package markdowncreation
+
+import (
+	"fmt"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// setCodeDriftData sets the data for resources whose code and state do not match in the markdown report
+func (m *MarkdownCreator) setCodeDriftData(report *doc.MarkDownDoc) {
+	report.Write("# Code Drift").Writeln().Writeln()
+
+	report.Write("## Resources in Configuration but not in State").Writeln().Writeln()
+	if len(m.unappliedResources) == 0 {
+		report.Write("No unapplied resources found!").Writeln().Writeln()
+	} else {
+		writeCodeDriftTable(report, m.unappliedResources)
+	}
+
+	report.Write("## State Entries without Configuration").Writeln().Writeln()
+	if len(m.unconfiguredResources) == 0 {
+		report.Write("No state entries without configuration found!").Writeln()
+		return
+	}
+	writeCodeDriftTable(report, m.unconfiguredResources)
+}
+
+// writeCodeDriftTable writes a table of code drift resources to the markdown report
+func writeCodeDriftTable(report *doc.MarkDownDoc, resources []CodeDriftResource) {
+	report.Write("|Type|Name|Module|State File|\n| :---: | :---: | :---: | :---: |\n")
+	for _, resource := range resources {
+		report.Write(fmt.Sprintf("|%s", resource.ResourceType))
+		report.Write(fmt.Sprintf("|%s", resource.ResourceName))
+		report.Write(fmt.Sprintf("|%s", resource.ModuleName))
+		report.Write(fmt.Sprintf("|%s|", resource.StateFileName)).Writeln()
+	}
+
+	report.Writeln()
+}
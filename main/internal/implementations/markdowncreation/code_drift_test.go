@@ -0,0 +1,59 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_setCodeDriftData(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.unappliedResources = []CodeDriftResource{
+		{
+			ResourceType:  "aws_s3_bucket",
+			ResourceName:  "logs",
+			ModuleName:    "",
+			StateFileName: "workspace1",
+		},
+	}
+	markdownCreator.unconfiguredResources = []CodeDriftResource{
+		{
+			ResourceType:  "aws_instance",
+			ResourceName:  "example",
+			ModuleName:    "module.example",
+			StateFileName: "workspace2",
+		},
+	}
+
+	// When
+	markdownCreator.setCodeDriftData(report)
+
+	// Then
+	title := "# Code Drift\n\n"
+	tableHeaders := "|Type|Name|Module|State File|\n| :---: | :---: | :---: | :---: |\n"
+
+	unapplied := "## Resources in Configuration but not in State\n\n" + tableHeaders + "|aws_s3_bucket|logs||workspace1|\n\n"
+	unconfigured := "## State Entries without Configuration\n\n" + tableHeaders + "|aws_instance|example|module.example|workspace2|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s", title, unapplied, unconfigured)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
+
+func TestMarkdownCreator_setCodeDriftData_NoResources(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+
+	// When
+	markdownCreator.setCodeDriftData(report)
+
+	// Then
+	expectedMarkdown := "# Code Drift\n\n" +
+		"## Resources in Configuration but not in State\n\nNo unapplied resources found!\n\n" +
+		"## State Entries without Configuration\n\nNo state entries without configuration found!\n"
+	assert.Equal(t, expectedMarkdown, report.String())
+}
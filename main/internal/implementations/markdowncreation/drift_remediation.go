@@ -0,0 +1,40 @@
+package markdowncreation
+
+import (
+	"fmt"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// setDriftRemediationData sets the data for drifted attributes rewritten to their cloud values in the markdown report.
+// The section is omitted when drift remediation did not run.
+func (m *MarkdownCreator) setDriftRemediationData(report *doc.MarkDownDoc) {
+	if len(m.driftRemediations) == 0 {
+		return
+	}
+
+	report.Write("# Drift Remediation").Writeln().Writeln()
+	report.Write("Drifted attributes below were rewritten within configuration to accept the value found in the cloud. " +
+		"Attributes that could not be rewritten are left unchanged and should be reviewed manually.").Writeln().Writeln()
+
+	report.Write("|Address|Attribute|Cloud Value|State File|Outcome|\n| :---: | :---: | :---: | :---: | :---: |\n")
+	for _, remediation := range m.driftRemediations {
+		address := fmt.Sprintf("%s.%s", remediation.ResourceType, remediation.ResourceName)
+		if remediation.ModuleName != "" {
+			address = fmt.Sprintf("%s.%s", remediation.ModuleName, address)
+		}
+
+		outcome := remediation.Note
+		if remediation.Remediated {
+			outcome = fmt.Sprintf("Updated in %s", remediation.File)
+		}
+
+		report.Write(fmt.Sprintf("|%s", address))
+		report.Write(fmt.Sprintf("|%s", remediation.AttributeName))
+		report.Write(fmt.Sprintf("|%s", remediation.CloudValue))
+		report.Write(fmt.Sprintf("|%s", remediation.StateFileName))
+		report.Write(fmt.Sprintf("|%s|", outcome)).Writeln()
+	}
+
+	report.Writeln()
+}
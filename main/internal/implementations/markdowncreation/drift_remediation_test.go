@@ -0,0 +1,50 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_setDriftRemediationData(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.driftRemediations = []DriftRemediation{
+		{
+			StateFileName: "workspace-a",
+			ResourceType:  "aws_s3_bucket",
+			ResourceName:  "logs",
+			AttributeName: "tags.Team",
+			CloudValue:    "platform",
+			File:          "/workspace-a/main.tf",
+			Remediated:    true,
+		},
+		{
+			StateFileName: "workspace-a",
+			ModuleName:    "module.queue",
+			ResourceType:  "aws_sqs_queue",
+			ResourceName:  "this",
+			AttributeName: "delay_seconds",
+			CloudValue:    "10",
+			Note:          "resource is defined within module.queue; update the module's inputs to accept the cloud value",
+		},
+	}
+
+	// When
+	markdownCreator.setDriftRemediationData(report)
+
+	// Then
+	title := "# Drift Remediation\n\n" +
+		"Drifted attributes below were rewritten within configuration to accept the value found in the cloud. " +
+		"Attributes that could not be rewritten are left unchanged and should be reviewed manually.\n\n"
+
+	tableHeaders := "|Address|Attribute|Cloud Value|State File|Outcome|\n| :---: | :---: | :---: | :---: | :---: |\n"
+	tableContent := "|aws_s3_bucket.logs|tags.Team|platform|workspace-a|Updated in /workspace-a/main.tf|\n" +
+		"|module.queue.aws_sqs_queue.this|delay_seconds|10|workspace-a|resource is defined within module.queue; update the module's inputs to accept the cloud value|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s", title, tableHeaders, tableContent)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
@@ -0,0 +1,45 @@
+package markdowncreation
+
+import (
+	"fmt"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// setDuplicatedResourcesData sets the data for resources managed by more than one state file in the markdown report
+func (m *MarkdownCreator) setDuplicatedResourcesData(report *doc.MarkDownDoc) {
+	report.Write("# Resources Managed by Multiple State Files").Writeln().Writeln()
+
+	if len(m.duplicatedResources) == 0 {
+		report.Write("No resources managed by multiple state files found!").Writeln()
+		return
+	}
+
+	report.Write("|Type|Cloud ID|Address|State File|Resolution|\n| :---: | :---: | :---: | :---: | :---: |\n")
+	for _, duplicatedResource := range m.duplicatedResources {
+		configuredClaims := 0
+		for _, claim := range duplicatedResource.Claims {
+			if claim.Configured {
+				configuredClaims++
+			}
+		}
+
+		for _, claim := range duplicatedResource.Claims {
+			resolution := "Keep"
+			if claim.ShouldResolve {
+				resolution = "Remove from state"
+			} else if claim.Configured && configuredClaims > 1 {
+				// Each of these claims has a resource block, so removing any from state needs its code removed too.
+				resolution = "Manual review, configured in multiple workspaces"
+			}
+
+			report.Write(fmt.Sprintf("|%s", duplicatedResource.ResourceType))
+			report.Write(fmt.Sprintf("|%s", duplicatedResource.InstanceID))
+			report.Write(fmt.Sprintf("|%s", claim.Address))
+			report.Write(fmt.Sprintf("|%s", claim.StateFileName))
+			report.Write(fmt.Sprintf("|%s|", resolution)).Writeln()
+		}
+	}
+
+	report.Writeln()
+}
@@ -0,0 +1,48 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_setDuplicatedResourcesData(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.duplicatedResources = []DuplicatedResource{
+		{
+			InstanceID:   "my-logs",
+			ResourceType: "aws_s3_bucket",
+			Claims: []ResourceClaim{
+				{StateFileName: "workspace-a", Address: "aws_s3_bucket.logs", ShouldResolve: true},
+				{StateFileName: "workspace-b", Address: "aws_s3_bucket.logs", Configured: true, ShouldResolve: false},
+			},
+		},
+		{
+			InstanceID:   "my-data",
+			ResourceType: "aws_s3_bucket",
+			Claims: []ResourceClaim{
+				{StateFileName: "workspace-a", Address: "aws_s3_bucket.data", Configured: true},
+				{StateFileName: "workspace-b", Address: "aws_s3_bucket.data", Configured: true},
+			},
+		},
+	}
+
+	// When
+	markdownCreator.setDuplicatedResourcesData(report)
+
+	// Then
+	title := "# Resources Managed by Multiple State Files\n\n"
+
+	tableHeaders := "|Type|Cloud ID|Address|State File|Resolution|\n| :---: | :---: | :---: | :---: | :---: |\n"
+	tableContent := "|aws_s3_bucket|my-logs|aws_s3_bucket.logs|workspace-a|Remove from state|\n" +
+		"|aws_s3_bucket|my-logs|aws_s3_bucket.logs|workspace-b|Keep|\n" +
+		"|aws_s3_bucket|my-data|aws_s3_bucket.data|workspace-a|Manual review, configured in multiple workspaces|\n" +
+		"|aws_s3_bucket|my-data|aws_s3_bucket.data|workspace-b|Manual review, configured in multiple workspaces|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s", title, tableHeaders, tableContent)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
@@ -2,6 +2,7 @@ package markdowncreation
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -21,6 +22,7 @@ type ManagedDriftResource struct {
 	CloudValue            string `json:"CloudValue"`
 	InstanceID            string `json:"InstanceID"`
 	InstanceRegion        string `json:"InstanceRegion"`
+	Severity              string `json:"Severity"`
 	StateFileName         string `json:"StateFileName"`
 	ModuleName            string `json:"ModuleName"`
 	ResourceType          string `json:"ResourceType"`
@@ -54,14 +56,109 @@ type DeletedResource struct {
 	ResourceName  string `json:"ResourceName"`
 }
 
+// CodeDriftResource represents a resource whose definition in code and entry in state do not match
+type CodeDriftResource struct {
+	StateFileName string `json:"StateFileName"`
+	ModuleName    string `json:"ModuleName"`
+	ResourceType  string `json:"ResourceType"`
+	ResourceName  string `json:"ResourceName"`
+}
+
+// DuplicatedResource represents a cloud resource that is managed by more than one state file
+type DuplicatedResource struct {
+	InstanceID   string          `json:"InstanceID"`
+	ResourceType string          `json:"ResourceType"`
+	Claims       []ResourceClaim `json:"Claims"`
+}
+
+// ResourceClaim represents a single state file address that manages a cloud resource
+type ResourceClaim struct {
+	StateFileName string `json:"StateFileName"`
+	ModuleName    string `json:"ModuleName"`
+	ResourceName  string `json:"ResourceName"`
+	Address       string `json:"Address"`
+	Configured    bool   `json:"Configured"`
+	ShouldResolve bool   `json:"ShouldResolve"`
+}
+
+// SecurityDrift represents a security finding introduced by drift of a resource managed by terraform
+type SecurityDrift struct {
+	InstanceID            string   `json:"InstanceID"`
+	StateFileName         string   `json:"StateFileName"`
+	ModuleName            string   `json:"ModuleName"`
+	ResourceType          string   `json:"ResourceType"`
+	ResourceName          string   `json:"ResourceName"`
+	RecentActor           string   `json:"RecentActor"`
+	RecentActionTimestamp string   `json:"RecentActionTimestamp"`
+	RuleID                string   `json:"RuleID"`
+	RuleDescription       string   `json:"RuleDescription"`
+	Severity              string   `json:"Severity"`
+	Resolution            string   `json:"Resolution"`
+	Links                 []string `json:"Links"`
+}
+
+// GeneratedResourceName represents the name chosen for a resource written to Terraform configuration
+type GeneratedResourceName struct {
+	Name      string `json:"Name"`
+	Address   string `json:"Address"`
+	Workspace string `json:"Workspace"`
+	CloudID   string `json:"CloudID"`
+}
+
+// DriftRemediation represents the outcome of accepting the cloud value of a drifted attribute
+type DriftRemediation struct {
+	StateFileName string `json:"StateFileName"`
+	ModuleName    string `json:"ModuleName"`
+	ResourceType  string `json:"ResourceType"`
+	ResourceName  string `json:"ResourceName"`
+	AttributeName string `json:"AttributeName"`
+	CloudValue    string `json:"CloudValue"`
+	File          string `json:"File"`
+	Remediated    bool   `json:"Remediated"`
+	Note          string `json:"Note"`
+}
+
+// VersionMismatch represents a Terraform or provider version pinned inconsistently across workspaces
+type VersionMismatch struct {
+	Name           string            `json:"Name"`
+	Selected       string            `json:"Selected"`
+	WorkspaceToPin map[string]string `json:"WorkspaceToPin"`
+	Reason         string            `json:"Reason"`
+}
+
+// RepositoryVersions represents the Terraform and provider versions inferred from the repository
+type RepositoryVersions struct {
+	TerraformVersion string            `json:"TerraformVersion"`
+	Providers        map[string]string `json:"Providers"`
+	Mismatches       []VersionMismatch `json:"Mismatches"`
+}
+
+// PlanStatus represents the outcome of validating the generated code of a workspace
+type PlanStatus struct {
+	Workspace            string   `json:"Workspace"`
+	Validate             string   `json:"Validate"`
+	Plan                 string   `json:"Plan"`
+	QuarantinedResources []string `json:"QuarantinedResources"`
+	Message              string   `json:"Message"`
+}
+
 // MarkdownCreator is responsible for creating the markdown file with the data from the state of cloud
 type MarkdownCreator struct {
 	newResources            map[string]string
+	generatedNames          map[string]GeneratedResourceName
 	resourcesToCloudActions map[string]map[string]CloudActionDetail
 	costEstimates           []CostEstimate
 	securityScan            []SecurityRisk
+	securityDrift           []SecurityDrift
 	managedDrift            []ManagedDriftResource
 	deletedResources        []DeletedResource
+	unappliedResources      []CodeDriftResource
+	unconfiguredResources   []CodeDriftResource
+	duplicatedResources     []DuplicatedResource
+	driftRemediations       []DriftRemediation
+	versionMismatches       []VersionMismatch
+	planStatuses            []PlanStatus
+	cloudActorIdentities    CloudActorIdentities
 }
 
 // NewMarkdownCreator returns a new MarkdownCreator
@@ -71,23 +168,39 @@ func NewMarkdownCreator() *MarkdownCreator {
 
 // CreateMarkdownFile creates a markdown file with the data from the state of cloud
 func (m *MarkdownCreator) CreateMarkdownFile(jobName string) error {
+	return m.createMarkdownFile(jobName, nil)
+}
+
+// createMarkdownFile creates a markdown file with the data from the state of cloud, limited to scope when not nil
+func (m *MarkdownCreator) createMarkdownFile(jobName string, scope *ReportScope) error {
 	err := m.initData()
 	if err != nil {
 		return fmt.Errorf("[markdown_creator][create_markdown_file] error initializing data: %w", err)
 	}
 
+	if scope != nil {
+		m.applyScope(scope)
+	}
+
 	report := doc.NewMarkDown()
 
 	m.setGeneralData(report, jobName)
+	m.setSecurityDriftData(report)
 	m.setSecurityRiskData(report)
 	m.setCostsEstimatesData(report)
 	m.setResourcesOutsideOfTerraformControlData(report)
 	m.setDeletedResourcesData(report)
+	m.setCodeDriftData(report)
+	m.setDuplicatedResourcesData(report)
 	m.setDriftedResourcesManagedByTerraformData(report)
+	m.setDriftRemediationData(report)
 	m.setRootCausesOfDriftData(report)
+	m.setResponsiblePeopleData(report)
+	m.setVersionMismatchesData(report)
+	m.setPlanStatusData(report)
 	m.setFooter(report)
 
-	err = os.Mkdir(OutputPath, 0o755)
+	err = os.MkdirAll(OutputPath, 0o755)
 	if err != nil {
 		return fmt.Errorf("[markdown_creator][create_markdown_file] error creating output directory: %w", err)
 	}
@@ -97,6 +210,16 @@ func (m *MarkdownCreator) CreateMarkdownFile(jobName string) error {
 		return fmt.Errorf("[markdown_creator][create_markdown_file] error creating file: %w", err)
 	}
 
+	err = m.writeRequestedReviewers()
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][create_markdown_file] error writing requested reviewers: %w", err)
+	}
+
+	err = m.writeReportSummary()
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][create_markdown_file] error writing report summary: %w", err)
+	}
+
 	return nil
 }
 
@@ -113,6 +236,18 @@ func (m *MarkdownCreator) initData() error {
 		return fmt.Errorf("error parsing JSON from resources new resources: %v", err)
 	}
 
+	generatedNamesBytes, err := readOptionalFile(filePathRoot + "new-resources-names.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading new resources names file: %w", err)
+	}
+	var generatedNames map[string]GeneratedResourceName
+	if generatedNamesBytes != nil {
+		err = json.Unmarshal(generatedNamesBytes, &generatedNames)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON from new resources names: %v", err)
+		}
+	}
+
 	resourcesToCloudActionsBytes, err := readFile(filePathRoot + "resources-to-cloud-actions.json")
 	if err != nil {
 		return fmt.Errorf("[markdown_creator][init_data] error reading resources to cloud actions file: %w", err)
@@ -143,6 +278,18 @@ func (m *MarkdownCreator) initData() error {
 		return fmt.Errorf("error parsing JSON from security scans: %v", err)
 	}
 
+	securityDriftBytes, err := readOptionalFile(filePathRoot + "security-drift.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading security drift file: %w", err)
+	}
+	var securityDrift []SecurityDrift
+	if securityDriftBytes != nil {
+		err = json.Unmarshal(securityDriftBytes, &securityDrift)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON from security drift: %v", err)
+		}
+	}
+
 	managedDriftBytes, err := readFile(filePathRoot + "drift-resources-differences.json")
 	if err != nil {
 		return fmt.Errorf("[markdown_creator][init_data] error reading drift resources differences file: %w", err)
@@ -163,12 +310,91 @@ func (m *MarkdownCreator) initData() error {
 		return fmt.Errorf("error parsing JSON from deleted resources: %v", err)
 	}
 
+	unappliedResources, err := readOptionalCodeDriftResources(filePathRoot + "drift-resources-unapplied.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading drift resources unapplied file: %w", err)
+	}
+
+	unconfiguredResources, err := readOptionalCodeDriftResources(filePathRoot + "drift-resources-without-configuration.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading drift resources without configuration file: %w", err)
+	}
+
+	duplicatedResourcesBytes, err := readOptionalFile(filePathRoot + "drift-resources-duplicated.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading drift resources duplicated file: %w", err)
+	}
+	var duplicatedResources []DuplicatedResource
+	if duplicatedResourcesBytes != nil {
+		err = json.Unmarshal(duplicatedResourcesBytes, &duplicatedResources)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON from duplicated resources: %v", err)
+		}
+	}
+
+	driftRemediationBytes, err := readOptionalFile(filePathRoot + "drift-remediation.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading drift remediation file: %w", err)
+	}
+	var driftRemediations []DriftRemediation
+	if driftRemediationBytes != nil {
+		err = json.Unmarshal(driftRemediationBytes, &driftRemediations)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON from drift remediations: %v", err)
+		}
+	}
+
+	repositoryVersionsBytes, err := readOptionalFile(filePathRoot + "repository-versions.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading repository versions file: %w", err)
+	}
+	var repositoryVersions RepositoryVersions
+	if repositoryVersionsBytes != nil {
+		err = json.Unmarshal(repositoryVersionsBytes, &repositoryVersions)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON from repository versions: %v", err)
+		}
+	}
+
+	planStatusBytes, err := readOptionalFile(filePathRoot + "plan-status.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading plan status file: %w", err)
+	}
+	var planStatuses []PlanStatus
+	if planStatusBytes != nil {
+		err = json.Unmarshal(planStatusBytes, &planStatuses)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON from plan statuses: %v", err)
+		}
+	}
+
+	cloudActorIdentitiesBytes, err := readOptionalFile(filePathRoot + "cloud-actor-identities.json")
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][init_data] error reading cloud actor identities file: %w", err)
+	}
+	var cloudActorIdentities CloudActorIdentities
+	if cloudActorIdentitiesBytes != nil {
+		err = json.Unmarshal(cloudActorIdentitiesBytes, &cloudActorIdentities)
+		if err != nil {
+			return fmt.Errorf("error parsing JSON from cloud actor identities: %v", err)
+		}
+	}
+
 	m.newResources = newResources
+	m.generatedNames = generatedNames
 	m.resourcesToCloudActions = resourcesToCloudActions
 	m.costEstimates = costEstimates
 	m.securityScan = securityScan["results"]
+	m.securityDrift = securityDrift
 	m.managedDrift = managedDrift
 	m.deletedResources = deletedResources
+	m.unappliedResources = unappliedResources
+	m.unconfiguredResources = unconfiguredResources
+	m.duplicatedResources = duplicatedResources
+	m.driftRemediations = driftRemediations
+	m.versionMismatches = repositoryVersions.Mismatches
+	m.planStatuses = planStatuses
+	m.cloudActorIdentities = cloudActorIdentities
 
 	return nil
 }
@@ -210,3 +436,31 @@ func readFile(path string) ([]byte, error) {
 
 	return data, nil
 }
+
+// readOptionalFile reads a file and returns the bytes, returning no data if the file does not exist
+func readOptionalFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", path, err)
+	}
+
+	return data, nil
+}
+
+// readOptionalCodeDriftResources reads code drift resources from an optional output file
+func readOptionalCodeDriftResources(path string) ([]CodeDriftResource, error) {
+	data, err := readOptionalFile(path)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var resources []CodeDriftResource
+	err = json.Unmarshal(data, &resources)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing JSON from code drift resources: %v", err)
+	}
+
+	return resources, nil
+}
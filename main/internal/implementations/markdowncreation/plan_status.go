@@ -0,0 +1,36 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// setPlanStatusData sets the outcome of validating the generated code of each touched workspace in the markdown
+// report. The section is omitted when generated code is not validated.
+func (m *MarkdownCreator) setPlanStatusData(report *doc.MarkDownDoc) {
+	if len(m.planStatuses) == 0 {
+		return
+	}
+
+	report.Write("# Plan Status").Writeln().Writeln()
+	report.Write("Generated code was validated within a sandboxed copy of each workspace before opening this pull request. " +
+		"New resources that failed `terraform validate`, or whose import `terraform plan` proposes changes for, were moved to `cloud-concierge/quarantine/new-resources.tf.txt`, and their import blocks and tfmigrate import actions were removed. Planned changes to other resources are only reported.").Writeln().Writeln()
+
+	report.Write("|Workspace|Validate|Plan|Quarantined Resources|Details|\n| :---: | :---: | :---: | :---: | :---: |\n")
+	for _, status := range m.planStatuses {
+		plan := status.Plan
+		if plan == "" {
+			plan = "-"
+		}
+
+		report.Write(fmt.Sprintf("|%s", status.Workspace))
+		report.Write(fmt.Sprintf("|%s", status.Validate))
+		report.Write(fmt.Sprintf("|%s", plan))
+		report.Write(fmt.Sprintf("|%s", strings.Join(status.QuarantinedResources, "<br>")))
+		report.Write(fmt.Sprintf("|%s|", strings.ReplaceAll(status.Message, "|", "\\|"))).Writeln()
+	}
+
+	report.Writeln()
+}
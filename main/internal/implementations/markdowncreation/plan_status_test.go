@@ -0,0 +1,55 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_setPlanStatusData(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.planStatuses = []PlanStatus{
+		{
+			Workspace:            "dev",
+			Validate:             "passed",
+			Plan:                 "no changes",
+			QuarantinedResources: []string{"aws_s3_bucket.logs", "aws_s3_bucket.assets"},
+		},
+		{
+			Workspace: "prod",
+			Validate:  "failed",
+			Message:   "Unsupported argument: An argument named \"acl\" is not expected here.",
+		},
+	}
+
+	// When
+	markdownCreator.setPlanStatusData(report)
+
+	// Then
+	title := "# Plan Status\n\n"
+	description := "Generated code was validated within a sandboxed copy of each workspace before opening this pull request. " +
+		"New resources that failed `terraform validate`, or whose import `terraform plan` proposes changes for, were moved to `cloud-concierge/quarantine/new-resources.tf.txt`, and their import blocks and tfmigrate import actions were removed. Planned changes to other resources are only reported.\n\n"
+
+	tableHeaders := "|Workspace|Validate|Plan|Quarantined Resources|Details|\n| :---: | :---: | :---: | :---: | :---: |\n"
+	tableContent := "|dev|passed|no changes|aws_s3_bucket.logs<br>aws_s3_bucket.assets||\n" +
+		"|prod|failed|-||Unsupported argument: An argument named \"acl\" is not expected here.|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s%s", title, description, tableHeaders, tableContent)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
+
+func TestMarkdownCreator_setPlanStatusData_NotValidated(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+
+	// When
+	markdownCreator.setPlanStatusData(report)
+
+	// Then
+	assert.Equal(t, "", report.String())
+}
@@ -0,0 +1,55 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// PullRequestSummary represents a pull request opened for a subset of a job's changes
+type PullRequestSummary struct {
+	Scope string `json:"Scope"`
+	URL   string `json:"URL"`
+}
+
+// CreatePullRequestIndex creates a markdown file listing each pull request opened within a job
+func (m *MarkdownCreator) CreatePullRequestIndex(jobName string, pullRequests []PullRequestSummary) error {
+	report := doc.NewMarkDown()
+	m.setPullRequestIndexData(report, jobName, pullRequests)
+
+	err := os.MkdirAll(OutputPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][create_pull_request_index] error creating output directory: %w", err)
+	}
+
+	err = report.Export(fmt.Sprintf("%s/pull-requests.md", OutputPath))
+	if err != nil {
+		return fmt.Errorf("[markdown_creator][create_pull_request_index] error creating file: %w", err)
+	}
+
+	return nil
+}
+
+// setPullRequestIndexData sets the table of pull requests opened within a job
+func (m *MarkdownCreator) setPullRequestIndexData(report *doc.MarkDownDoc, jobName string, pullRequests []PullRequestSummary) {
+	report.Write(fmt.Sprintf("%s - Pull Requests", jobName)).Writeln()
+	report.Write("========================================================").Writeln().Writeln()
+
+	if len(pullRequests) == 0 {
+		report.Write("No pull requests were opened.").Writeln()
+		return
+	}
+
+	report.Write(fmt.Sprintf("'%s' split its changes into %d pull requests, each with a report of the resources within its scope.",
+		jobName, len(pullRequests))).Writeln().Writeln()
+
+	report.Write("|Scope|Pull Request|").Writeln()
+	report.Write("| :---: | :---: |").Writeln()
+
+	for _, pullRequest := range pullRequests {
+		report.Write(fmt.Sprintf("|%s|%s|", pullRequest.Scope, pullRequest.URL)).Writeln()
+	}
+
+	report.Writeln()
+}
@@ -0,0 +1,46 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_setPullRequestIndexData(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	pullRequests := []PullRequestSummary{
+		{Scope: "networking", URL: "https://github.com/org/repo/pull/1"},
+		{Scope: "storage", URL: "https://github.com/org/repo/pull/2"},
+	}
+
+	// When
+	markdownCreator.setPullRequestIndexData(report, "Job", pullRequests)
+
+	// Then
+	title := "Job - Pull Requests\n========================================================\n\n"
+	description := "'Job' split its changes into 2 pull requests, each with a report of the resources within its scope.\n\n"
+
+	tableHeaders := "|Scope|Pull Request|\n| :---: | :---: |\n"
+	tableContent := "|networking|https://github.com/org/repo/pull/1|\n" +
+		"|storage|https://github.com/org/repo/pull/2|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s%s", title, description, tableHeaders, tableContent)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
+
+func TestMarkdownCreator_setPullRequestIndexData_NoPullRequests(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+
+	// When
+	markdownCreator.setPullRequestIndexData(report, "Job", []PullRequestSummary{})
+
+	// Then
+	expectedMarkdown := "Job - Pull Requests\n========================================================\n\nNo pull requests were opened.\n"
+	assert.Equal(t, expectedMarkdown, report.String())
+}
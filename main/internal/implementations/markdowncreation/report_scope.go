@@ -0,0 +1,179 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+)
+
+// ReportScope limits a report to the resources within a single pull request. Empty fields do not limit the report.
+type ReportScope struct {
+	// Name describes the scope within the report's title.
+	Name string
+
+	// Workspaces are the workspaces whose resources are reported.
+	Workspaces []string
+
+	// Category is the documentize resource category, such as "networking", whose resources are reported.
+	Category string
+}
+
+// CreateScopedMarkdownFile creates a markdown file with the data from the state of cloud limited to the resources
+// within scope.
+func (m *MarkdownCreator) CreateScopedMarkdownFile(jobName string, scope ReportScope) error {
+	return m.createMarkdownFile(fmt.Sprintf("%s (%s)", jobName, scope.Name), &scope)
+}
+
+// includes returns whether a resource of the given workspace and type is within scope. Resources of an unknown
+// workspace are only within scope when the scope is not limited to workspaces.
+func (s *ReportScope) includes(workspace string, resourceType string) bool {
+	if s.Category != "" && documentize.PrimaryCategory(resourceType) != s.Category {
+		return false
+	}
+
+	return s.includesWorkspace(workspace)
+}
+
+// includesWorkspace returns whether a workspace is within scope.
+func (s *ReportScope) includesWorkspace(workspace string) bool {
+	if len(s.Workspaces) == 0 {
+		return true
+	}
+
+	for _, scopeWorkspace := range s.Workspaces {
+		if scopeWorkspace == workspace {
+			return true
+		}
+	}
+	return false
+}
+
+// applyScope removes the data of resources outside of scope.
+func (m *MarkdownCreator) applyScope(scope *ReportScope) {
+	newResources := map[string]string{}
+	for resourceID, document := range m.newResources {
+		if scope.includes(m.generatedNames[resourceID].Workspace, resourceType(resourceID)) {
+			newResources[resourceID] = document
+		}
+	}
+
+	generatedNames := map[string]GeneratedResourceName{}
+	for resourceID, generatedName := range m.generatedNames {
+		if _, ok := newResources[resourceID]; ok {
+			generatedNames[resourceID] = generatedName
+		}
+	}
+
+	costEstimates := []CostEstimate{}
+	for _, costEstimate := range m.costEstimates {
+		_, isNewResource := newResources[costEstimate.ResourceName]
+		if isNewResource || (len(scope.Workspaces) == 0 && scope.includes("", resourceType(costEstimate.ResourceName))) {
+			costEstimates = append(costEstimates, costEstimate)
+		}
+	}
+
+	resourcesToCloudActions := map[string]map[string]CloudActionDetail{}
+	for resourceKey, cloudActions := range m.resourcesToCloudActions {
+		_, isNewResource := newResources[resourceKey]
+		workspace, keyResourceType := cloudActionResource(resourceKey)
+		if isNewResource || (workspace != "" && scope.includes(workspace, keyResourceType)) {
+			resourcesToCloudActions[resourceKey] = cloudActions
+		}
+	}
+
+	securityDrift := []SecurityDrift{}
+	for _, drift := range m.securityDrift {
+		if scope.includes(drift.StateFileName, drift.ResourceType) {
+			securityDrift = append(securityDrift, drift)
+		}
+	}
+
+	managedDrift := []ManagedDriftResource{}
+	for _, drift := range m.managedDrift {
+		if scope.includes(drift.StateFileName, drift.ResourceType) {
+			managedDrift = append(managedDrift, drift)
+		}
+	}
+
+	deletedResources := []DeletedResource{}
+	for _, deleted := range m.deletedResources {
+		if scope.includes(deleted.StateFileName, deleted.ResourceType) {
+			deletedResources = append(deletedResources, deleted)
+		}
+	}
+
+	duplicatedResources := []DuplicatedResource{}
+	for _, duplicated := range m.duplicatedResources {
+		for _, claim := range duplicated.Claims {
+			if scope.includes(claim.StateFileName, duplicated.ResourceType) {
+				duplicatedResources = append(duplicatedResources, duplicated)
+				break
+			}
+		}
+	}
+
+	driftRemediations := []DriftRemediation{}
+	for _, remediation := range m.driftRemediations {
+		if scope.includes(remediation.StateFileName, remediation.ResourceType) {
+			driftRemediations = append(driftRemediations, remediation)
+		}
+	}
+
+	versionMismatches := []VersionMismatch{}
+	for _, mismatch := range m.versionMismatches {
+		for workspace := range mismatch.WorkspaceToPin {
+			if scope.includesWorkspace(workspace) {
+				versionMismatches = append(versionMismatches, mismatch)
+				break
+			}
+		}
+	}
+
+	planStatuses := []PlanStatus{}
+	for _, planStatus := range m.planStatuses {
+		if scope.includesWorkspace(planStatus.Workspace) {
+			planStatuses = append(planStatuses, planStatus)
+		}
+	}
+
+	m.newResources = newResources
+	m.generatedNames = generatedNames
+	m.costEstimates = costEstimates
+	m.resourcesToCloudActions = resourcesToCloudActions
+	m.securityDrift = securityDrift
+	m.managedDrift = managedDrift
+	m.deletedResources = deletedResources
+	m.unappliedResources = filterCodeDriftResources(m.unappliedResources, scope)
+	m.unconfiguredResources = filterCodeDriftResources(m.unconfiguredResources, scope)
+	m.duplicatedResources = duplicatedResources
+	m.driftRemediations = driftRemediations
+	m.versionMismatches = versionMismatches
+	m.planStatuses = planStatuses
+}
+
+// filterCodeDriftResources returns the code drift resources within scope.
+func filterCodeDriftResources(resources []CodeDriftResource, scope *ReportScope) []CodeDriftResource {
+	scoped := []CodeDriftResource{}
+	for _, resource := range resources {
+		if scope.includes(resource.StateFileName, resource.ResourceType) {
+			scoped = append(scoped, resource)
+		}
+	}
+	return scoped
+}
+
+// resourceType returns the resource type of a "type.name" resource identifier.
+func resourceType(resourceID string) string {
+	return strings.Split(resourceID, ".")[0]
+}
+
+// cloudActionResource returns the workspace and resource type of a managed resource's cloud actions key, formatted
+// as "workspace.type.name.division". New resources, keyed by "type.name", have no workspace.
+func cloudActionResource(resourceKey string) (string, string) {
+	components := strings.Split(resourceKey, ".")
+	if len(components) < 4 {
+		return "", components[0]
+	}
+	return components[0], components[1]
+}
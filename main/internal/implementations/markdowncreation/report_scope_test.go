@@ -0,0 +1,71 @@
+package markdowncreation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_applyScope_Workspace(t *testing.T) {
+	// Given
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.newResources = map[string]string{
+		"aws_s3_bucket.logs": "logs bucket",
+		"aws_vpc.main":       "main vpc",
+	}
+	markdownCreator.generatedNames = map[string]GeneratedResourceName{
+		"aws_s3_bucket.logs": {Name: "logs", Workspace: "dev"},
+		"aws_vpc.main":       {Name: "main", Workspace: "prod"},
+	}
+	markdownCreator.resourcesToCloudActions = map[string]map[string]CloudActionDetail{
+		"aws_s3_bucket.logs":              {},
+		"aws_vpc.main":                    {},
+		"dev.aws_s3_bucket.assets.aws":    {},
+		"prod.aws_security_group.web.aws": {},
+	}
+	markdownCreator.deletedResources = []DeletedResource{
+		{StateFileName: "dev", ResourceType: "aws_s3_bucket", ResourceName: "old"},
+		{StateFileName: "prod", ResourceType: "aws_vpc", ResourceName: "old"},
+	}
+	markdownCreator.planStatuses = []PlanStatus{{Workspace: "dev"}, {Workspace: "prod"}}
+
+	// When
+	markdownCreator.applyScope(&ReportScope{Name: "dev", Workspaces: []string{"dev"}})
+
+	// Then
+	assert.Equal(t, map[string]string{"aws_s3_bucket.logs": "logs bucket"}, markdownCreator.newResources)
+	assert.Equal(t, map[string]GeneratedResourceName{"aws_s3_bucket.logs": {Name: "logs", Workspace: "dev"}}, markdownCreator.generatedNames)
+	assert.Equal(t, map[string]map[string]CloudActionDetail{
+		"aws_s3_bucket.logs":           {},
+		"dev.aws_s3_bucket.assets.aws": {},
+	}, markdownCreator.resourcesToCloudActions)
+	assert.Equal(t, []DeletedResource{{StateFileName: "dev", ResourceType: "aws_s3_bucket", ResourceName: "old"}}, markdownCreator.deletedResources)
+	assert.Equal(t, []PlanStatus{{Workspace: "dev"}}, markdownCreator.planStatuses)
+}
+
+func TestMarkdownCreator_applyScope_Category(t *testing.T) {
+	// Given
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.newResources = map[string]string{
+		"aws_s3_bucket.logs": "logs bucket",
+		"aws_vpc.main":       "main vpc",
+	}
+	markdownCreator.costEstimates = []CostEstimate{
+		{ResourceName: "aws_s3_bucket.logs"},
+		{ResourceName: "aws_vpc.main"},
+	}
+	markdownCreator.deletedResources = []DeletedResource{
+		{StateFileName: "dev", ResourceType: "aws_s3_bucket", ResourceName: "old"},
+		{StateFileName: "prod", ResourceType: "aws_vpc", ResourceName: "old"},
+	}
+	markdownCreator.planStatuses = []PlanStatus{{Workspace: "dev"}, {Workspace: "prod"}}
+
+	// When
+	markdownCreator.applyScope(&ReportScope{Name: "networking", Category: "networking"})
+
+	// Then
+	assert.Equal(t, map[string]string{"aws_vpc.main": "main vpc"}, markdownCreator.newResources)
+	assert.Equal(t, []CostEstimate{{ResourceName: "aws_vpc.main"}}, markdownCreator.costEstimates)
+	assert.Equal(t, []DeletedResource{{StateFileName: "prod", ResourceType: "aws_vpc", ResourceName: "old"}}, markdownCreator.deletedResources)
+	assert.Equal(t, []PlanStatus{{Workspace: "dev"}, {Workspace: "prod"}}, markdownCreator.planStatuses)
+}
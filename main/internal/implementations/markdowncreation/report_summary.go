@@ -0,0 +1,59 @@
+package markdowncreation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ReportSummaryPath is the path of the file counting the resources within the report, from which commit messages
+// are rendered.
+const ReportSummaryPath = OutputPath + "/report-summary.json"
+
+// ReportSummary counts the resources within the report.
+type ReportSummary struct {
+	// NewResources is the number of resources outside of Terraform control.
+	NewResources int `json:"newResources"`
+
+	// DriftedResources is the number of resource instances managed by Terraform with drifted attributes.
+	DriftedResources int `json:"driftedResources"`
+
+	// DeletedResources is the number of resources managed by Terraform which no longer exist in the cloud.
+	DeletedResources int `json:"deletedResources"`
+}
+
+// reportSummary counts the resources within the report.
+func (m *MarkdownCreator) reportSummary() ReportSummary {
+	driftedInstances := map[string]bool{}
+	for _, driftedResource := range m.managedDrift {
+		instance := fmt.Sprintf("%s.%s.%s.%s.%s", driftedResource.StateFileName, driftedResource.ModuleName,
+			driftedResource.ResourceType, driftedResource.ResourceName, driftedResource.InstanceID)
+		driftedInstances[instance] = true
+	}
+
+	return ReportSummary{
+		NewResources:     len(m.newResources),
+		DriftedResources: len(driftedInstances),
+		DeletedResources: len(m.deletedResources),
+	}
+}
+
+// writeReportSummary writes the counts of the resources within the report, replacing any previously written summary.
+func (m *MarkdownCreator) writeReportSummary() error {
+	err := os.Remove(ReportSummaryPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[os.Remove %v]%w", ReportSummaryPath, err)
+	}
+
+	jsonBytes, err := json.Marshal(m.reportSummary())
+	if err != nil {
+		return fmt.Errorf("[json.Marshal]%w", err)
+	}
+
+	err = os.WriteFile(ReportSummaryPath, jsonBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile %v]%w", ReportSummaryPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package markdowncreation
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownCreator_writeReportSummary(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	require.NoError(t, os.MkdirAll(OutputPath, 0o755))
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.newResources = map[string]string{
+		"aws_s3_bucket.logs": "dev",
+		"aws_s3_bucket.data": "dev",
+	}
+	markdownCreator.managedDrift = []ManagedDriftResource{
+		{StateFileName: "dev", ResourceType: "aws_vpc", ResourceName: "main", InstanceID: "vpc-1", AttributeName: "tags"},
+		{StateFileName: "dev", ResourceType: "aws_vpc", ResourceName: "main", InstanceID: "vpc-1", AttributeName: "cidr_block"},
+		{StateFileName: "prod", ResourceType: "aws_vpc", ResourceName: "main", InstanceID: "vpc-2", AttributeName: "tags"},
+	}
+	markdownCreator.deletedResources = []DeletedResource{
+		{StateFileName: "dev", ResourceType: "aws_subnet", ResourceName: "a", InstanceID: "subnet-1"},
+	}
+
+	// When
+	err = markdownCreator.writeReportSummary()
+	require.NoError(t, err)
+	err = markdownCreator.writeReportSummary()
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(ReportSummaryPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"newResources": 2, "driftedResources": 2, "deletedResources": 1}`, string(content))
+}
@@ -2,6 +2,7 @@ package markdowncreation
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/atsushinee/go-markdown-generator/doc"
 )
@@ -59,21 +60,33 @@ func (m *MarkdownCreator) setDriftedResourcesManagedByTerraformData(report *doc.
 		)
 	}
 
-	for stateFileName, resourcePathDriftedResources := range stateFileDriftedResources {
+	m.setDriftSeveritySummary(report)
+
+	for _, stateFileName := range sortKeysBySeverity(stateFileDriftedResources, func(resources ResourcePathDriftedResources) int {
+		return highestSeverityRank(flattenResourcePaths(resources))
+	}) {
+		resourcePathDriftedResources := stateFileDriftedResources[stateFileName]
 		report.Write(fmt.Sprintf("## State File `%s`", stateFileName)).Writeln().Writeln()
 
-		for resourcePath, instanceDriftedResources := range resourcePathDriftedResources {
+		for _, resourcePath := range sortKeysBySeverity(resourcePathDriftedResources, func(resources InstanceDriftedResources) int {
+			return highestSeverityRank(flattenInstances(resources))
+		}) {
+			instanceDriftedResources := resourcePathDriftedResources[resourcePath]
 			report.Write(fmt.Sprintf("### Resource: %s", resourcePath)).Writeln().Writeln()
 
-			for instanceID, driftedResources := range instanceDriftedResources {
+			for _, instanceID := range sortKeysBySeverity(instanceDriftedResources, highestSeverityRank) {
+				driftedResources := instanceDriftedResources[instanceID]
+				sortBySeverity(driftedResources)
+
 				report.Write(fmt.Sprintf("**Instance ID**: `%s`", instanceID)).Writeln().Writeln()
 				report.Write(fmt.Sprintf("**Most Recent Non-Terraform Actor**: `%s`", driftedResources[0].RecentActor)).Writeln()
 				report.Write(fmt.Sprintf("**Most Recent Action Date**: `%s`", driftedResources[0].RecentActionTimestamp)).Writeln().Writeln()
 				report.Write("- [ ] Completed").Writeln().Writeln()
 
-				report.Write("|Attribute|Terraform Value|Cloud Value|\n| :---: | :---: | :---: |\n")
+				report.Write("|Severity|Attribute|Terraform Value|Cloud Value|\n| :---: | :---: | :---: | :---: |\n")
 
 				for _, driftedResource := range driftedResources {
+					report.Write(fmt.Sprintf("|%s", driftedResource.Severity))
 					report.Write(fmt.Sprintf("|%s", driftedResource.AttributeName))
 					report.Write(fmt.Sprintf("|%s", driftedResource.TerraformValue))
 					report.Write(fmt.Sprintf("|%s|", driftedResource.CloudValue)).Writeln()
@@ -83,3 +96,87 @@ func (m *MarkdownCreator) setDriftedResourcesManagedByTerraformData(report *doc.
 		}
 	}
 }
+
+// severityOrder lists drift severities from most to least severe
+var severityOrder = []string{"critical", "high", "medium", "low"}
+
+// severityRank returns the rank of a drift severity, with larger values being more severe
+func severityRank(severity string) int {
+	for i, current := range severityOrder {
+		if current == severity {
+			return len(severityOrder) - i
+		}
+	}
+	return 0
+}
+
+// setDriftSeveritySummary sets a count of drifted attributes by severity in the markdown report
+func (m *MarkdownCreator) setDriftSeveritySummary(report *doc.MarkDownDoc) {
+	counts := map[string]int{}
+	for _, driftedResource := range m.managedDrift {
+		counts[driftedResource.Severity]++
+	}
+
+	report.Write("|Critical|High|Medium|Low|\n| :---: | :---: | :---: | :---: |\n")
+	for _, severity := range severityOrder {
+		report.Write(fmt.Sprintf("|%d", counts[severity]))
+	}
+	report.Write("|").Writeln().Writeln()
+}
+
+// highestSeverityRank returns the rank of the most severe drifted attribute
+func highestSeverityRank(driftedResources []ManagedDriftResource) int {
+	highest := 0
+	for _, driftedResource := range driftedResources {
+		if rank := severityRank(driftedResource.Severity); rank > highest {
+			highest = rank
+		}
+	}
+	return highest
+}
+
+// flattenInstances returns all drifted attributes across instances
+func flattenInstances(instanceDriftedResources InstanceDriftedResources) []ManagedDriftResource {
+	driftedResources := make([]ManagedDriftResource, 0)
+	for _, instanceResources := range instanceDriftedResources {
+		driftedResources = append(driftedResources, instanceResources...)
+	}
+	return driftedResources
+}
+
+// flattenResourcePaths returns all drifted attributes across resource paths
+func flattenResourcePaths(resourcePathDriftedResources ResourcePathDriftedResources) []ManagedDriftResource {
+	driftedResources := make([]ManagedDriftResource, 0)
+	for _, instanceDriftedResources := range resourcePathDriftedResources {
+		driftedResources = append(driftedResources, flattenInstances(instanceDriftedResources)...)
+	}
+	return driftedResources
+}
+
+// sortKeysBySeverity returns the keys of the map ordered by descending severity rank, then by key
+func sortKeysBySeverity[K ~string, V any](values map[K]V, rank func(V) int) []K {
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		rankI, rankJ := rank(values[keys[i]]), rank(values[keys[j]])
+		if rankI != rankJ {
+			return rankI > rankJ
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// sortBySeverity orders drifted attributes by descending severity, then by attribute name
+func sortBySeverity(driftedResources []ManagedDriftResource) {
+	sort.SliceStable(driftedResources, func(i, j int) bool {
+		rankI, rankJ := severityRank(driftedResources[i].Severity), severityRank(driftedResources[j].Severity)
+		if rankI != rankJ {
+			return rankI > rankJ
+		}
+		return driftedResources[i].AttributeName < driftedResources[j].AttributeName
+	})
+}
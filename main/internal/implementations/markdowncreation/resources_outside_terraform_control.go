@@ -3,6 +3,7 @@ package markdowncreation
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/atsushinee/go-markdown-generator/doc"
@@ -17,18 +18,56 @@ func (m *MarkdownCreator) setResourcesOutsideOfTerraformControlData(report *doc.
 		return
 	}
 
-	if len(m.costEstimates) == 0 {
+	resourcesDetailsByType := m.getCostsEstimations()
+	if len(m.costEstimates) == 0 || len(resourcesDetailsByType) == 0 || !m.atLeastOneValidResource(resourcesDetailsByType) {
 		m.resourcesWithoutCostEstimates(report)
-		return
+	} else {
+		m.resourcesWithCostEstimates(report, resourcesDetailsByType)
 	}
 
-	resourcesDetailsByType := m.getCostsEstimations()
-	if len(resourcesDetailsByType) == 0 || !m.atLeastOneValidResource(resourcesDetailsByType) {
-		m.resourcesWithoutCostEstimates(report)
+	m.generatedResourceNames(report)
+}
+
+// generatedResourceNames lists the address given to each resource written to Terraform configuration, marking
+// resources quarantined after failing validation
+func (m *MarkdownCreator) generatedResourceNames(report *doc.MarkDownDoc) {
+	if len(m.generatedNames) == 0 {
 		return
 	}
 
-	m.resourcesWithCostEstimates(report, resourcesDetailsByType)
+	quarantined := map[string]bool{}
+	for _, status := range m.planStatuses {
+		for _, address := range status.QuarantinedResources {
+			quarantined[status.Workspace+"/"+address] = true
+		}
+	}
+
+	generatedNames := make([]GeneratedResourceName, 0, len(m.generatedNames))
+	for _, generatedName := range m.generatedNames {
+		generatedNames = append(generatedNames, generatedName)
+	}
+	sort.Slice(generatedNames, func(i, j int) bool {
+		if generatedNames[i].Workspace != generatedNames[j].Workspace {
+			return generatedNames[i].Workspace < generatedNames[j].Workspace
+		}
+		return generatedNames[i].Address < generatedNames[j].Address
+	})
+
+	report.Write("## Generated Resource Addresses").Writeln().Writeln()
+	report.Write("|Address|Cloud ID|State File|Status|\n| :---: | :---: | :---: | :---: |\n")
+	for _, generatedName := range generatedNames {
+		status := "Codified"
+		if quarantined[generatedName.Workspace+"/"+generatedName.Address] {
+			status = "Quarantined, failed validation"
+		}
+
+		report.Write(fmt.Sprintf("|%s", generatedName.Address))
+		report.Write(fmt.Sprintf("|%s", generatedName.CloudID))
+		report.Write(fmt.Sprintf("|%s", generatedName.Workspace))
+		report.Write(fmt.Sprintf("|%s|", status)).Writeln()
+	}
+
+	report.Writeln()
 }
 
 // ResourceCostEstimate represents the cost estimate for a resource
@@ -243,3 +243,34 @@ func TestMarkdownCreator_setResourcesOutsideOfTerraformControlData_OnlyOneValidC
 	require.Contains(t, resourcesValues, "|aws_db_subnet_group|1|No Charge|No Charge|No Charge|")
 	require.Contains(t, resourcesValues, "|aws_lb_listener|1|1|$12.84|False|")
 }
+
+func TestMarkdownCreator_setResourcesOutsideOfTerraformControlData_GeneratedNames(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.newResources = map[string]string{
+		"aws_vpc.tfer--vpc-1": "terraform generated resource",
+		"aws_vpc.tfer--vpc-2": "terraform generated resource",
+	}
+	markdownCreator.generatedNames = map[string]GeneratedResourceName{
+		"aws_vpc.tfer--vpc-2": {Name: "vpc_main_2", Address: "aws_vpc.vpc_main_2", Workspace: "workspace", CloudID: "vpc-2"},
+		"aws_vpc.tfer--vpc-1": {Name: "vpc_main", Address: "aws_vpc.vpc_main", Workspace: "workspace", CloudID: "vpc-1"},
+	}
+	markdownCreator.planStatuses = []PlanStatus{
+		{Workspace: "other", QuarantinedResources: []string{"aws_vpc.vpc_main"}},
+		{Workspace: "workspace", QuarantinedResources: []string{"aws_vpc.vpc_main_2"}},
+	}
+
+	// When
+	markdownCreator.setResourcesOutsideOfTerraformControlData(report)
+
+	// Then
+	expected := "# Resources Outside of Terraform Control\n\n" +
+		"|Type|# Resources|\n| :---: | :---: |\n" +
+		"|aws_vpc|2|\n\n" +
+		"## Generated Resource Addresses\n\n" +
+		"|Address|Cloud ID|State File|Status|\n| :---: | :---: | :---: | :---: |\n" +
+		"|aws_vpc.vpc_main|vpc-1|workspace|Codified|\n" +
+		"|aws_vpc.vpc_main_2|vpc-2|workspace|Quarantined, failed validation|\n\n"
+	assert.Equal(t, expected, report.String())
+}
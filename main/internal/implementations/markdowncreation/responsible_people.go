@@ -0,0 +1,117 @@
+package markdowncreation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// RequestedReviewersPath is the path of the file listing the users requested to review the pull request because
+// they created unmanaged resources or caused drift within the report.
+const RequestedReviewersPath = OutputPath + "/requested-reviewers.json"
+
+// CloudActorIdentities represents the version control system usernames of cloud actors, and how they are notified
+type CloudActorIdentities struct {
+	Notification    string            `json:"notification"`
+	ActorToUsername map[string]string `json:"actorToUsername"`
+}
+
+// responsibleActors returns a map between the cloud actors who created or modified the reported resources and their
+// version control system username, excluding actors without a username.
+func (m *MarkdownCreator) responsibleActors() map[string]string {
+	actorToUsername := map[string]string{}
+	for _, cloudActions := range m.resourcesToCloudActions {
+		for _, detail := range cloudActions {
+			if username := m.cloudActorIdentities.ActorToUsername[detail.Actor]; username != "" {
+				actorToUsername[detail.Actor] = username
+			}
+		}
+	}
+	return actorToUsername
+}
+
+// responsibleUsernames returns the sorted, distinct usernames of the people responsible for the reported resources.
+func (m *MarkdownCreator) responsibleUsernames() []string {
+	usernameSet := map[string]bool{}
+	for _, username := range m.responsibleActors() {
+		usernameSet[username] = true
+	}
+
+	usernames := []string{}
+	for username := range usernameSet {
+		usernames = append(usernames, username)
+	}
+	sort.Strings(usernames)
+	return usernames
+}
+
+// setResponsiblePeopleData sets the people responsible for the reported resources, @-mentioning them when configured to
+func (m *MarkdownCreator) setResponsiblePeopleData(report *doc.MarkDownDoc) {
+	notification := m.cloudActorIdentities.Notification
+	if notification != "mention" && notification != "review" {
+		return
+	}
+
+	actorToUsername := m.responsibleActors()
+	if len(actorToUsername) == 0 {
+		return
+	}
+
+	report.Write("## People Responsible").Writeln().Writeln()
+
+	usernames := m.responsibleUsernames()
+	if notification == "mention" {
+		mentions := make([]string, 0, len(usernames))
+		for _, username := range usernames {
+			mentions = append(mentions, "@"+username)
+		}
+		report.Write(fmt.Sprintf("%s created resources outside of Terraform control or caused drift. Please review the changes below.",
+			strings.Join(mentions, " "))).Writeln().Writeln()
+	} else {
+		report.Write("The following people created resources outside of Terraform control or caused drift, and have been " +
+			"requested to review this pull request.").Writeln().Writeln()
+	}
+
+	report.Write("|Cloud Actor|User|\n| :---: | :---: |").Writeln()
+
+	actors := make([]string, 0, len(actorToUsername))
+	for actor := range actorToUsername {
+		actors = append(actors, actor)
+	}
+	sort.Strings(actors)
+
+	for _, actor := range actors {
+		report.Write(fmt.Sprintf("|%s|%s|", actor, actorToUsername[actor])).Writeln()
+	}
+
+	report.Writeln()
+}
+
+// writeRequestedReviewers writes the users requested to review the pull request when configured to, removing any
+// previously written list otherwise.
+func (m *MarkdownCreator) writeRequestedReviewers() error {
+	err := os.Remove(RequestedReviewersPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("[os.Remove %v]%w", RequestedReviewersPath, err)
+	}
+
+	if m.cloudActorIdentities.Notification != "review" {
+		return nil
+	}
+
+	jsonBytes, err := json.Marshal(m.responsibleUsernames())
+	if err != nil {
+		return fmt.Errorf("[json.Marshal]%w", err)
+	}
+
+	err = os.WriteFile(RequestedReviewersPath, jsonBytes, 0o400)
+	if err != nil {
+		return fmt.Errorf("[os.WriteFile %v]%w", RequestedReviewersPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func responsiblePeopleMarkdownCreator(notification string) *MarkdownCreator {
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.resourcesToCloudActions = map[string]map[string]CloudActionDetail{
+		"aws_s3_bucket.logs": {
+			"creation": {Actor: "arn:aws:sts::123:assumed-role/Admin/jane@corp.com", Timestamp: "2023-01-01"},
+		},
+		"dev.aws_vpc.main.123": {
+			"modified": {Actor: "arn:aws:iam::123:user/john", Timestamp: "2023-01-02"},
+		},
+		"dev.aws_subnet.a.123": {
+			"modified": {Actor: "arn:aws:iam::123:user/deploy-bot", Timestamp: "2023-01-03"},
+		},
+	}
+	markdownCreator.cloudActorIdentities = CloudActorIdentities{
+		Notification: notification,
+		ActorToUsername: map[string]string{
+			"arn:aws:sts::123:assumed-role/Admin/jane@corp.com": "jane-gh",
+			"arn:aws:iam::123:user/john":                        "john-gh",
+			"arn:aws:iam::123:user/outside-of-scope":            "other-gh",
+		},
+	}
+	return markdownCreator
+}
+
+func TestMarkdownCreator_setResponsiblePeopleData_Mention(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := responsiblePeopleMarkdownCreator("mention")
+
+	// When
+	markdownCreator.setResponsiblePeopleData(report)
+
+	// Then
+	title := "## People Responsible\n\n"
+	description := "@jane-gh @john-gh created resources outside of Terraform control or caused drift. Please review the changes below.\n\n"
+
+	tableHeaders := "|Cloud Actor|User|\n| :---: | :---: |\n"
+	tableContent := "|arn:aws:iam::123:user/john|john-gh|\n" +
+		"|arn:aws:sts::123:assumed-role/Admin/jane@corp.com|jane-gh|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s%s", title, description, tableHeaders, tableContent)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
+
+func TestMarkdownCreator_setResponsiblePeopleData_NoNotification(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := responsiblePeopleMarkdownCreator("none")
+
+	// When
+	markdownCreator.setResponsiblePeopleData(report)
+
+	// Then
+	assert.Equal(t, "", report.String())
+}
+
+func TestMarkdownCreator_writeRequestedReviewers(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	require.NoError(t, os.MkdirAll(OutputPath, 0o755))
+	markdownCreator := responsiblePeopleMarkdownCreator("review")
+
+	// When
+	err = markdownCreator.writeRequestedReviewers()
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(RequestedReviewersPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `["jane-gh", "john-gh"]`, string(content))
+
+	// When
+	markdownCreator.cloudActorIdentities.Notification = "mention"
+	err = markdownCreator.writeRequestedReviewers()
+
+	// Then
+	require.NoError(t, err)
+	_, err = os.Stat(RequestedReviewersPath)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,40 @@
+package markdowncreation
+
+import (
+	"fmt"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// setSecurityDriftData sets the data for drift that introduced security findings in the markdown report
+func (m *MarkdownCreator) setSecurityDriftData(report *doc.MarkDownDoc) {
+	report.Write("# Drift That Weakened Your Security Posture").Writeln().Writeln()
+
+	if len(m.securityDrift) == 0 {
+		report.Write("No drift weakened your security posture!").Writeln()
+		return
+	}
+
+	report.Write("The security findings below are present within the cloud but not within the Terraform definition of the resource, " +
+		"meaning they were introduced by drift.").Writeln().Writeln()
+
+	report.Write("|Resource|Instance ID|State File|Finding|Severity|Recent Actor|Action Date|Resolution|\n" +
+		"| :---: | :---: | :---: | :---: | :---: | :---: | :---: | :---: |\n")
+	for _, securityDrift := range m.securityDrift {
+		resourcePath := fmt.Sprintf("%s.%s", securityDrift.ResourceType, securityDrift.ResourceName)
+		if securityDrift.ModuleName != "" {
+			resourcePath = fmt.Sprintf("%s.%s", securityDrift.ModuleName, resourcePath)
+		}
+
+		report.Write(fmt.Sprintf("|%s", resourcePath))
+		report.Write(fmt.Sprintf("|%s", securityDrift.InstanceID))
+		report.Write(fmt.Sprintf("|%s", securityDrift.StateFileName))
+		report.Write(fmt.Sprintf("|%s", securityDrift.RuleDescription))
+		report.Write(fmt.Sprintf("|%s", securityDrift.Severity))
+		report.Write(fmt.Sprintf("|%s", securityDrift.RecentActor))
+		report.Write(fmt.Sprintf("|%s", securityDrift.RecentActionTimestamp))
+		report.Write(fmt.Sprintf("|%s|", securityDrift.Resolution)).Writeln()
+	}
+
+	report.Writeln()
+}
@@ -0,0 +1,57 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_setSecurityDriftData(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.securityDrift = []SecurityDrift{
+		{
+			InstanceID:            "sg-1",
+			StateFileName:         "workspace",
+			ModuleName:            "module.network",
+			ResourceType:          "aws_security_group",
+			ResourceName:          "web",
+			RecentActor:           "user@example.com",
+			RecentActionTimestamp: "2023-06-01",
+			RuleDescription:       "An ingress security group rule allows traffic from /0.",
+			Severity:              "CRITICAL",
+			Resolution:            "Set a more restrictive cidr range",
+		},
+	}
+
+	// When
+	markdownCreator.setSecurityDriftData(report)
+
+	// Then
+	title := "# Drift That Weakened Your Security Posture\n\n"
+	description := "The security findings below are present within the cloud but not within the Terraform definition of the resource, " +
+		"meaning they were introduced by drift.\n\n"
+
+	tableHeaders := "|Resource|Instance ID|State File|Finding|Severity|Recent Actor|Action Date|Resolution|\n" +
+		"| :---: | :---: | :---: | :---: | :---: | :---: | :---: | :---: |\n"
+	tableContent := "|module.network.aws_security_group.web|sg-1|workspace|An ingress security group rule allows traffic from /0." +
+		"|CRITICAL|user@example.com|2023-06-01|Set a more restrictive cidr range|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s%s", title, description, tableHeaders, tableContent)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
+
+func TestMarkdownCreator_setSecurityDriftData_Empty(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+
+	// When
+	markdownCreator.setSecurityDriftData(report)
+
+	// Then
+	assert.Equal(t, "# Drift That Weakened Your Security Posture\n\nNo drift weakened your security posture!\n", report.String())
+}
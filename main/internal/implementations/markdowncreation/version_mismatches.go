@@ -0,0 +1,40 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+)
+
+// setVersionMismatchesData sets the data for Terraform and provider versions pinned inconsistently across workspaces
+// in the markdown report. The section is omitted when every pin agrees.
+func (m *MarkdownCreator) setVersionMismatchesData(report *doc.MarkDownDoc) {
+	if len(m.versionMismatches) == 0 {
+		return
+	}
+
+	report.Write("# Version Mismatches").Writeln().Writeln()
+
+	report.Write("|Name|Workspace Pins|Version Used|Reason|\n| :---: | :---: | :---: | :---: |\n")
+	for _, mismatch := range m.versionMismatches {
+		workspaces := make([]string, 0, len(mismatch.WorkspaceToPin))
+		for workspace := range mismatch.WorkspaceToPin {
+			workspaces = append(workspaces, workspace)
+		}
+		sort.Strings(workspaces)
+
+		pins := make([]string, 0, len(workspaces))
+		for _, workspace := range workspaces {
+			pins = append(pins, fmt.Sprintf("%s: %s", workspace, mismatch.WorkspaceToPin[workspace]))
+		}
+
+		report.Write(fmt.Sprintf("|%s", mismatch.Name))
+		report.Write(fmt.Sprintf("|%s", strings.Join(pins, "<br>")))
+		report.Write(fmt.Sprintf("|%s", mismatch.Selected))
+		report.Write(fmt.Sprintf("|%s|", mismatch.Reason)).Writeln()
+	}
+
+	report.Writeln()
+}
@@ -0,0 +1,47 @@
+package markdowncreation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/atsushinee/go-markdown-generator/doc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownCreator_setVersionMismatchesData(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+	markdownCreator.versionMismatches = []VersionMismatch{
+		{
+			Name:           "aws",
+			Selected:       "5.31.0",
+			WorkspaceToPin: map[string]string{"prod": "5.31.0", "dev": "4.67.0"},
+			Reason:         "workspaces lock different provider versions within .terraform.lock.hcl",
+		},
+	}
+
+	// When
+	markdownCreator.setVersionMismatchesData(report)
+
+	// Then
+	title := "# Version Mismatches\n\n"
+
+	tableHeaders := "|Name|Workspace Pins|Version Used|Reason|\n| :---: | :---: | :---: | :---: |\n"
+	tableContent := "|aws|dev: 4.67.0<br>prod: 5.31.0|5.31.0|workspaces lock different provider versions within .terraform.lock.hcl|\n\n"
+
+	expectedMarkdown := fmt.Sprintf("%s%s%s", title, tableHeaders, tableContent)
+	assert.Equal(t, expectedMarkdown, report.String())
+}
+
+func TestMarkdownCreator_setVersionMismatchesData_NoMismatches(t *testing.T) {
+	// Given
+	report := doc.NewMarkDown()
+	markdownCreator := NewMarkdownCreator()
+
+	// When
+	markdownCreator.setVersionMismatchesData(report)
+
+	// Then
+	assert.Equal(t, "", report.String())
+}
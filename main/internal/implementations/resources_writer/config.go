@@ -0,0 +1,11 @@
+package resourceswriter
+
+// Config contains the values that determine how results are written to the version control system.
+type Config struct {
+	// JobName is the name of the current job.
+	JobName string
+
+	// PullRequestStrategy is how changes are split into pull requests, one of SinglePullRequestStrategy,
+	// WorkspacePullRequestStrategy or CategoryPullRequestStrategy.
+	PullRequestStrategy string
+}
@@ -16,23 +16,23 @@ type Factory struct{}
 
 // Instantiate creates an instance that implements the ResourcesWriter interface, with the implementation
 // depending on the current environment.
-func (f *Factory) Instantiate(environment string, vcs interfaces.VCS, provider terraformValueObjects.Provider, hclConfig hclcreate.Config, jobName string) (interfaces.ResourcesWriter, error) {
+func (f *Factory) Instantiate(environment string, vcs interfaces.VCS, provider terraformValueObjects.Provider, hclConfig hclcreate.Config, config Config) (interfaces.ResourcesWriter, error) {
 	switch environment {
 	case "isolated":
 		return new(IsolatedResourcesWriter), nil
 	default:
-		return f.bootstrappedResourceWriter(vcs, provider, hclConfig, jobName)
+		return f.bootstrappedResourceWriter(vcs, provider, hclConfig, config)
 	}
 }
 
 // bootstrappedResourceWriter creates a complete implementation of the ResourcesWriter interface with
 // configuration specified via environment variables.
-func (f *Factory) bootstrappedResourceWriter(vcs interfaces.VCS, provider terraformValueObjects.Provider, hclConfig hclcreate.Config, jobName string) (interfaces.ResourcesWriter, error) {
+func (f *Factory) bootstrappedResourceWriter(vcs interfaces.VCS, provider terraformValueObjects.Provider, hclConfig hclcreate.Config, config Config) (interfaces.ResourcesWriter, error) {
 	hclCreate, err := hclcreate.NewHCLCreate(hclConfig, provider)
 	if err != nil {
 		log.Errorf("[cannot instantiate hclCreate config]%s", err.Error())
 		return nil, fmt.Errorf("[cannot instantiate hclCreate config]%w", err)
 	}
 
-	return NewTerraformResourceWriter(hclCreate, vcs, markdowncreation.NewMarkdownCreator(), jobName), nil
+	return NewTerraformResourceWriter(hclCreate, vcs, markdowncreation.NewMarkdownCreator(), config), nil
 }
@@ -0,0 +1,678 @@
+package resourceswriter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/documentize"
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/markdowncreation"
+)
+
+const (
+	// SinglePullRequestStrategy opens a single pull request containing all changes.
+	SinglePullRequestStrategy = "single"
+
+	// WorkspacePullRequestStrategy opens one pull request per workspace with changes.
+	WorkspacePullRequestStrategy = "workspace"
+
+	// CategoryPullRequestStrategy opens one pull request per documentize resource category, such as networking or
+	// storage, splitting new resources, import blocks, removed blocks and tfmigrate migrations by the category of the
+	// resource they address into files named for the category, such as new-resources-storage.tf, so that the pull
+	// requests merge independently. Referenced resources and data sources follow the blocks referencing them, and
+	// provider configurations and other changes are included within each pull request.
+	CategoryPullRequestStrategy = "category"
+
+	// moduleCategory is the category of module calls, and of the resources addressed within them.
+	moduleCategory = "modules"
+
+	// sharedScopeName is the name of the pull request opened when no changes can be split by category.
+	sharedScopeName = "other"
+)
+
+// workspaceFile is the content and mode of a file within a workspace directory.
+type workspaceFile struct {
+	content []byte
+	mode    fs.FileMode
+}
+
+// workspaceFiles is a map between the paths of files within workspace directories and the file. A nil file within
+// a set of changes indicates that the file is deleted.
+type workspaceFiles map[string]*workspaceFile
+
+// pullRequestScope is the subset of a job's changes opened as a single pull request.
+type pullRequestScope struct {
+	// reportScope limits the pull request's report to the resources within scope.
+	reportScope markdowncreation.ReportScope
+
+	// changes are the changed files within scope.
+	changes workspaceFiles
+}
+
+// isSplittingPullRequests returns whether changes are split into several pull requests.
+func (w *TerraformResourceWriter) isSplittingPullRequests() bool {
+	return w.pullRequestStrategy == WorkspacePullRequestStrategy || w.pullRequestStrategy == CategoryPullRequestStrategy
+}
+
+// openScopedPullRequests splits the changes made since originalFiles into scopes, opening a pull request for each
+// from its own branch, and writes an index of the pull requests opened. Returns the url of each pull request on its
+// own line.
+func (w *TerraformResourceWriter) openScopedPullRequests(originalFiles workspaceFiles, workspaceToDirectory map[string]string) (string, error) {
+	changedFiles, err := snapshotWorkspaceFiles(workspaceToDirectory)
+	if err != nil {
+		return "", fmt.Errorf("[open_scoped_pull_requests][snapshotWorkspaceFiles]%w", err)
+	}
+
+	changes := diffWorkspaceFiles(originalFiles, changedFiles)
+
+	var scopes []pullRequestScope
+	if w.pullRequestStrategy == WorkspacePullRequestStrategy {
+		scopes = workspaceScopes(changes, workspaceToDirectory)
+	} else {
+		scopes, err = categoryScopes(changes, workspaceToDirectory)
+		if err != nil {
+			return "", fmt.Errorf("[open_scoped_pull_requests][categoryScopes]%w", err)
+		}
+	}
+
+	pullRequests := []markdowncreation.PullRequestSummary{}
+	prURLs := []string{}
+	for _, scope := range scopes {
+		scopedJobName := fmt.Sprintf("%v %v", w.jobName, scope.reportScope.Name)
+		logrus.Infof("[open_scoped_pull_requests] opening pull request for %v with %d changed files", scope.reportScope.Name, len(scope.changes))
+
+		err = restoreWorkspaceFiles(originalFiles, workspaceToDirectory)
+		if err != nil {
+			return "", fmt.Errorf("[open_scoped_pull_requests][restoreWorkspaceFiles]%w", err)
+		}
+
+		err = writeWorkspaceFiles(scope.changes)
+		if err != nil {
+			return "", fmt.Errorf("[open_scoped_pull_requests][writeWorkspaceFiles]%w", err)
+		}
+
+		err = w.formatGeneratedCode(workspaceToDirectory)
+		if err != nil {
+			return "", fmt.Errorf("[open_scoped_pull_requests]%w", err)
+		}
+
+		err = w.markdownCreator.CreateScopedMarkdownFile(w.jobName, scope.reportScope)
+		if err != nil {
+			return "", fmt.Errorf("[open_scoped_pull_requests][error in markdownCreator.CreateScopedMarkdownFile]%w", err)
+		}
+
+		err = w.vcs.Checkout(scopedJobName)
+		if err != nil {
+			return "", fmt.Errorf("[open_scoped_pull_requests][error in checkout to new branch with vcs]%w", err)
+		}
+
+		prURL, err := w.commitChangesOpenPullRequest(scopedJobName)
+		if err != nil {
+			return "", fmt.Errorf("[open_scoped_pull_requests]%w", err)
+		}
+
+		pullRequests = append(pullRequests, markdowncreation.PullRequestSummary{Scope: scope.reportScope.Name, URL: prURL})
+		prURLs = append(prURLs, prURL)
+	}
+
+	err = w.markdownCreator.CreatePullRequestIndex(w.jobName, pullRequests)
+	if err != nil {
+		return "", fmt.Errorf("[open_scoped_pull_requests][error in markdownCreator.CreatePullRequestIndex]%w", err)
+	}
+
+	return strings.Join(prURLs, "\n"), nil
+}
+
+// workspaceScopes groups changes by the workspace whose directory contains them, the deepest such directory when
+// workspace directories are nested.
+func workspaceScopes(changes workspaceFiles, workspaceToDirectory map[string]string) []pullRequestScope {
+	workspaceToChanges := map[string]workspaceFiles{}
+	for path, file := range changes {
+		workspace := owningWorkspace(path, workspaceToDirectory)
+		if workspaceToChanges[workspace] == nil {
+			workspaceToChanges[workspace] = workspaceFiles{}
+		}
+		workspaceToChanges[workspace][path] = file
+	}
+
+	scopes := []pullRequestScope{}
+	for _, workspace := range sortedKeys(workspaceToChanges) {
+		scopes = append(scopes, pullRequestScope{
+			reportScope: markdowncreation.ReportScope{Name: workspace, Workspaces: []string{workspace}},
+			changes:     workspaceToChanges[workspace],
+		})
+	}
+
+	return scopes
+}
+
+// owningWorkspace returns the workspace whose directory most closely contains path.
+func owningWorkspace(path string, workspaceToDirectory map[string]string) string {
+	owner, ownerDirectory := "", ""
+	for _, workspace := range sortedKeys(workspaceToDirectory) {
+		directory := filepath.Clean(fmt.Sprintf("repo%v", workspaceToDirectory[workspace])) + string(filepath.Separator)
+		if strings.HasPrefix(path, directory) && len(directory) > len(ownerDirectory) {
+			owner, ownerDirectory = workspace, directory
+		}
+	}
+	return owner
+}
+
+// categoryScopes splits the generated files within changes by the category of the resource each block addresses,
+// writing the blocks of each category to a file named for the category so that the pull requests merge independently.
+// Blocks referenced by other generated blocks of their workspace, such as subnets or data sources, are split into the
+// category of the blocks referencing them, and blocks addressing no resource, such as provider configurations, are
+// kept within the original file, identical within each scope. A workspace whose blocks are referenced from several
+// categories is opened as a single scope. Other changes are included within each scope, or make up a single scope
+// when nothing is split.
+func categoryScopes(changes workspaceFiles, workspaceToDirectory map[string]string) ([]pullRequestScope, error) {
+	sharedChanges := workspaceFiles{}
+	workspaceToChanges := map[string]workspaceFiles{}
+	for path, file := range changes {
+		if file == nil || !isSplittableFile(path) {
+			sharedChanges[path] = file
+			continue
+		}
+
+		workspace := owningWorkspace(path, workspaceToDirectory)
+		if workspaceToChanges[workspace] == nil {
+			workspaceToChanges[workspace] = workspaceFiles{}
+		}
+		workspaceToChanges[workspace][path] = file
+	}
+
+	categoryToPathBlocks := map[string]map[string][]*hclwrite.Block{}
+	pathToSharedBlocks := map[string][]*hclwrite.Block{}
+	fallbackScopes := []pullRequestScope{}
+	for _, workspace := range sortedKeys(workspaceToChanges) {
+		pathToBlocks := map[string][]*hclwrite.Block{}
+		blocks := []*hclwrite.Block{}
+		for _, path := range sortedKeys(workspaceToChanges[workspace]) {
+			hclFile, diagnostics := hclwrite.ParseConfig(workspaceToChanges[workspace][path].content, path, hcl.InitialPos)
+			if diagnostics.HasErrors() {
+				return nil, fmt.Errorf("[hclwrite.ParseConfig %v]%v", path, diagnostics)
+			}
+			pathToBlocks[path] = hclFile.Body().Blocks()
+			blocks = append(blocks, pathToBlocks[path]...)
+		}
+
+		addressToCategory, ok := resolveAddressCategories(blocks)
+		if !ok {
+			logrus.Warnf("[category_scopes] generated code of workspace %v is referenced across categories, opening a single pull request for it", workspace)
+			fallbackScopes = append(fallbackScopes, pullRequestScope{
+				reportScope: markdowncreation.ReportScope{Name: workspace, Workspaces: []string{workspace}},
+				changes:     workspaceToChanges[workspace],
+			})
+			continue
+		}
+
+		for path, pathBlocks := range pathToBlocks {
+			categoryToBlocks, sharedBlocks := splitByCategory(pathBlocks, addressToCategory)
+			for category, blocks := range categoryToBlocks {
+				if categoryToPathBlocks[category] == nil {
+					categoryToPathBlocks[category] = map[string][]*hclwrite.Block{}
+				}
+				categoryToPathBlocks[category][path] = blocks
+			}
+			if len(sharedBlocks) > 0 {
+				pathToSharedBlocks[path] = sharedBlocks
+			}
+		}
+	}
+
+	if len(categoryToPathBlocks) == 0 {
+		for path := range pathToSharedBlocks {
+			sharedChanges[path] = changes[path]
+		}
+	}
+
+	scopes := []pullRequestScope{}
+	for _, category := range sortedKeys(categoryToPathBlocks) {
+		scopeChanges := workspaceFiles{}
+		for path, blocks := range categoryToPathBlocks[category] {
+			scopeChanges[categoryPath(path, category)] = &workspaceFile{content: formatBlocks(blocks), mode: changes[path].mode}
+		}
+		for path, sharedBlocks := range pathToSharedBlocks {
+			scopeChanges[path] = &workspaceFile{content: formatBlocks(sharedBlocks), mode: changes[path].mode}
+		}
+
+		scopes = append(scopes, pullRequestScope{
+			reportScope: markdowncreation.ReportScope{Name: category, Category: category},
+			changes:     scopeChanges,
+		})
+	}
+	scopes = append(scopes, fallbackScopes...)
+
+	if len(scopes) == 0 {
+		if len(sharedChanges) == 0 {
+			return []pullRequestScope{}, nil
+		}
+		return []pullRequestScope{{reportScope: markdowncreation.ReportScope{Name: sharedScopeName}, changes: sharedChanges}}, nil
+	}
+
+	for _, scope := range scopes {
+		for path, file := range sharedChanges {
+			scope.changes[path] = file
+		}
+	}
+
+	return scopes, nil
+}
+
+// categoryPath returns the path to which the blocks of a generated file within a category are written. The category
+// is inserted before the suffix of cloud-concierge's underscore separated file names, such as "<id>_imports.tf", and
+// appended to other file names, such as "new-resources.tf".
+func categoryPath(path string, category string) string {
+	extension := filepath.Ext(path)
+	stem := strings.TrimSuffix(filepath.Base(path), extension)
+	category = strings.ReplaceAll(category, " ", "-")
+
+	if index := strings.LastIndex(stem, "_"); index > 0 {
+		return filepath.Join(filepath.Dir(path), fmt.Sprintf("%v_%v%v%v", stem[:index], category, stem[index:], extension))
+	}
+	return filepath.Join(filepath.Dir(path), fmt.Sprintf("%v-%v%v", stem, category, extension))
+}
+
+// isSplittableFile returns whether a file is generated code whose blocks can be split by category: new resources,
+// import blocks, removed blocks and tfmigrate migrations.
+func isSplittableFile(path string) bool {
+	directory := filepath.ToSlash(filepath.Dir(path))
+
+	switch {
+	case filepath.Base(path) == "new-resources.tf":
+		return true
+	case strings.HasSuffix(directory, "cloud-concierge/imports"), strings.HasSuffix(directory, "cloud-concierge/removed"):
+		return filepath.Ext(path) == ".tf"
+	case strings.HasSuffix(directory, "cloud-concierge/tfmigrate"):
+		return filepath.Ext(path) == ".hcl" && filepath.Base(path) != ".tfmigrate.hcl"
+	default:
+		return false
+	}
+}
+
+// splitByCategory splits the top level blocks of an HCL file by the category of the resource each addresses, with
+// referenced resources, data sources and module calls in the category within addressToCategory. The actions of
+// tfmigrate migration blocks are split by the resource each action addresses. Blocks addressing no resource are
+// returned separately.
+func splitByCategory(blocks []*hclwrite.Block, addressToCategory map[string]string) (map[string][]*hclwrite.Block, []*hclwrite.Block) {
+	categoryToBlocks := map[string][]*hclwrite.Block{}
+	sharedBlocks := []*hclwrite.Block{}
+	for _, block := range blocks {
+		if block.Type() == "migration" {
+			for category, migrationBlock := range splitMigrationBlock(block, addressToCategory) {
+				categoryToBlocks[category] = append(categoryToBlocks[category], migrationBlock)
+			}
+			continue
+		}
+
+		category, ok := blockCategory(block, addressToCategory)
+		if !ok {
+			sharedBlocks = append(sharedBlocks, block)
+			continue
+		}
+		categoryToBlocks[category] = append(categoryToBlocks[category], block)
+	}
+
+	return categoryToBlocks, sharedBlocks
+}
+
+// formatBlocks returns the formatted content of an HCL file containing blocks.
+func formatBlocks(blocks []*hclwrite.Block) []byte {
+	var buffer bytes.Buffer
+	for i, block := range blocks {
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		buffer.Write(block.BuildTokens(nil).Bytes())
+	}
+	return hclwrite.Format(buffer.Bytes())
+}
+
+// blockCategory returns the category of the resource a top level block defines or addresses, and false for blocks
+// addressing no resource.
+func blockCategory(block *hclwrite.Block, addressToCategory map[string]string) (string, bool) {
+	switch block.Type() {
+	case "resource", "data", "module":
+		if address := blockAddress(block); address != "" {
+			return addressToCategory[address], true
+		}
+	case "import":
+		return referenceCategory(attributeSource(block.Body(), "to"), addressToCategory), true
+	case "removed", "moved":
+		return referenceCategory(attributeSource(block.Body(), "from"), addressToCategory), true
+	}
+
+	return "", false
+}
+
+// resolveAddressCategories returns the category of each resource, data source and module call defined within
+// blocks. Those referenced by other blocks belong to the category of the blocks referencing them, so that each is
+// split alongside the blocks depending upon it. Returns false when a block is referenced from several categories.
+func resolveAddressCategories(blocks []*hclwrite.Block) (map[string]string, bool) {
+	addressToBlock := map[string]*hclwrite.Block{}
+	for _, block := range blocks {
+		if address := blockAddress(block); address != "" {
+			addressToBlock[address] = block
+		}
+	}
+
+	addressToReferrers := map[string][]string{}
+	for _, address := range sortedKeys(addressToBlock) {
+		for _, reference := range bodyReferences(addressToBlock[address].Body()) {
+			if _, ok := addressToBlock[reference]; ok && reference != address {
+				addressToReferrers[reference] = append(addressToReferrers[reference], address)
+			}
+		}
+	}
+
+	addressToCategories := map[string]map[string]bool{}
+	var referringCategories func(address string, visiting map[string]bool) map[string]bool
+	referringCategories = func(address string, visiting map[string]bool) map[string]bool {
+		if categories, ok := addressToCategories[address]; ok {
+			return categories
+		}
+
+		categories := map[string]bool{}
+		if len(addressToReferrers[address]) == 0 {
+			categories[definedCategory(addressToBlock[address])] = true
+		}
+
+		visiting[address] = true
+		for _, referrer := range addressToReferrers[address] {
+			if visiting[referrer] {
+				continue
+			}
+			for category := range referringCategories(referrer, visiting) {
+				categories[category] = true
+			}
+		}
+		delete(visiting, address)
+
+		addressToCategories[address] = categories
+		return categories
+	}
+
+	addressToCategory := map[string]string{}
+	for _, address := range sortedKeys(addressToBlock) {
+		categories := referringCategories(address, map[string]bool{})
+		if len(categories) > 1 {
+			return nil, false
+		}
+
+		addressToCategory[address] = definedCategory(addressToBlock[address])
+		for category := range categories {
+			addressToCategory[address] = category
+		}
+	}
+
+	return addressToCategory, true
+}
+
+// blockAddress returns the Terraform address of the resource, data source or module call a block defines, or an
+// empty string for other blocks.
+func blockAddress(block *hclwrite.Block) string {
+	labels := block.Labels()
+	switch {
+	case block.Type() == "resource" && len(labels) == 2:
+		return fmt.Sprintf("%v.%v", labels[0], labels[1])
+	case block.Type() == "data" && len(labels) == 2:
+		return fmt.Sprintf("data.%v.%v", labels[0], labels[1])
+	case block.Type() == "module" && len(labels) == 1:
+		return fmt.Sprintf("module.%v", labels[0])
+	default:
+		return ""
+	}
+}
+
+// definedCategory returns the category of the resource, data source or module call a block defines.
+func definedCategory(block *hclwrite.Block) string {
+	if block.Type() == "module" {
+		return moduleCategory
+	}
+	return documentize.PrimaryCategory(block.Labels()[0])
+}
+
+// bodyReferences returns the addresses of the resources, data sources and module calls referenced within a body and
+// its nested blocks.
+func bodyReferences(body *hclwrite.Body) []string {
+	references := []string{}
+	for _, name := range sortedKeys(body.Attributes()) {
+		for _, traversal := range body.GetAttribute(name).Expr().Variables() {
+			if address := referenceAddress(string(traversal.BuildTokens(nil).Bytes())); address != "" {
+				references = append(references, address)
+			}
+		}
+	}
+
+	for _, block := range body.Blocks() {
+		references = append(references, bodyReferences(block.Body())...)
+	}
+	return references
+}
+
+// referenceAddress returns the address of the resource, data source or module call a reference or Terraform address
+// begins with, without instance keys.
+func referenceAddress(reference string) string {
+	parts := strings.Split(strings.Trim(strings.TrimSpace(reference), `"'`), ".")
+
+	length := 2
+	if parts[0] == "data" {
+		length = 3
+	}
+	if len(parts) < length {
+		return ""
+	}
+
+	for i := range parts[:length] {
+		parts[i] = strings.TrimSpace(strings.SplitN(parts[i], "[", 2)[0])
+	}
+	return strings.Join(parts[:length], ".")
+}
+
+// referenceCategory returns the category of the resource at a Terraform address, using the category within
+// addressToCategory of resources defined by generated code.
+func referenceCategory(address string, addressToCategory map[string]string) string {
+	if category, ok := addressToCategory[referenceAddress(address)]; ok {
+		return category
+	}
+	return addressCategory(address)
+}
+
+// splitMigrationBlock splits the actions of a tfmigrate migration block by the category of the resource each
+// action addresses, returning a copy of the block for each category.
+func splitMigrationBlock(block *hclwrite.Block, addressToCategory map[string]string) map[string]*hclwrite.Block {
+	categoryToActions := map[string][]cty.Value{}
+	for _, action := range migrationActions(block.Body()) {
+		fields := strings.Fields(action)
+		category := documentize.UncategorizedCategory
+		if len(fields) > 1 {
+			category = referenceCategory(fields[1], addressToCategory)
+		}
+		categoryToActions[category] = append(categoryToActions[category], cty.StringVal(action))
+	}
+
+	categoryToBlock := map[string]*hclwrite.Block{}
+	for category, actions := range categoryToActions {
+		migrationBlock := hclwrite.NewBlock(block.Type(), block.Labels())
+		for _, name := range sortedKeys(block.Body().Attributes()) {
+			if name == "actions" {
+				continue
+			}
+			migrationBlock.Body().SetAttributeRaw(name, block.Body().GetAttribute(name).Expr().BuildTokens(nil))
+		}
+		migrationBlock.Body().SetAttributeValue("actions", cty.ListVal(actions))
+		categoryToBlock[category] = migrationBlock
+	}
+
+	return categoryToBlock
+}
+
+// migrationActions returns the actions of a tfmigrate migration block.
+func migrationActions(body *hclwrite.Body) []string {
+	attribute := body.GetAttribute("actions")
+	if attribute == nil {
+		return nil
+	}
+
+	expression, diagnostics := hclsyntax.ParseExpression(attribute.Expr().BuildTokens(nil).Bytes(), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return nil
+	}
+
+	value, diagnostics := expression.Value(nil)
+	if diagnostics.HasErrors() || !value.CanIterateElements() {
+		return nil
+	}
+
+	actions := []string{}
+	for iterator := value.ElementIterator(); iterator.Next(); {
+		_, element := iterator.Element()
+		if element.Type() == cty.String && element.IsKnown() && !element.IsNull() {
+			actions = append(actions, element.AsString())
+		}
+	}
+	return actions
+}
+
+// attributeSource returns the source of an attribute's expression.
+func attributeSource(body *hclwrite.Body, name string) string {
+	attribute := body.GetAttribute(name)
+	if attribute == nil {
+		return ""
+	}
+	return string(attribute.Expr().BuildTokens(nil).Bytes())
+}
+
+// addressCategory returns the category of the resource at a Terraform address. Resources within modules belong to
+// the same category as the module call.
+func addressCategory(address string) string {
+	address = strings.Trim(strings.TrimSpace(address), `"'`)
+	if strings.HasPrefix(address, "module.") {
+		return moduleCategory
+	}
+
+	address = strings.TrimPrefix(address, "data.")
+	return documentize.PrimaryCategory(strings.Split(address, ".")[0])
+}
+
+// snapshotWorkspaceFiles reads the files within each workspace directory, ignoring git and Terraform working files.
+func snapshotWorkspaceFiles(workspaceToDirectory map[string]string) (workspaceFiles, error) {
+	files := workspaceFiles{}
+	for _, workspace := range sortedKeys(workspaceToDirectory) {
+		directory := fmt.Sprintf("repo%v", workspaceToDirectory[workspace])
+
+		err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				if entry.Name() == ".git" || entry.Name() == ".terraform" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			path = filepath.Clean(path)
+			if _, ok := files[path]; ok {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			files[path] = &workspaceFile{content: content, mode: info.Mode().Perm()}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("[filepath.WalkDir %v]%w", directory, err)
+		}
+	}
+
+	return files, nil
+}
+
+// diffWorkspaceFiles returns the files changed between two snapshots, with a nil file for those deleted.
+func diffWorkspaceFiles(originalFiles workspaceFiles, changedFiles workspaceFiles) workspaceFiles {
+	changes := workspaceFiles{}
+	for path, file := range changedFiles {
+		if originalFile, ok := originalFiles[path]; !ok || !bytes.Equal(originalFile.content, file.content) {
+			changes[path] = file
+		}
+	}
+
+	for path := range originalFiles {
+		if _, ok := changedFiles[path]; !ok {
+			changes[path] = nil
+		}
+	}
+
+	return changes
+}
+
+// restoreWorkspaceFiles returns each workspace directory to the files within originalFiles.
+func restoreWorkspaceFiles(originalFiles workspaceFiles, workspaceToDirectory map[string]string) error {
+	currentFiles, err := snapshotWorkspaceFiles(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[snapshotWorkspaceFiles]%w", err)
+	}
+
+	return writeWorkspaceFiles(diffWorkspaceFiles(currentFiles, originalFiles))
+}
+
+// writeWorkspaceFiles writes each file, deleting those that are nil.
+func writeWorkspaceFiles(files workspaceFiles) error {
+	for _, path := range sortedKeys(files) {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("[os.Remove %v]%w", path, err)
+		}
+
+		file := files[path]
+		if file == nil {
+			continue
+		}
+
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			return fmt.Errorf("[os.MkdirAll %v]%w", filepath.Dir(path), err)
+		}
+
+		err = os.WriteFile(path, file.content, file.mode)
+		if err != nil {
+			return fmt.Errorf("[os.WriteFile %v]%w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
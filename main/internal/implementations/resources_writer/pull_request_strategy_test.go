@@ -0,0 +1,334 @@
+package resourceswriter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/markdowncreation"
+)
+
+func TestWorkspaceScopes(t *testing.T) {
+	// Given
+	workspaceToDirectory := map[string]string{"dev": "/dev/", "dev-network": "/dev/network/", "prod": "/prod/"}
+	changes := workspaceFiles{
+		filepath.Join("repo", "dev", "new-resources.tf"):            {content: []byte("dev")},
+		filepath.Join("repo", "dev", "network", "new-resources.tf"): {content: []byte("dev-network")},
+		filepath.Join("repo", "prod", "main.tf"):                    nil,
+	}
+
+	// When
+	scopes := workspaceScopes(changes, workspaceToDirectory)
+
+	// Then
+	expectedScopes := []pullRequestScope{
+		{
+			reportScope: markdowncreation.ReportScope{Name: "dev", Workspaces: []string{"dev"}},
+			changes:     workspaceFiles{filepath.Join("repo", "dev", "new-resources.tf"): {content: []byte("dev")}},
+		},
+		{
+			reportScope: markdowncreation.ReportScope{Name: "dev-network", Workspaces: []string{"dev-network"}},
+			changes:     workspaceFiles{filepath.Join("repo", "dev", "network", "new-resources.tf"): {content: []byte("dev-network")}},
+		},
+		{
+			reportScope: markdowncreation.ReportScope{Name: "prod", Workspaces: []string{"prod"}},
+			changes:     workspaceFiles{filepath.Join("repo", "prod", "main.tf"): nil},
+		},
+	}
+	assert.Equal(t, expectedScopes, scopes)
+}
+
+func TestCategoryScopes(t *testing.T) {
+	// Given
+	newResourcesPath := filepath.Join("repo", "dev", "new-resources.tf")
+	importsPath := filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_imports.tf")
+	migrationPath := filepath.Join("repo", "dev", "cloud-concierge", "tfmigrate", "abc_migrations.hcl")
+	sharedPath := filepath.Join("repo", "dev", "main.tf")
+
+	changes := workspaceFiles{
+		newResourcesPath: {content: []byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+`), mode: 0o644},
+		importsPath: {content: []byte(`import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+
+import {
+  to = aws_vpc.main
+  id = "vpc-123"
+}
+`), mode: 0o644},
+		migrationPath: {content: []byte(`migration "state" "abc" {
+  dir     = "/github/workspace/dev/"
+  actions = ["import aws_s3_bucket.assets assets", "import aws_vpc.edge vpc-456"]
+}
+`), mode: 0o644},
+		sharedPath: {content: []byte("provider \"aws\" {}\n"), mode: 0o644},
+	}
+
+	// When
+	scopes, err := categoryScopes(changes, map[string]string{"dev": "/dev/"})
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, scopes, 2)
+	assertMergeableScopes(t, scopes)
+
+	networking := scopes[0]
+	assert.Equal(t, markdowncreation.ReportScope{Name: "networking", Category: "networking"}, networking.reportScope)
+	assert.Equal(t, `resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+`, string(networking.changes[filepath.Join("repo", "dev", "new-resources-networking.tf")].content))
+	assert.Equal(t, `import {
+  to = aws_vpc.main
+  id = "vpc-123"
+}
+`, string(networking.changes[filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_networking_imports.tf")].content))
+	assert.Equal(t, `migration "state" "abc" {
+  dir     = "/github/workspace/dev/"
+  actions = ["import aws_vpc.edge vpc-456"]
+}
+`, string(networking.changes[filepath.Join("repo", "dev", "cloud-concierge", "tfmigrate", "abc_networking_migrations.hcl")].content))
+	assert.Equal(t, changes[sharedPath], networking.changes[sharedPath])
+	assert.NotContains(t, networking.changes, newResourcesPath)
+
+	storage := scopes[1]
+	assert.Equal(t, markdowncreation.ReportScope{Name: "storage", Category: "storage"}, storage.reportScope)
+	assert.Equal(t, `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`, string(storage.changes[filepath.Join("repo", "dev", "new-resources-storage.tf")].content))
+	assert.Equal(t, `migration "state" "abc" {
+  dir     = "/github/workspace/dev/"
+  actions = ["import aws_s3_bucket.assets assets"]
+}
+`, string(storage.changes[filepath.Join("repo", "dev", "cloud-concierge", "tfmigrate", "abc_storage_migrations.hcl")].content))
+	assert.Equal(t, changes[sharedPath], storage.changes[sharedPath])
+}
+
+func TestCategoryScopes_ProvidersAndReferencedBlocks(t *testing.T) {
+	// Given
+	newResourcesPath := filepath.Join("repo", "dev", "new-resources.tf")
+	importsPath := filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_imports.tf")
+
+	changes := workspaceFiles{
+		newResourcesPath: {content: []byte(`resource "aws_instance" "web" {
+  provider               = aws.us-west-2
+  subnet_id              = aws_subnet.private.id
+  vpc_security_group_ids = [data.aws_security_group.network_web.id]
+}
+
+resource "aws_subnet" "private" {
+  provider   = aws.us-west-2
+  cidr_block = "10.0.1.0/24"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+data "aws_security_group" "network_web" {
+  id = "sg-123"
+}
+
+provider "aws" {
+  alias  = "us-west-2"
+  region = "us-west-2"
+}
+`), mode: 0o644},
+		importsPath: {content: []byte(`import {
+  to = aws_subnet.private
+  id = "subnet-123"
+}
+
+import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+`), mode: 0o644},
+	}
+
+	// When
+	scopes, err := categoryScopes(changes, map[string]string{"dev": "/dev/"})
+
+	// Then
+	require.NoError(t, err)
+	require.Len(t, scopes, 2)
+	assertMergeableScopes(t, scopes)
+
+	providers := `provider "aws" {
+  alias  = "us-west-2"
+  region = "us-west-2"
+}
+`
+
+	compute := scopes[0]
+	assert.Equal(t, markdowncreation.ReportScope{Name: "compute", Category: "compute"}, compute.reportScope)
+	assert.Equal(t, `resource "aws_instance" "web" {
+  provider               = aws.us-west-2
+  subnet_id              = aws_subnet.private.id
+  vpc_security_group_ids = [data.aws_security_group.network_web.id]
+}
+
+resource "aws_subnet" "private" {
+  provider   = aws.us-west-2
+  cidr_block = "10.0.1.0/24"
+}
+
+data "aws_security_group" "network_web" {
+  id = "sg-123"
+}
+`, string(compute.changes[filepath.Join("repo", "dev", "new-resources-compute.tf")].content))
+	assert.Equal(t, providers, string(compute.changes[newResourcesPath].content))
+	assert.Equal(t, `import {
+  to = aws_subnet.private
+  id = "subnet-123"
+}
+`, string(compute.changes[filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_compute_imports.tf")].content))
+
+	storage := scopes[1]
+	assert.Equal(t, markdowncreation.ReportScope{Name: "storage", Category: "storage"}, storage.reportScope)
+	assert.Equal(t, `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`, string(storage.changes[filepath.Join("repo", "dev", "new-resources-storage.tf")].content))
+	assert.Equal(t, providers, string(storage.changes[newResourcesPath].content))
+	assert.Equal(t, `import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+`, string(storage.changes[filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_storage_imports.tf")].content))
+}
+
+func TestCategoryScopes_ReferencedAcrossCategories(t *testing.T) {
+	// Given
+	workspaceToDirectory := map[string]string{"dev": "/dev/", "prod": "/prod/"}
+	devNewResourcesPath := filepath.Join("repo", "dev", "new-resources.tf")
+	prodNewResourcesPath := filepath.Join("repo", "prod", "new-resources.tf")
+	sharedPath := filepath.Join("repo", "dev", "main.tf")
+
+	changes := workspaceFiles{
+		devNewResourcesPath: {content: []byte(`resource "aws_instance" "web" {
+  subnet_id = aws_subnet.private.id
+}
+
+resource "aws_db_instance" "main" {
+  subnet_ids = [aws_subnet.private.id]
+}
+
+resource "aws_subnet" "private" {
+  cidr_block = "10.0.1.0/24"
+}
+`), mode: 0o644},
+		prodNewResourcesPath: {content: []byte(`resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`), mode: 0o644},
+		sharedPath: {content: []byte("locals {}\n"), mode: 0o644},
+	}
+
+	// When
+	scopes, err := categoryScopes(changes, workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+	expectedScopes := []pullRequestScope{
+		{
+			reportScope: markdowncreation.ReportScope{Name: "storage", Category: "storage"},
+			changes: workspaceFiles{
+				filepath.Join("repo", "prod", "new-resources-storage.tf"): changes[prodNewResourcesPath],
+				sharedPath: changes[sharedPath],
+			},
+		},
+		{
+			reportScope: markdowncreation.ReportScope{Name: "dev", Workspaces: []string{"dev"}},
+			changes: workspaceFiles{
+				devNewResourcesPath: changes[devNewResourcesPath],
+				sharedPath:          changes[sharedPath],
+			},
+		},
+	}
+	assert.Equal(t, expectedScopes, scopes)
+	assertMergeableScopes(t, scopes)
+}
+
+// assertMergeableScopes asserts that no path is changed with different contents by two scopes, so that the pull
+// requests opened for them merge without conflicts.
+func assertMergeableScopes(t *testing.T, scopes []pullRequestScope) {
+	pathToContent := map[string]string{}
+	for _, scope := range scopes {
+		for path, file := range scope.changes {
+			content := "<deleted>"
+			if file != nil {
+				content = string(file.content)
+			}
+
+			if existing, ok := pathToContent[path]; ok {
+				assert.Equal(t, existing, content, "%v differs between scopes", path)
+			}
+			pathToContent[path] = content
+		}
+	}
+}
+
+func TestCategoryPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("repo", "dev", "new-resources-compute.tf"), categoryPath(filepath.Join("repo", "dev", "new-resources.tf"), "compute"))
+	assert.Equal(t, filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_ci-cd_imports.tf"),
+		categoryPath(filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_imports.tf"), "ci cd"))
+	assert.Equal(t, filepath.Join("repo", "dev", "cloud-concierge", "removed", "abc_bucket_storage_removed.tf"),
+		categoryPath(filepath.Join("repo", "dev", "cloud-concierge", "removed", "abc_bucket_removed.tf"), "storage"))
+}
+
+func TestCategoryScopes_NoSplittableChanges(t *testing.T) {
+	// Given
+	changes := workspaceFiles{filepath.Join("repo", "dev", "main.tf"): {content: []byte("locals {}\n"), mode: 0o644}}
+
+	// When
+	scopes, err := categoryScopes(changes, map[string]string{"dev": "/dev/"})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []pullRequestScope{{reportScope: markdowncreation.ReportScope{Name: "other"}, changes: changes}}, scopes)
+}
+
+func TestRestoreWorkspaceFiles(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	workspaceToDirectory := map[string]string{"dev": "/dev/"}
+	require.NoError(t, os.MkdirAll(filepath.Join("repo", "dev"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join("repo", "dev", "main.tf"), []byte("original"), 0o644))
+
+	originalFiles, err := snapshotWorkspaceFiles(workspaceToDirectory)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join("repo", "dev", "main.tf"), []byte("changed"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join("repo", "dev", "cloud-concierge", "imports"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_imports.tf"), []byte("import {}"), 0o400))
+
+	// When
+	err = restoreWorkspaceFiles(originalFiles, workspaceToDirectory)
+
+	// Then
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join("repo", "dev", "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+
+	_, err = os.Stat(filepath.Join("repo", "dev", "cloud-concierge", "imports", "abc_imports.tf"))
+	assert.True(t, os.IsNotExist(err))
+}
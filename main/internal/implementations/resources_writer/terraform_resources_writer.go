@@ -26,15 +26,25 @@ type TerraformResourceWriter struct {
 
 	// jobName is the name of the current job
 	jobName string
+
+	// pullRequestStrategy is how changes are split into pull requests
+	pullRequestStrategy string
 }
 
 // NewTerraformResourceWriter instantiates and returns a new instance of the TerraformResourceWriter.
-func NewTerraformResourceWriter(hclCreate hclcreate.HCLCreate, vcs interfaces.VCS, markdownCreator *markdowncreation.MarkdownCreator, jobName string) interfaces.ResourcesWriter {
-	return &TerraformResourceWriter{hclCreate: hclCreate, vcs: vcs, jobName: jobName, markdownCreator: markdownCreator}
+func NewTerraformResourceWriter(hclCreate hclcreate.HCLCreate, vcs interfaces.VCS, markdownCreator *markdowncreation.MarkdownCreator, config Config) interfaces.ResourcesWriter {
+	return &TerraformResourceWriter{
+		hclCreate:           hclCreate,
+		vcs:                 vcs,
+		jobName:             config.JobName,
+		pullRequestStrategy: config.PullRequestStrategy,
+		markdownCreator:     markdownCreator,
+	}
 }
 
 // Execute writes new resources to the relevant version control system,
-// and returns a pull request url corresponding to the new changes.
+// and returns a pull request url corresponding to the new changes. When changes are split into several
+// pull requests, the url of each is returned on its own line.
 func (w *TerraformResourceWriter) Execute(ctx context.Context, createDummyFile bool, workspaceToDirectory map[string]string) (string, error) {
 	logrus.Debugf("[terraform_resource_writer] Executing with jobName: %v, createDummyFile: %v, workspaceToDirectory: %v", w.jobName, createDummyFile, workspaceToDirectory)
 
@@ -43,17 +53,43 @@ func (w *TerraformResourceWriter) Execute(ctx context.Context, createDummyFile b
 		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
 	}
 
+	var originalFiles workspaceFiles
+	if w.isSplittingPullRequests() {
+		originalFiles, err = snapshotWorkspaceFiles(workspaceToDirectory)
+		if err != nil {
+			return "", fmt.Errorf("[terraform_resource_writer][snapshotWorkspaceFiles]%w", err)
+		}
+	}
+
 	err = w.writeNewResourcesAndMigrationStatements(ctx, createDummyFile, workspaceToDirectory)
 	if err != nil {
 		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
 	}
 
+	err = w.validateGeneratedCode(workspaceToDirectory)
+	if err != nil {
+		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
+	if w.isSplittingPullRequests() {
+		prURLs, err := w.openScopedPullRequests(originalFiles, workspaceToDirectory)
+		if err != nil {
+			return "", fmt.Errorf("[terraform_resource_writer]%w", err)
+		}
+		return prURLs, nil
+	}
+
+	err = w.formatGeneratedCode(workspaceToDirectory)
+	if err != nil {
+		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
+	}
+
 	err = w.writeNewMarkdownAnalysis()
 	if err != nil {
 		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
 	}
 
-	prURL, err := w.commitChangesOpenPullRequest()
+	prURL, err := w.commitChangesOpenPullRequest(w.jobName)
 	if err != nil {
 		return "", fmt.Errorf("[terraform_resource_writer]%w", err)
 	}
@@ -61,9 +97,9 @@ func (w *TerraformResourceWriter) Execute(ctx context.Context, createDummyFile b
 }
 
 // commitChangesOpenPullRequest adds new files to the VCS, commits the changes,
-// and opens a pull request for the branch.
-func (w *TerraformResourceWriter) commitChangesOpenPullRequest() (string, error) {
-	logrus.Debugf("[commit_changes_open_pull_request] Executing with jobName: %v", w.jobName)
+// and opens a pull request for the branch titled with jobName.
+func (w *TerraformResourceWriter) commitChangesOpenPullRequest(jobName string) (string, error) {
+	logrus.Debugf("[commit_changes_open_pull_request] Executing with jobName: %v", jobName)
 
 	err := w.vcs.AddChanges()
 	if err != nil {
@@ -80,7 +116,7 @@ func (w *TerraformResourceWriter) commitChangesOpenPullRequest() (string, error)
 		return "", fmt.Errorf("[commit_changes_open_pull_request][error in vcs.Push]%w", err)
 	}
 
-	prURL, err := w.vcs.OpenPullRequest(w.jobName)
+	prURL, err := w.vcs.OpenPullRequest(jobName)
 	if err != nil {
 		return "", fmt.Errorf("[commit_changes_open_pull_request][error in vcs.OpenPullRequest]%w", err)
 	}
@@ -110,7 +146,12 @@ func (w *TerraformResourceWriter) writeNewResourcesAndMigrationStatements(ctx co
 			return fmt.Errorf("[write_new_resources_and_migration_statements][error in write_dummy_file]%w", err)
 		}
 
-		return nil
+		err = w.remediateDrift(workspaceToDirectory)
+		if err != nil {
+			return fmt.Errorf("[write_new_resources_and_migration_statements]%w", err)
+		}
+
+		return w.writeStateRemovals(workspaceToDirectory)
 	}
 
 	err := w.hclCreate.ExtractResourceDefinitions(createDummyFile, workspaceToDirectory)
@@ -128,6 +169,65 @@ func (w *TerraformResourceWriter) writeNewResourcesAndMigrationStatements(ctx co
 		return fmt.Errorf("[write_new_resources_and_migration_statements][error in hclc.CreateImports]%w", err)
 	}
 
+	err = w.remediateDrift(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[write_new_resources_and_migration_statements]%w", err)
+	}
+
+	return w.writeStateRemovals(workspaceToDirectory)
+}
+
+// remediateDrift rewrites drifted attributes of managed resources to their cloud values, when configured to
+// accept the cloud state.
+func (w *TerraformResourceWriter) remediateDrift(workspaceToDirectory map[string]string) error {
+	err := w.hclCreate.RemediateDrift(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[remediate_drift][error in hclc.RemediateDrift]%w", err)
+	}
+
+	return nil
+}
+
+// validateGeneratedCode validates the generated code of each touched workspace within a sandbox, quarantining new
+// resources that fail validation, when configured to.
+func (w *TerraformResourceWriter) validateGeneratedCode(workspaceToDirectory map[string]string) error {
+	err := w.hclCreate.ValidateGeneratedCode(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[validate_generated_code][error in hclc.ValidateGeneratedCode]%w", err)
+	}
+
+	return nil
+}
+
+// formatGeneratedCode rewrites the generated code of each workspace in the configured output format. Runs after
+// validation, which quarantines failing new resources by editing their HCL.
+func (w *TerraformResourceWriter) formatGeneratedCode(workspaceToDirectory map[string]string) error {
+	err := w.hclCreate.FormatGeneratedCode(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[format_generated_code][error in hclc.FormatGeneratedCode]%w", err)
+	}
+
+	return nil
+}
+
+// writeStateRemovals writes the removed blocks or tfmigrate migrations needed to resolve resources
+// managed by more than one state file and resources whose cloud resource no longer exists.
+func (w *TerraformResourceWriter) writeStateRemovals(workspaceToDirectory map[string]string) error {
+	id, err := w.vcs.GetID()
+	if err != nil {
+		return fmt.Errorf("[write_state_removals][error getting the vcs id]%w", err)
+	}
+
+	err = w.hclCreate.CreateDuplicatedResourceRemovals(id, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[write_state_removals][error in hclc.CreateDuplicatedResourceRemovals]%w", err)
+	}
+
+	err = w.hclCreate.CreateDeletedResourceRemovals(id, workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[write_state_removals][error in hclc.CreateDeletedResourceRemovals]%w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,372 @@
+package driftdetector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// CodeDriftResource represents a resource whose definition in code and entry in state do not match.
+type CodeDriftResource struct {
+	StateFileName StateFileName
+	ModuleName    string
+	ResourceType  string
+	ResourceName  string
+}
+
+// WorkspaceConfiguration contains the resource, module, moved and removed blocks defined within
+// the root module of a workspace's Terraform configuration.
+type WorkspaceConfiguration struct {
+	// Resources maps resource addresses of the form "type.name" to whether the resource must
+	// have instances within state once applied. Resources with a literal count of zero are omitted.
+	Resources map[string]bool
+
+	// Modules is the set of module call names defined within the workspace.
+	Modules map[string]bool
+
+	// MovedFrom is the set of addresses referenced by the "from" attribute of moved blocks.
+	MovedFrom map[string]bool
+
+	// MovedTo is the set of addresses referenced by the "to" attribute of moved blocks.
+	MovedTo map[string]bool
+
+	// Removed is the set of addresses referenced by the "from" attribute of removed blocks.
+	Removed map[string]bool
+}
+
+// identifyAndWriteCodeDrift cross-checks each workspace's Terraform configuration against its downloaded
+// state file, writing resources that are not yet applied and state entries without configuration.
+func (m *ManagedResourcesDriftDetector) identifyAndWriteCodeDrift(workspaceToDirectory map[string]string) (bool, error) {
+	unapplied := make([]CodeDriftResource, 0)
+	withoutConfiguration := make([]CodeDriftResource, 0)
+
+	for workspace, directory := range workspaceToDirectory {
+		configuration, err := parseWorkspaceConfiguration(fmt.Sprintf("repo%v", directory))
+		if err != nil {
+			return false, fmt.Errorf("[parseWorkspaceConfiguration][workspace %v]%w", workspace, err)
+		}
+
+		fileContent, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", workspace))
+		if err != nil {
+			return false, fmt.Errorf("failed to read state file %s: %v", workspace, err)
+		}
+
+		stateFile, err := m.parseRemoteStateFile(fileContent)
+		if err != nil {
+			return false, fmt.Errorf("[m.parseRemoteStateFile]%w", err)
+		}
+
+		workspaceUnapplied, workspaceWithoutConfiguration := m.identifyCodeDrift(workspace, configuration, stateFile)
+		unapplied = append(unapplied, workspaceUnapplied...)
+		withoutConfiguration = append(withoutConfiguration, workspaceWithoutConfiguration...)
+	}
+
+	err := writeCodeDriftResources("outputs/drift-resources-unapplied.json", unapplied)
+	if err != nil {
+		return false, fmt.Errorf("[writeCodeDriftResources][unapplied]%w", err)
+	}
+
+	err = writeCodeDriftResources("outputs/drift-resources-without-configuration.json", withoutConfiguration)
+	if err != nil {
+		return false, fmt.Errorf("[writeCodeDriftResources][without configuration]%w", err)
+	}
+
+	return len(unapplied) > 0 || len(withoutConfiguration) > 0, nil
+}
+
+// identifyCodeDrift compares a workspace configuration with its state file, returning resources defined in
+// configuration but missing from state, and managed state entries that no longer have a configuration.
+func (m *ManagedResourcesDriftDetector) identifyCodeDrift(
+	workspace string, configuration WorkspaceConfiguration, stateFile TerraformStateFile,
+) ([]CodeDriftResource, []CodeDriftResource) {
+	unapplied := make([]CodeDriftResource, 0)
+	withoutConfiguration := make([]CodeDriftResource, 0)
+
+	rootStateAddresses := map[string]bool{}
+	for _, resource := range stateFile.Resources {
+		if resource.Mode != "managed" || !m.isValidDeletedResource(resource.Type) {
+			continue
+		}
+
+		if resource.Module != "" {
+			moduleCallName := rootModuleCallName(resource.Module)
+			moduleCallAddress := fmt.Sprintf("module.%v", moduleCallName)
+			if !configuration.Modules[moduleCallName] && !configuration.MovedFrom[moduleCallAddress] && !configuration.Removed[moduleCallAddress] {
+				withoutConfiguration = append(withoutConfiguration, newCodeDriftResource(workspace, resource.Module, resource.Type, resource.Name))
+			}
+			continue
+		}
+
+		address := fmt.Sprintf("%v.%v", resource.Type, resource.Name)
+		rootStateAddresses[address] = true
+
+		_, configured := configuration.Resources[address]
+		if !configured && !configuration.MovedFrom[address] && !configuration.Removed[address] {
+			withoutConfiguration = append(withoutConfiguration, newCodeDriftResource(workspace, "", resource.Type, resource.Name))
+		}
+	}
+
+	configuredAddresses := make([]string, 0, len(configuration.Resources))
+	for address := range configuration.Resources {
+		configuredAddresses = append(configuredAddresses, address)
+	}
+	sort.Strings(configuredAddresses)
+
+	for _, address := range configuredAddresses {
+		if !configuration.Resources[address] || rootStateAddresses[address] || configuration.MovedTo[address] {
+			continue
+		}
+
+		resourceType, resourceName, _ := strings.Cut(address, ".")
+		if !m.isValidDeletedResource(resourceType) {
+			continue
+		}
+		unapplied = append(unapplied, newCodeDriftResource(workspace, "", resourceType, resourceName))
+	}
+
+	return unapplied, withoutConfiguration
+}
+
+// newCodeDriftResource is a helper for generating a CodeDriftResource value.
+func newCodeDriftResource(workspace string, module string, resourceType string, resourceName string) CodeDriftResource {
+	return CodeDriftResource{
+		StateFileName: StateFileName(workspace),
+		ModuleName:    module,
+		ResourceType:  resourceType,
+		ResourceName:  resourceName,
+	}
+}
+
+// rootModuleCallName extracts the name of the root module call from a state module address,
+// for example "module.network[0].module.subnets" becomes "network".
+func rootModuleCallName(moduleAddress string) string {
+	segments := strings.Split(moduleAddress, ".")
+	if len(segments) < 2 {
+		return moduleAddress
+	}
+
+	name, _, _ := strings.Cut(segments[1], "[")
+	return name
+}
+
+// parseWorkspaceConfiguration reads all .tf and .tf.json files within the specified directory and extracts
+// the resource, module, moved and removed blocks that they define.
+func parseWorkspaceConfiguration(directory string) (WorkspaceConfiguration, error) {
+	configuration := WorkspaceConfiguration{
+		Resources: map[string]bool{},
+		Modules:   map[string]bool{},
+		MovedFrom: map[string]bool{},
+		MovedTo:   map[string]bool{},
+		Removed:   map[string]bool{},
+	}
+
+	files, err := os.ReadDir(directory)
+	if err != nil {
+		return WorkspaceConfiguration{}, fmt.Errorf("[os.ReadDir]%w", err)
+	}
+
+	for _, file := range files {
+		isJSON := strings.HasSuffix(file.Name(), ".tf.json")
+		if file.IsDir() || (!strings.HasSuffix(file.Name(), ".tf") && !isJSON) {
+			continue
+		}
+
+		content, err := os.ReadFile(fmt.Sprintf("%v/%v", strings.TrimSuffix(directory, "/"), file.Name()))
+		if err != nil {
+			return WorkspaceConfiguration{}, fmt.Errorf("[os.ReadFile]%w", err)
+		}
+
+		if isJSON {
+			err = addJSONToConfiguration(configuration, content)
+			if err != nil {
+				return WorkspaceConfiguration{}, fmt.Errorf("[addJSONToConfiguration][%v]%w", file.Name(), err)
+			}
+			continue
+		}
+
+		hclFile, diagnostics := hclwrite.ParseConfig(content, file.Name(), hcl.Pos{Line: 1, Column: 1})
+		if diagnostics.HasErrors() {
+			return WorkspaceConfiguration{}, fmt.Errorf("[hclwrite.ParseConfig][%v]%v", file.Name(), diagnostics.Error())
+		}
+
+		addBlocksToConfiguration(configuration, hclFile.Body().Blocks())
+	}
+
+	return configuration, nil
+}
+
+// addBlocksToConfiguration adds the relevant top level blocks of a Terraform file to the WorkspaceConfiguration.
+func addBlocksToConfiguration(configuration WorkspaceConfiguration, blocks []*hclwrite.Block) {
+	for _, block := range blocks {
+		labels := block.Labels()
+
+		switch block.Type() {
+		case "resource":
+			if len(labels) == 2 && attributeExpression(block, "count") != "0" {
+				configuration.Resources[fmt.Sprintf("%v.%v", labels[0], labels[1])] = isExpectedInState(block)
+			}
+		case "module":
+			if len(labels) == 1 {
+				configuration.Modules[labels[0]] = true
+			}
+		case "moved":
+			if from := attributeExpression(block, "from"); from != "" {
+				configuration.MovedFrom[from] = true
+			}
+			if to := attributeExpression(block, "to"); to != "" {
+				configuration.MovedTo[to] = true
+			}
+		case "removed":
+			if from := attributeExpression(block, "from"); from != "" {
+				configuration.Removed[from] = true
+			}
+		}
+	}
+}
+
+// addJSONToConfiguration adds the relevant top level blocks of a Terraform JSON file to the WorkspaceConfiguration.
+func addJSONToConfiguration(configuration WorkspaceConfiguration, content []byte) error {
+	var file struct {
+		Resource map[string]map[string]json.RawMessage `json:"resource"`
+		Module   map[string]json.RawMessage            `json:"module"`
+		Moved    json.RawMessage                       `json:"moved"`
+		Removed  json.RawMessage                       `json:"removed"`
+	}
+
+	err := json.Unmarshal(content, &file)
+	if err != nil {
+		return fmt.Errorf("[json.Unmarshal]%w", err)
+	}
+
+	for resourceType, resources := range file.Resource {
+		for resourceName, body := range resources {
+			bodies, err := jsonBlockBodies(body)
+			if err != nil {
+				return fmt.Errorf("[jsonBlockBodies][%v.%v]%w", resourceType, resourceName, err)
+			}
+
+			for _, body := range bodies {
+				count, hasCount := body["count"]
+				if hasCount && strings.TrimSpace(string(count)) == "0" {
+					continue
+				}
+
+				_, hasForEach := body["for_each"]
+				configuration.Resources[fmt.Sprintf("%v.%v", resourceType, resourceName)] = !hasForEach &&
+					(!hasCount || isNumericLiteral(strings.Trim(string(count), `"`)))
+			}
+		}
+	}
+
+	for moduleName := range file.Module {
+		configuration.Modules[moduleName] = true
+	}
+
+	movedBodies, err := jsonBlockBodies(file.Moved)
+	if err != nil {
+		return fmt.Errorf("[jsonBlockBodies][moved]%w", err)
+	}
+	for _, body := range movedBodies {
+		if from := jsonStringAttribute(body, "from"); from != "" {
+			configuration.MovedFrom[from] = true
+		}
+		if to := jsonStringAttribute(body, "to"); to != "" {
+			configuration.MovedTo[to] = true
+		}
+	}
+
+	removedBodies, err := jsonBlockBodies(file.Removed)
+	if err != nil {
+		return fmt.Errorf("[jsonBlockBodies][removed]%w", err)
+	}
+	for _, body := range removedBodies {
+		if from := jsonStringAttribute(body, "from"); from != "" {
+			configuration.Removed[from] = true
+		}
+	}
+
+	return nil
+}
+
+// jsonBlockBodies returns the bodies of a block within a Terraform JSON file, which may be either a single object
+// or an array of objects.
+func jsonBlockBodies(value json.RawMessage) ([]map[string]json.RawMessage, error) {
+	trimmed := strings.TrimSpace(string(value))
+	if trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(trimmed, "[") {
+		var bodies []map[string]json.RawMessage
+		err := json.Unmarshal(value, &bodies)
+		return bodies, err
+	}
+
+	var body map[string]json.RawMessage
+	err := json.Unmarshal(value, &body)
+	return []map[string]json.RawMessage{body}, err
+}
+
+// jsonStringAttribute returns the value of a string attribute within a Terraform JSON block body, or an empty
+// string if the attribute is not defined or is not a string.
+func jsonStringAttribute(body map[string]json.RawMessage, name string) string {
+	var value string
+	if json.Unmarshal(body[name], &value) != nil {
+		return ""
+	}
+	return value
+}
+
+// isExpectedInState determines whether a resource block must be represented in state once applied. Resources whose
+// count or for_each depends on an expression may legitimately have zero instances, so they are not expected.
+func isExpectedInState(block *hclwrite.Block) bool {
+	if block.Body().GetAttribute("for_each") != nil {
+		return false
+	}
+
+	if count := attributeExpression(block, "count"); count != "" {
+		return isNumericLiteral(count)
+	}
+
+	return true
+}
+
+// attributeExpression returns the source text of an attribute's expression within a block, or an empty
+// string if the attribute is not defined.
+func attributeExpression(block *hclwrite.Block, name string) string {
+	attribute := block.Body().GetAttribute(name)
+	if attribute == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(attribute.Expr().BuildTokens(nil).Bytes()))
+}
+
+// isNumericLiteral returns true if the input string only contains digits.
+func isNumericLiteral(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, character := range value {
+		if character < '0' || character > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// writeCodeDriftResources writes within a json file the code drift resources to render within the PR
+func writeCodeDriftResources(path string, resources []CodeDriftResource) error {
+	resourcesJSON, err := json.MarshalIndent(resources, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%w", err)
+	}
+
+	return os.WriteFile(path, resourcesJSON, 0o400)
+}
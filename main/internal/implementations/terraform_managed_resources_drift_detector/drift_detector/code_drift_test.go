@@ -0,0 +1,147 @@
+package driftdetector
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWorkspaceConfiguration(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	configuration := `
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket" "disabled" {
+  count  = 0
+  bucket = "disabled"
+}
+
+resource "aws_s3_bucket" "conditional" {
+  count  = var.enabled ? 1 : 0
+  bucket = "conditional"
+}
+
+resource "aws_s3_bucket" "many" {
+  for_each = toset(["a", "b"])
+  bucket   = each.key
+}
+
+module "network" {
+  source = "./network"
+}
+
+moved {
+  from = aws_s3_bucket.old_logs
+  to   = aws_s3_bucket.logs
+}
+
+removed {
+  from = aws_s3_bucket.legacy
+}
+`
+	err := os.WriteFile(directory+"/main.tf", []byte(configuration), 0o600)
+	require.NoError(t, err)
+	err = os.WriteFile(directory+"/README.md", []byte("not terraform"), 0o600)
+	require.NoError(t, err)
+
+	// When
+	got, err := parseWorkspaceConfiguration(directory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, WorkspaceConfiguration{
+		Resources: map[string]bool{
+			"aws_s3_bucket.logs":        true,
+			"aws_s3_bucket.conditional": false,
+			"aws_s3_bucket.many":        false,
+		},
+		Modules:   map[string]bool{"network": true},
+		MovedFrom: map[string]bool{"aws_s3_bucket.old_logs": true},
+		MovedTo:   map[string]bool{"aws_s3_bucket.logs": true},
+		Removed:   map[string]bool{"aws_s3_bucket.legacy": true},
+	}, got)
+}
+
+func TestParseWorkspaceConfiguration_JSON(t *testing.T) {
+	// Given
+	directory := t.TempDir()
+	configuration := `{
+  "resource": {
+    "aws_s3_bucket": {
+      "logs": {"bucket": "logs"},
+      "disabled": {"count": 0, "bucket": "disabled"},
+      "conditional": {"count": "${var.enabled ? 1 : 0}", "bucket": "conditional"},
+      "many": [{"for_each": "${toset([\"a\", \"b\"])}", "bucket": "${each.key}"}]
+    }
+  },
+  "module": {
+    "network": {"source": "./network"}
+  },
+  "moved": [
+    {"from": "aws_s3_bucket.old_logs", "to": "aws_s3_bucket.logs"}
+  ],
+  "removed": {"from": "aws_s3_bucket.legacy"}
+}`
+	err := os.WriteFile(directory+"/main.tf.json", []byte(configuration), 0o600)
+	require.NoError(t, err)
+
+	// When
+	got, err := parseWorkspaceConfiguration(directory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, WorkspaceConfiguration{
+		Resources: map[string]bool{
+			"aws_s3_bucket.logs":        true,
+			"aws_s3_bucket.conditional": false,
+			"aws_s3_bucket.many":        false,
+		},
+		Modules:   map[string]bool{"network": true},
+		MovedFrom: map[string]bool{"aws_s3_bucket.old_logs": true},
+		MovedTo:   map[string]bool{"aws_s3_bucket.logs": true},
+		Removed:   map[string]bool{"aws_s3_bucket.legacy": true},
+	}, got)
+}
+
+func TestIdentifyCodeDrift(t *testing.T) {
+	// Given
+	detector := NewManagedResourcesDriftDetector(ManagedResourceDriftDetectorConfig{})
+	configuration := WorkspaceConfiguration{
+		Resources: map[string]bool{
+			"aws_s3_bucket.logs":        true,
+			"aws_s3_bucket.new":         true,
+			"aws_s3_bucket.conditional": false,
+		},
+		Modules:   map[string]bool{"network": true},
+		MovedFrom: map[string]bool{"aws_s3_bucket.old_logs": true},
+		MovedTo:   map[string]bool{"aws_s3_bucket.logs": true},
+		Removed:   map[string]bool{"aws_s3_bucket.legacy": true},
+	}
+	stateFile := TerraformStateFile{
+		Resources: []*Resource{
+			{Mode: "managed", Type: "aws_s3_bucket", Name: "old_logs"},
+			{Mode: "managed", Type: "aws_s3_bucket", Name: "legacy"},
+			{Mode: "managed", Type: "aws_s3_bucket", Name: "orphan"},
+			{Mode: "data", Type: "aws_caller_identity", Name: "current"},
+			{Mode: "managed", Module: "module.network[0]", Type: "aws_vpc", Name: "main"},
+			{Mode: "managed", Module: "module.dns", Type: "aws_route53_zone", Name: "main"},
+		},
+	}
+
+	// When
+	unapplied, withoutConfiguration := detector.identifyCodeDrift("workspace", configuration, stateFile)
+
+	// Then
+	assert.Equal(t, []CodeDriftResource{
+		{StateFileName: "workspace", ModuleName: "", ResourceType: "aws_s3_bucket", ResourceName: "new"},
+	}, unapplied)
+	assert.Equal(t, []CodeDriftResource{
+		{StateFileName: "workspace", ModuleName: "", ResourceType: "aws_s3_bucket", ResourceName: "orphan"},
+		{StateFileName: "workspace", ModuleName: "module.dns", ResourceType: "aws_route53_zone", ResourceName: "main"},
+	}, withoutConfiguration)
+}
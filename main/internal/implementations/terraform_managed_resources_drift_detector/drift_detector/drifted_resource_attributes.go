@@ -0,0 +1,42 @@
+package driftdetector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DriftedResourceAttributes contains the full set of flat attributes of a drifted resource instance, both as
+// recorded within Terraform state and as currently found within the cloud.
+type DriftedResourceAttributes struct {
+	InstanceID          string
+	TerraformAttributes map[string]string
+	CloudAttributes     map[string]string
+	AttributeDetail
+}
+
+// newDriftedResourceAttributes creates a DriftedResourceAttributes value for the resource instance that the
+// AttributeDifference belongs to.
+func newDriftedResourceAttributes(
+	difference AttributeDifference,
+	terraformAttributes map[string]string,
+	cloudAttributes map[string]string,
+) DriftedResourceAttributes {
+	return DriftedResourceAttributes{
+		InstanceID:          difference.InstanceID,
+		TerraformAttributes: terraformAttributes,
+		CloudAttributes:     cloudAttributes,
+		AttributeDetail:     difference.AttributeDetail,
+	}
+}
+
+// writeDriftedResourceAttributes writes within a json file the attributes of all drifted resources, for use when
+// comparing the security posture of the Terraform and cloud representations of each resource.
+func (m *ManagedResourcesDriftDetector) writeDriftedResourceAttributes(driftedResourceAttributes []DriftedResourceAttributes) error {
+	driftedResourceAttributesJSON, err := json.MarshalIndent(driftedResourceAttributes, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%w", err)
+	}
+
+	return os.WriteFile("outputs/drift-resources-attributes.json", driftedResourceAttributesJSON, 0o400)
+}
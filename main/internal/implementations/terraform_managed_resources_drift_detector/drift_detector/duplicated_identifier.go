@@ -0,0 +1,201 @@
+package driftdetector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ResourceIDToClaims is a map between a resource's unique id and every state file address that manages it.
+type ResourceIDToClaims map[string][]ResourceClaim
+
+// ResourceClaim represents a single state file address that manages a cloud resource.
+type ResourceClaim struct {
+	StateFileName StateFileName
+	ModuleName    string
+	ResourceName  string
+
+	// Address is the full Terraform address of the resource instance within its state file.
+	Address string
+
+	// Configured is true when the claim's resource is still defined within its workspace's configuration.
+	Configured bool
+
+	// ShouldResolve is true when the state file should relinquish management of the resource.
+	ShouldResolve bool
+}
+
+// DuplicatedResource represents a cloud resource that is managed by more than one state file.
+type DuplicatedResource struct {
+	InstanceID   string
+	ResourceType string
+	Claims       []ResourceClaim
+}
+
+// addResourceClaims adds a claim for every managed resource instance within the state file to resourceIDToClaims.
+func (m *ManagedResourcesDriftDetector) addResourceClaims(resourceIDToClaims ResourceIDToClaims, stateFileName string, stateFile TerraformStateFile) {
+	for _, resource := range stateFile.Resources {
+		if resource.Mode != "managed" {
+			continue
+		}
+
+		for _, instance := range resource.Instances {
+			identifier := claimIdentifier(instance)
+			if identifier == "" {
+				continue
+			}
+
+			id := fmt.Sprintf("%v.%v", resource.Type, identifier)
+			resourceIDToClaims[id] = append(resourceIDToClaims[id], ResourceClaim{
+				StateFileName: StateFileName(stateFileName),
+				ModuleName:    resource.Module,
+				ResourceName:  resource.Name,
+				Address:       resourceInstanceAddress(resource, instance),
+			})
+		}
+	}
+}
+
+// claimIdentifierAttributes are the attributes identifying a cloud resource, in order of preference. Attributes
+// such as "arn" and "self_link" are globally unique, while an "id" such as an IAM role name may only be unique within
+// a single account or project.
+var claimIdentifierAttributes = []string{"arn", "self_link", "id"}
+
+// claimIdentifier returns the most unique non-empty identifier of a resource instance, or an empty string if the
+// instance has none.
+func claimIdentifier(instance ResourceInstance) string {
+	for _, attribute := range claimIdentifierAttributes {
+		if value, ok := instance.Attributes[attribute].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// resourceInstanceAddress generates the full Terraform address of a resource instance, for example
+// module.network.aws_subnet.private["a"].
+func resourceInstanceAddress(resource *Resource, instance ResourceInstance) string {
+	address := fmt.Sprintf("%v.%v", resource.Type, resource.Name)
+	if resource.Module != "" {
+		address = fmt.Sprintf("%v.%v", resource.Module, address)
+	}
+
+	switch key := instance.IndexKey.(type) {
+	case float64:
+		address += fmt.Sprintf("[%v]", int(key))
+	case string:
+		address += fmt.Sprintf("[%q]", key)
+	}
+
+	return address
+}
+
+// identifyAndWriteDuplicatedResources identifies cloud resources claimed by more than one state file and writes them
+// in the mapping file.
+func (m *ManagedResourcesDriftDetector) identifyAndWriteDuplicatedResources(resourceIDToClaims ResourceIDToClaims, workspaceToDirectory map[string]string) (bool, error) {
+	workspaceToConfiguration := map[string]WorkspaceConfiguration{}
+	for workspace, directory := range workspaceToDirectory {
+		configuration, err := parseWorkspaceConfiguration(fmt.Sprintf("repo%v", directory))
+		if err != nil {
+			return false, fmt.Errorf("[parseWorkspaceConfiguration][workspace %v]%w", workspace, err)
+		}
+		workspaceToConfiguration[workspace] = configuration
+	}
+
+	duplicated := m.identifyDuplicatedResources(resourceIDToClaims, workspaceToConfiguration)
+
+	duplicatedJSON, err := json.MarshalIndent(duplicated, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("[json.MarshalIndent]%w", err)
+	}
+
+	err = os.WriteFile("outputs/drift-resources-duplicated.json", duplicatedJSON, 0o400)
+	if err != nil {
+		return false, fmt.Errorf("[os.WriteFile]%w", err)
+	}
+
+	return len(duplicated) > 0, nil
+}
+
+// identifyDuplicatedResources returns the resources claimed by more than one state file. Within each duplicate, the
+// first claim whose address is still defined in its workspace's configuration is kept, or the first claim if none
+// is. Only claims without configuration are marked to be resolved, as removing a configured resource from state
+// would either conflict with its resource block or cause it to be recreated. Duplicates with more than one
+// configured claim are therefore only reported.
+func (m *ManagedResourcesDriftDetector) identifyDuplicatedResources(
+	resourceIDToClaims ResourceIDToClaims, workspaceToConfiguration map[string]WorkspaceConfiguration,
+) []DuplicatedResource {
+	resourceIDs := make([]string, 0, len(resourceIDToClaims))
+	for id := range resourceIDToClaims {
+		resourceIDs = append(resourceIDs, id)
+	}
+	sort.Strings(resourceIDs)
+
+	duplicated := make([]DuplicatedResource, 0)
+	for _, id := range resourceIDs {
+		claims := resourceIDToClaims[id]
+		if !claimedByMultipleStateFiles(claims) {
+			continue
+		}
+
+		resourceType, instanceID, _ := strings.Cut(id, ".")
+		if !m.isValidDeletedResource(resourceType) {
+			continue
+		}
+
+		sortedClaims := make([]ResourceClaim, len(claims))
+		copy(sortedClaims, claims)
+		sort.SliceStable(sortedClaims, func(i, j int) bool {
+			if sortedClaims[i].StateFileName != sortedClaims[j].StateFileName {
+				return sortedClaims[i].StateFileName < sortedClaims[j].StateFileName
+			}
+			return sortedClaims[i].Address < sortedClaims[j].Address
+		})
+
+		ownerIndex := -1
+		for i := range sortedClaims {
+			sortedClaims[i].Configured = sortedClaims[i].isConfigured(resourceType, workspaceToConfiguration[string(sortedClaims[i].StateFileName)])
+			if sortedClaims[i].Configured && ownerIndex == -1 {
+				ownerIndex = i
+			}
+		}
+
+		if ownerIndex == -1 {
+			ownerIndex = 0
+		}
+
+		for i := range sortedClaims {
+			sortedClaims[i].ShouldResolve = i != ownerIndex && !sortedClaims[i].Configured
+		}
+
+		duplicated = append(duplicated, DuplicatedResource{
+			InstanceID:   instanceID,
+			ResourceType: resourceType,
+			Claims:       sortedClaims,
+		})
+	}
+
+	return duplicated
+}
+
+// isConfigured returns true if the claim's resource is defined within the workspace configuration.
+func (c ResourceClaim) isConfigured(resourceType string, configuration WorkspaceConfiguration) bool {
+	if c.ModuleName != "" {
+		return configuration.Modules[rootModuleCallName(c.ModuleName)]
+	}
+
+	_, configured := configuration.Resources[fmt.Sprintf("%v.%v", resourceType, c.ResourceName)]
+	return configured
+}
+
+// claimedByMultipleStateFiles returns true if the claims span more than one state file.
+func claimedByMultipleStateFiles(claims []ResourceClaim) bool {
+	for _, claim := range claims[1:] {
+		if claim.StateFileName != claims[0].StateFileName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package driftdetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifyDuplicatedResources(t *testing.T) {
+	// Given
+	m := NewManagedResourcesDriftDetector(ManagedResourceDriftDetectorConfig{})
+	resourceIDToClaims := ResourceIDToClaims{}
+
+	m.addResourceClaims(resourceIDToClaims, "workspace-b", TerraformStateFile{
+		Resources: []*Resource{
+			{
+				Mode: "managed", Type: "aws_s3_bucket", Name: "logs",
+				Instances: []ResourceInstance{{Attributes: map[string]interface{}{"id": "my-logs"}}},
+			},
+			{
+				Mode: "managed", Type: "aws_s3_bucket", Name: "unique",
+				Instances: []ResourceInstance{{Attributes: map[string]interface{}{"id": "my-unique"}}},
+			},
+		},
+	})
+	m.addResourceClaims(resourceIDToClaims, "workspace-a", TerraformStateFile{
+		Resources: []*Resource{
+			{
+				Mode: "managed", Module: "module.storage", Type: "aws_s3_bucket", Name: "buckets",
+				Instances: []ResourceInstance{{IndexKey: "logs", Attributes: map[string]interface{}{"id": "my-logs"}}},
+			},
+			{
+				Mode: "data", Type: "aws_s3_bucket", Name: "unique",
+				Instances: []ResourceInstance{{Attributes: map[string]interface{}{"id": "my-unique"}}},
+			},
+		},
+	})
+
+	workspaceToConfiguration := map[string]WorkspaceConfiguration{
+		"workspace-a": {Modules: map[string]bool{}, Resources: map[string]bool{}},
+		"workspace-b": {Modules: map[string]bool{}, Resources: map[string]bool{"aws_s3_bucket.logs": true}},
+	}
+
+	// When
+	duplicated := m.identifyDuplicatedResources(resourceIDToClaims, workspaceToConfiguration)
+
+	// Then
+	assert.Equal(t, []DuplicatedResource{
+		{
+			InstanceID:   "my-logs",
+			ResourceType: "aws_s3_bucket",
+			Claims: []ResourceClaim{
+				{
+					StateFileName: "workspace-a",
+					ModuleName:    "module.storage",
+					ResourceName:  "buckets",
+					Address:       `module.storage.aws_s3_bucket.buckets["logs"]`,
+					ShouldResolve: true,
+				},
+				{
+					StateFileName: "workspace-b",
+					ModuleName:    "",
+					ResourceName:  "logs",
+					Address:       "aws_s3_bucket.logs",
+					Configured:    true,
+					ShouldResolve: false,
+				},
+			},
+		},
+	}, duplicated)
+}
+
+func TestIdentifyDuplicatedResources_MultipleConfiguredClaims(t *testing.T) {
+	// Given
+	m := NewManagedResourcesDriftDetector(ManagedResourceDriftDetectorConfig{})
+	resourceIDToClaims := ResourceIDToClaims{}
+
+	for _, workspace := range []string{"workspace-a", "workspace-b", "workspace-c"} {
+		m.addResourceClaims(resourceIDToClaims, workspace, TerraformStateFile{
+			Resources: []*Resource{
+				{
+					Mode: "managed", Type: "aws_s3_bucket", Name: "logs",
+					Instances: []ResourceInstance{{Attributes: map[string]interface{}{"id": "my-logs"}}},
+				},
+			},
+		})
+	}
+
+	configured := WorkspaceConfiguration{Modules: map[string]bool{}, Resources: map[string]bool{"aws_s3_bucket.logs": true}}
+	workspaceToConfiguration := map[string]WorkspaceConfiguration{
+		"workspace-a": configured,
+		"workspace-b": configured,
+		"workspace-c": {Modules: map[string]bool{}, Resources: map[string]bool{}},
+	}
+
+	// When
+	duplicated := m.identifyDuplicatedResources(resourceIDToClaims, workspaceToConfiguration)
+
+	// Then
+	assert.Equal(t, []DuplicatedResource{
+		{
+			InstanceID:   "my-logs",
+			ResourceType: "aws_s3_bucket",
+			Claims: []ResourceClaim{
+				{StateFileName: "workspace-a", ResourceName: "logs", Address: "aws_s3_bucket.logs", Configured: true},
+				{StateFileName: "workspace-b", ResourceName: "logs", Address: "aws_s3_bucket.logs", Configured: true},
+				{StateFileName: "workspace-c", ResourceName: "logs", Address: "aws_s3_bucket.logs", ShouldResolve: true},
+			},
+		},
+	}, duplicated)
+}
+
+func TestAddResourceClaims_Identifiers(t *testing.T) {
+	// Given
+	m := NewManagedResourcesDriftDetector(ManagedResourceDriftDetectorConfig{})
+	resourceIDToClaims := ResourceIDToClaims{}
+
+	// When
+	m.addResourceClaims(resourceIDToClaims, "workspace-a", TerraformStateFile{
+		Resources: []*Resource{
+			{
+				Mode: "managed", Type: "aws_iam_role", Name: "deploy",
+				Instances: []ResourceInstance{
+					{Attributes: map[string]interface{}{"id": "deploy", "arn": "arn:aws:iam::111111111111:role/deploy"}},
+				},
+			},
+			{
+				Mode: "managed", Type: "aws_s3_bucket", Name: "pending",
+				Instances: []ResourceInstance{
+					{IndexKey: float64(0), Attributes: map[string]interface{}{"id": ""}},
+					{IndexKey: float64(1), Attributes: map[string]interface{}{}},
+				},
+			},
+		},
+	})
+	m.addResourceClaims(resourceIDToClaims, "workspace-b", TerraformStateFile{
+		Resources: []*Resource{
+			{
+				Mode: "managed", Type: "aws_iam_role", Name: "deploy",
+				Instances: []ResourceInstance{
+					{Attributes: map[string]interface{}{"id": "deploy", "arn": "arn:aws:iam::222222222222:role/deploy"}},
+				},
+			},
+			{
+				Mode: "managed", Type: "aws_s3_bucket", Name: "pending",
+				Instances: []ResourceInstance{{Attributes: map[string]interface{}{"id": nil}}},
+			},
+		},
+	})
+
+	// Then
+	assert.Equal(t, ResourceIDToClaims{
+		"aws_iam_role.arn:aws:iam::111111111111:role/deploy": {
+			{StateFileName: "workspace-a", ResourceName: "deploy", Address: "aws_iam_role.deploy"},
+		},
+		"aws_iam_role.arn:aws:iam::222222222222:role/deploy": {
+			{StateFileName: "workspace-b", ResourceName: "deploy", Address: "aws_iam_role.deploy"},
+		},
+	}, resourceIDToClaims)
+}
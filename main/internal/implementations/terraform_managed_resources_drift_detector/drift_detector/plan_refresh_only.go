@@ -0,0 +1,282 @@
+package driftdetector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// refreshOnlyPlanFileName is the name of the saved plan file written within each workspace directory.
+	refreshOnlyPlanFileName = "cloud-concierge-refresh-only.tfplan"
+
+	// refreshOnlySandboxPath is the directory into which the repository is copied for refresh-only plans, so that
+	// initializing workspaces and saving plans leaves no files within the cloned repository.
+	refreshOnlySandboxPath = "outputs/refresh-only"
+)
+
+// RefreshOnlyPlan represents the subset of `terraform show -json` output for a saved refresh-only plan
+// that is needed to identify drifted resources.
+type RefreshOnlyPlan struct {
+	ResourceDrift []ResourceDrift `json:"resource_drift"`
+}
+
+// ResourceDrift represents a single entry within the resource_drift section of a Terraform plan.
+type ResourceDrift struct {
+	Address       string              `json:"address"`
+	ModuleAddress string              `json:"module_address"`
+	Mode          string              `json:"mode"`
+	Type          string              `json:"type"`
+	Name          string              `json:"name"`
+	Change        ResourceDriftChange `json:"change"`
+}
+
+// ResourceDriftChange contains the actions and values of a drifted resource, with Before being the
+// value recorded in state and After being the value read from the cloud during the refresh.
+type ResourceDriftChange struct {
+	Actions []string               `json:"actions"`
+	Before  map[string]interface{} `json:"before"`
+	After   map[string]interface{} `json:"after"`
+}
+
+// PlanRefreshOnlyDriftDetector is a type that identifies resources managed by Terraform that have drifted
+// by trusting Terraform's own refresh via `terraform plan -refresh-only`, rather than comparing
+// terraformer output against state.
+type PlanRefreshOnlyDriftDetector struct {
+	*ManagedResourcesDriftDetector
+}
+
+// NewPlanRefreshOnlyDriftDetector generates an instance of PlanRefreshOnlyDriftDetector
+func NewPlanRefreshOnlyDriftDetector(config ManagedResourceDriftDetectorConfig) *PlanRefreshOnlyDriftDetector {
+	return &PlanRefreshOnlyDriftDetector{
+		ManagedResourcesDriftDetector: NewManagedResourcesDriftDetector(config),
+	}
+}
+
+// Execute runs a refresh-only plan within each workspace directory of the cloned repository and
+// writes the identified differences and deleted resources in the same format as ManagedResourcesDriftDetector.
+func (p *PlanRefreshOnlyDriftDetector) Execute(_ context.Context, workspaceToDirectory map[string]string) (bool, error) {
+	logrus.Debugf("[plan_refresh_only_drift_detector] workspaceToDirectory: %v", workspaceToDirectory)
+
+	differences := make([]AttributeDifference, 0)
+	deleted := make([]DeletedResource, 0)
+	driftedResourceAttributes := make([]DriftedResourceAttributes, 0)
+
+	err := os.RemoveAll(refreshOnlySandboxPath)
+	if err != nil {
+		return false, fmt.Errorf("[os.RemoveAll %v]%w", refreshOnlySandboxPath, err)
+	}
+	defer os.RemoveAll(refreshOnlySandboxPath)
+
+	sandboxRepository := filepath.Join(refreshOnlySandboxPath, "repo")
+	err = copyDirectory("repo", sandboxRepository)
+	if err != nil {
+		return false, fmt.Errorf("[copyDirectory]%w", err)
+	}
+
+	for workspace, directory := range workspaceToDirectory {
+		plan, err := p.runRefreshOnlyPlan(sandboxRepository + directory)
+		if err != nil {
+			return false, fmt.Errorf("[p.runRefreshOnlyPlan][workspace %v]%w", workspace, err)
+		}
+
+		workspaceDifferences, workspaceDeleted, workspaceDriftedAttributes, err := p.convertResourceDrift(workspace, plan)
+		if err != nil {
+			return false, fmt.Errorf("[p.convertResourceDrift][workspace %v]%w", workspace, err)
+		}
+
+		differences = append(differences, workspaceDifferences...)
+		deleted = append(deleted, workspaceDeleted...)
+		driftedResourceAttributes = append(driftedResourceAttributes, workspaceDriftedAttributes...)
+	}
+
+	err = p.writeDeletedResources(deleted)
+	if err != nil {
+		return false, fmt.Errorf("[p.writeDeletedResources]%w", err)
+	}
+
+	err = p.writeDifferences(differences)
+	if err != nil {
+		return false, fmt.Errorf("[p.writeDifferences]%w", err)
+	}
+
+	err = p.writeDriftedResourceAttributes(driftedResourceAttributes)
+	if err != nil {
+		return false, fmt.Errorf("[p.writeDriftedResourceAttributes]%w", err)
+	}
+
+	codeDriftFound, err := p.identifyAndWriteCodeDrift(workspaceToDirectory)
+	if err != nil {
+		return false, fmt.Errorf("[p.identifyAndWriteCodeDrift]%w", err)
+	}
+
+	_, resourceIDToClaims, err := p.loadAllRemoteStateFiles(workspaceToDirectory)
+	if err != nil {
+		return false, fmt.Errorf("[p.loadAllRemoteStateFiles]%w", err)
+	}
+
+	duplicatesFound, err := p.identifyAndWriteDuplicatedResources(resourceIDToClaims, workspaceToDirectory)
+	if err != nil {
+		return false, fmt.Errorf("[p.identifyAndWriteDuplicatedResources]%w", err)
+	}
+
+	return len(deleted) > 0 || len(differences) > 0 || codeDriftFound || duplicatesFound, nil
+}
+
+// runRefreshOnlyPlan initializes Terraform, or OpenTofu, within the sandboxed workspace directory, saves a
+// refresh-only plan, and returns the parsed JSON representation of that plan.
+func (p *PlanRefreshOnlyDriftDetector) runRefreshOnlyPlan(directory string) (RefreshOnlyPlan, error) {
+	binary := p.config.Runtime.Binary()
+
+	_, err := executeCommandInDirectory(directory, binary, "init", "-input=false", "-no-color")
+	if err != nil {
+		return RefreshOnlyPlan{}, fmt.Errorf("[terraform init]%w", err)
+	}
+
+	planOutput, err := executeCommandInDirectory(
+		directory, binary, "plan", "-refresh-only", "-json", "-input=false", "-lock=false",
+		fmt.Sprintf("-out=%v", refreshOnlyPlanFileName),
+	)
+	if err != nil {
+		return RefreshOnlyPlan{}, fmt.Errorf("[terraform plan -refresh-only]%w", err)
+	}
+	logrus.Debugf("[plan_refresh_only_drift_detector] plan output: %v", planOutput)
+
+	showOutput, err := executeCommandInDirectory(directory, binary, "show", "-json", refreshOnlyPlanFileName)
+	if err != nil {
+		return RefreshOnlyPlan{}, fmt.Errorf("[terraform show -json]%w", err)
+	}
+
+	return parseRefreshOnlyPlan([]byte(showOutput))
+}
+
+// parseRefreshOnlyPlan parses the output of `terraform show -json` for a saved plan.
+func parseRefreshOnlyPlan(planContent []byte) (RefreshOnlyPlan, error) {
+	var plan RefreshOnlyPlan
+	if err := json.Unmarshal(planContent, &plan); err != nil {
+		return RefreshOnlyPlan{}, fmt.Errorf("failed to parse plan: %v", err)
+	}
+	return plan, nil
+}
+
+// convertResourceDrift converts the resource_drift entries of a refresh-only plan into AttributeDifference,
+// DeletedResource and DriftedResourceAttributes values.
+func (p *PlanRefreshOnlyDriftDetector) convertResourceDrift(
+	workspace string, plan RefreshOnlyPlan,
+) ([]AttributeDifference, []DeletedResource, []DriftedResourceAttributes, error) {
+	differences := make([]AttributeDifference, 0)
+	deleted := make([]DeletedResource, 0)
+	driftedResourceAttributes := make([]DriftedResourceAttributes, 0)
+
+	for _, drift := range plan.ResourceDrift {
+		if drift.Mode != "managed" || !p.isValidDeletedResource(drift.Type) {
+			continue
+		}
+
+		terraformAttributes, err := convertNestedMapToFlatAttributes(drift.Change.Before)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("[convertNestedMapToFlatAttributes]%v", err)
+		}
+
+		if isDeleteAction(drift.Change.Actions) {
+			deleted = append(deleted, DeletedResource{
+				InstanceID:    terraformAttributes["id"],
+				StateFileName: StateFileName(workspace),
+				ModuleName:    drift.ModuleAddress,
+				ResourceType:  drift.Type,
+				ResourceName:  drift.Name,
+			})
+			continue
+		}
+
+		cloudAttributes, err := convertNestedMapToFlatAttributes(drift.Change.After)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("[convertNestedMapToFlatAttributes]%v", err)
+		}
+
+		attributeComplement := &AttributeDetail{
+			StateFileName: StateFileName(workspace),
+			ModuleName:    drift.ModuleAddress,
+			ResourceType:  drift.Type,
+			ResourceName:  drift.Name,
+		}
+
+		driftedAttributes, resourceChanged, err := compareFlatAttributesAndGetDrifted(terraformAttributes, cloudAttributes, attributeComplement)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("[compareFlatAttributesAndGetDrifted]%v", err)
+		}
+
+		if resourceChanged {
+			differences = append(differences, driftedAttributes...)
+			driftedResourceAttributes = append(
+				driftedResourceAttributes,
+				newDriftedResourceAttributes(driftedAttributes[0], terraformAttributes, cloudAttributes),
+			)
+		}
+	}
+
+	return differences, deleted, driftedResourceAttributes, nil
+}
+
+// isDeleteAction returns true if the refresh-only plan actions indicate the resource no longer exists.
+func isDeleteAction(actions []string) bool {
+	return len(actions) == 1 && actions[0] == "delete"
+}
+
+// copyDirectory recursively copies the files within source to destination, skipping git metadata and Terraform
+// working directories.
+func copyDirectory(source string, destination string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(destination, relativePath)
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" || entry.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(destinationPath, 0o700)
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		fileBytes, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(destinationPath, fileBytes, 0o600)
+	})
+}
+
+// executeCommandInDirectory wraps os.exec.Command with capturing of std output and errors, running the
+// command within the specified directory.
+func executeCommandInDirectory(directory string, command string, args ...string) (string, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Dir = directory
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("%v\n\n%v", err, stderr.String()+out.String())
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,90 @@
+package driftdetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertResourceDrift(t *testing.T) {
+	// Given
+	detector := NewPlanRefreshOnlyDriftDetector(ManagedResourceDriftDetectorConfig{})
+	plan, err := parseRefreshOnlyPlan([]byte(`{
+		"resource_drift": [
+			{
+				"address": "google_storage_bucket.bucket",
+				"mode": "managed",
+				"type": "google_storage_bucket",
+				"name": "bucket",
+				"change": {
+					"actions": ["update"],
+					"before": {"id": "my-bucket", "location": "US", "force_destroy": false, "ports": [80, 443]},
+					"after": {"id": "my-bucket", "location": "US", "force_destroy": true, "ports": [80, 443]}
+				}
+			},
+			{
+				"address": "module.network.google_compute_network.vpc",
+				"module_address": "module.network",
+				"mode": "managed",
+				"type": "google_compute_network",
+				"name": "vpc",
+				"change": {
+					"actions": ["delete"],
+					"before": {"id": "projects/my-project/global/networks/vpc"},
+					"after": null
+				}
+			},
+			{
+				"address": "data.google_project.project",
+				"mode": "data",
+				"type": "google_project",
+				"name": "project",
+				"change": {"actions": ["update"], "before": {"id": "a"}, "after": {"id": "b"}}
+			}
+		]
+	}`))
+	assert.Nil(t, err)
+
+	// When
+	differences, deleted, driftedResourceAttributes, err := detector.convertResourceDrift("workspace", plan)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, []AttributeDifference{
+		{
+			AttributeName:  "force_destroy",
+			TerraformValue: "false",
+			CloudValue:     "true",
+			InstanceID:     "projects/_/buckets/my-bucket",
+			InstanceRegion: "US",
+			AttributeDetail: AttributeDetail{
+				StateFileName: "workspace",
+				ModuleName:    "",
+				ResourceType:  "google_storage_bucket",
+				ResourceName:  "bucket",
+			},
+		},
+	}, differences)
+	assert.Equal(t, []DeletedResource{
+		{
+			InstanceID:    "projects/my-project/global/networks/vpc",
+			StateFileName: "workspace",
+			ModuleName:    "module.network",
+			ResourceType:  "google_compute_network",
+			ResourceName:  "vpc",
+		},
+	}, deleted)
+	assert.Equal(t, []DriftedResourceAttributes{
+		{
+			InstanceID:          "projects/_/buckets/my-bucket",
+			TerraformAttributes: map[string]string{"id": "my-bucket", "location": "US", "force_destroy": "false", "ports.0": "80", "ports.1": "443"},
+			CloudAttributes:     map[string]string{"id": "my-bucket", "location": "US", "force_destroy": "true", "ports.0": "80", "ports.1": "443"},
+			AttributeDetail: AttributeDetail{
+				StateFileName: "workspace",
+				ModuleName:    "",
+				ResourceType:  "google_storage_bucket",
+				ResourceName:  "bucket",
+			},
+		},
+	}, driftedResourceAttributes)
+}
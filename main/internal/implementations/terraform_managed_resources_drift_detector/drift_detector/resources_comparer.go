@@ -19,6 +19,7 @@ type AttributeDifference struct {
 	CloudValue            string
 	InstanceID            string
 	InstanceRegion        string
+	Severity              Severity
 	AttributeDetail
 }
 
@@ -36,14 +37,15 @@ type AttributeDetail struct {
 func (m *ManagedResourcesDriftDetector) identifyResourceDifferences(
 	terraformerResources TerraformerResourceIDToData,
 	terraformResources TerraformStateResourceIDToData,
-) ([]AttributeDifference, error) {
+) ([]AttributeDifference, []DriftedResourceAttributes, error) {
 	attributeDifferences := make([]AttributeDifference, 0)
+	driftedResourceAttributes := make([]DriftedResourceAttributes, 0)
 
 	for id, data := range terraformResources {
 		if terraformerResource, ok := terraformerResources[id]; ok {
 			terraformInstanceConverted, err := convertNestedMapToFlatAttributes(data.Attributes)
 			if err != nil {
-				return nil, fmt.Errorf("[convertNestedMapToFlatAttributes]%v", err)
+				return nil, nil, fmt.Errorf("[convertNestedMapToFlatAttributes]%v", err)
 			}
 
 			attributeComplement := &AttributeDetail{
@@ -55,16 +57,20 @@ func (m *ManagedResourcesDriftDetector) identifyResourceDifferences(
 
 			driftedResources, resourcesChanged, err := compareFlatAttributesAndGetDrifted(terraformInstanceConverted, terraformerResource.AttributesFlat, attributeComplement)
 			if err != nil {
-				return nil, fmt.Errorf("[compareFlatAttributesAndGetDrifted]%v", err)
+				return nil, nil, fmt.Errorf("[compareFlatAttributesAndGetDrifted]%v", err)
 			}
 
 			if resourcesChanged {
 				attributeDifferences = append(attributeDifferences, driftedResources...)
+				driftedResourceAttributes = append(
+					driftedResourceAttributes,
+					newDriftedResourceAttributes(driftedResources[0], terraformInstanceConverted, terraformerResource.AttributesFlat),
+				)
 			}
 		}
 	}
 
-	return attributeDifferences, nil
+	return attributeDifferences, driftedResourceAttributes, nil
 }
 
 // compareFlatAttributesAndGetDrifted compares the attributes of remoteResourceAttributes and terraformerAttributes,
@@ -259,7 +265,14 @@ func recursiveToFlatAttributes(
 		}
 	} else {
 		for i, value := range currentSlice {
+			if value == nil {
+				continue
+			}
 			switch t := value.(type) {
+			case float64:
+				output[currentBase+strconv.Itoa(i)] = strconv.FormatFloat(value.(float64), 'f', -1, 64)
+			case bool:
+				output[currentBase+strconv.Itoa(i)] = strconv.FormatBool(value.(bool))
 			case string:
 				output[currentBase+strconv.Itoa(i)] = value.(string)
 			case []interface{}:
@@ -35,7 +35,7 @@ func TestGetExistentResourcesHaveChanged(t *testing.T) {
 	}
 
 	// When
-	differences, err := detector.identifyResourceDifferences(terraformerResourcesIDToData, stateFileResourcesIDToData)
+	differences, _, err := detector.identifyResourceDifferences(terraformerResourcesIDToData, stateFileResourcesIDToData)
 
 	// Then
 	require.Len(t, differences, 0)
@@ -45,7 +45,7 @@ func TestGetExistentResourcesHaveChanged(t *testing.T) {
 	stateFileResourcesIDToData["google_example.id_1"].Attributes["id"] = "modified-dragondrop-modules"
 
 	// When
-	differences, err = detector.identifyResourceDifferences(terraformerResourcesIDToData, stateFileResourcesIDToData)
+	differences, _, err = detector.identifyResourceDifferences(terraformerResourcesIDToData, stateFileResourcesIDToData)
 
 	// Then
 	require.NoError(t, err)
@@ -91,7 +91,7 @@ func TestGetExistentResourcesHaveChanged_MoreThanOneAttribute(t *testing.T) {
 	}
 
 	// When
-	differences, err := detector.identifyResourceDifferences(terraformerResourcesIDToData, stateFileResourcesIDToData)
+	differences, _, err := detector.identifyResourceDifferences(terraformerResourcesIDToData, stateFileResourcesIDToData)
 
 	// Then
 	require.NoError(t, err)
@@ -231,6 +231,20 @@ func TestConvertNestedMapToFlatAttributes(t *testing.T) {
 	assert.Equal(t, expectedOutput, output)
 }
 
+func TestConvertNestedMapToFlatAttributes_FractionalListValues(t *testing.T) {
+	// Given
+	input := map[string]interface{}{
+		"weights": []interface{}{0.5, float64(2), 1.25},
+	}
+
+	// When
+	output, err := convertNestedMapToFlatAttributes(input)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"weights.0": "0.5", "weights.1": "2", "weights.2": "1.25"}, output)
+}
+
 func TestConvertNestedMapToFlatAttributes_Two(t *testing.T) {
 	m := ManagedResourcesDriftDetector{}
 	inputTerraformStateFile, err := m.parseRemoteStateFile([]byte(`{
@@ -0,0 +1,177 @@
+package driftdetector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Severity is the risk rating of a drifted attribute.
+type Severity string
+
+const (
+	// SeverityCritical is drift that directly weakens security, such as network exposure or IAM changes.
+	SeverityCritical Severity = "critical"
+
+	// SeverityHigh is drift that weakens resilience or protection of data.
+	SeverityHigh Severity = "high"
+
+	// SeverityMedium is the default severity for drift without a more specific rule.
+	SeverityMedium Severity = "medium"
+
+	// SeverityLow is drift that is largely cosmetic, such as tag or description changes.
+	SeverityLow Severity = "low"
+)
+
+// severityRanks maps each Severity to its rank, with larger values being more severe.
+var severityRanks = map[Severity]int{
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// Decode is a custom decoder of a Severity for use with the envconfig library.
+func (s *Severity) Decode(value string) error {
+	severity := Severity(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := severityRanks[severity]; !ok && severity != "" {
+		return fmt.Errorf("severity must be one of 'critical', 'high', 'medium' or 'low', got '%v'", value)
+	}
+
+	*s = severity
+	return nil
+}
+
+// AtLeast returns true if the Severity is as severe or more severe than the minimum Severity.
+func (s Severity) AtLeast(minimum Severity) bool {
+	return severityRanks[s] >= severityRanks[minimum]
+}
+
+// SeverityRule assigns a Severity to drifted attributes whose resource type and attribute name match the rule.
+type SeverityRule struct {
+	// ResourceType is a regular expression matched against the full resource type. Empty matches any resource type.
+	ResourceType string `json:"resourceType"`
+
+	// Attribute is a regular expression matched against the full flattened attribute name, for
+	// example "ingress.0.cidr_blocks.0". Empty matches any attribute.
+	Attribute string `json:"attribute"`
+
+	// Severity is the Severity assigned to matching attributes.
+	Severity Severity `json:"severity"`
+}
+
+// SeverityRules is a list of SeverityRule values, where the first matching rule determines the Severity.
+type SeverityRules []SeverityRule
+
+// Decode is a custom decoder of SeverityRules for use with the envconfig library.
+func (r *SeverityRules) Decode(value string) error {
+	logrus.Debugf("SeverityRules.Decode() called with value: %v", value)
+	if value == "" {
+		return nil
+	}
+
+	var rules SeverityRules
+	err := json.Unmarshal([]byte(value), &rules)
+	if err != nil {
+		return fmt.Errorf("Error parsing specified json string: %v", err)
+	}
+
+	for i, rule := range rules {
+		if _, err := regexp.Compile(rule.ResourceType); err != nil {
+			return fmt.Errorf("invalid resourceType pattern %v: %v", rule.ResourceType, err)
+		}
+		if _, err := regexp.Compile(rule.Attribute); err != nil {
+			return fmt.Errorf("invalid attribute pattern %v: %v", rule.Attribute, err)
+		}
+		if err := rules[i].Severity.Decode(string(rule.Severity)); err != nil || rules[i].Severity == "" {
+			return fmt.Errorf("invalid severity for rule %v: %v", i, rule.Severity)
+		}
+	}
+
+	*r = rules
+	return nil
+}
+
+// builtInSeverityRules are the default rules for classifying drift, evaluated after any user provided rules.
+var builtInSeverityRules = SeverityRules{
+	{Attribute: `(tags|tags_all|labels)(\..*)?`, Severity: SeverityLow},
+	{Attribute: `description`, Severity: SeverityLow},
+
+	{ResourceType: `.*security_group.*`, Attribute: `(ingress|egress)(\..*)?`, Severity: SeverityCritical},
+	{ResourceType: `.*security_group.*`, Attribute: `.*(cidr|prefix_list|from_port|to_port|protocol).*`, Severity: SeverityCritical},
+	{ResourceType: `google_compute_firewall`, Attribute: `(allow|deny|source_ranges|source_tags|direction)(\..*)?`, Severity: SeverityCritical},
+	{ResourceType: `azurerm_network_security_(group|rule)`, Attribute: `.*(security_rule|address_prefix|port_range|access).*`, Severity: SeverityCritical},
+	{ResourceType: `.*iam.*`, Severity: SeverityCritical},
+	{Attribute: `(.*\.)?policy(_document)?(\..*)?`, Severity: SeverityCritical},
+	{ResourceType: `.*public_access_block.*`, Severity: SeverityCritical},
+	{Attribute: `.*(encrypt|kms_key|sse_algorithm|server_side_encryption).*`, Severity: SeverityCritical},
+	{Attribute: `.*public.*`, Severity: SeverityCritical},
+
+	{Attribute: `.*(acl|password|secret|ssl|tls|deletion_protection|logging|versioning|backup).*`, Severity: SeverityHigh},
+}
+
+// matches returns true if the rule applies to the resource type and attribute name.
+func (r SeverityRule) matches(resourceType string, attributeName string) bool {
+	return fullMatch(r.ResourceType, resourceType) && fullMatch(r.Attribute, attributeName)
+}
+
+// fullMatch returns true if the pattern matches the entire value, with an empty pattern matching any value.
+func fullMatch(pattern string, value string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	matched, err := regexp.MatchString(fmt.Sprintf("^(?:%v)$", pattern), value)
+	return err == nil && matched
+}
+
+// classifySeverity returns the Severity of a drifted attribute, applying override rules before built-in rules.
+func classifySeverity(overrides SeverityRules, resourceType string, attributeName string) Severity {
+	for _, rules := range []SeverityRules{overrides, builtInSeverityRules} {
+		for _, rule := range rules {
+			if rule.matches(resourceType, attributeName) {
+				return rule.Severity
+			}
+		}
+	}
+
+	return SeverityMedium
+}
+
+// assignSeverities sets the Severity of each AttributeDifference.
+func (m *ManagedResourcesDriftDetector) assignSeverities(differences []AttributeDifference) {
+	for i, difference := range differences {
+		differences[i].Severity = classifySeverity(m.config.SeverityOverrides, difference.ResourceType, difference.AttributeName)
+	}
+}
+
+// CountDriftAtOrAboveSeverity counts the drifted attributes within outputs/drift-resources-differences.json which are
+// at least as severe as the minimum Severity.
+func CountDriftAtOrAboveSeverity(minimum Severity) (int, error) {
+	fileContent, err := os.ReadFile("outputs/drift-resources-differences.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("[os.ReadFile]%v", err)
+	}
+
+	var differences []AttributeDifference
+	err = json.Unmarshal(fileContent, &differences)
+	if err != nil {
+		return 0, fmt.Errorf("[json.Unmarshal]%v", err)
+	}
+
+	count := 0
+	for _, difference := range differences {
+		if difference.Severity.AtLeast(minimum) {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,64 @@
+package driftdetector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifySeverity_BuiltInRules(t *testing.T) {
+	cases := []struct {
+		resourceType string
+		attribute    string
+		expected     Severity
+	}{
+		{"aws_security_group", "ingress.0.cidr_blocks.0", SeverityCritical},
+		{"aws_security_group", "tags.Name", SeverityLow},
+		{"aws_iam_role", "assume_role_policy", SeverityCritical},
+		{"aws_s3_bucket_policy", "policy", SeverityCritical},
+		{"aws_s3_bucket_public_access_block", "block_public_acls", SeverityCritical},
+		{"aws_db_instance", "storage_encrypted", SeverityCritical},
+		{"aws_db_instance", "publicly_accessible", SeverityCritical},
+		{"aws_db_instance", "deletion_protection", SeverityHigh},
+		{"google_storage_bucket", "labels.env", SeverityLow},
+		{"google_compute_firewall", "source_ranges.0", SeverityCritical},
+		{"aws_lb", "idle_timeout", SeverityMedium},
+	}
+
+	for _, c := range cases {
+		// When
+		output := classifySeverity(nil, c.resourceType, c.attribute)
+
+		// Then
+		assert.Equal(t, c.expected, output, "%v.%v", c.resourceType, c.attribute)
+	}
+}
+
+func TestClassifySeverity_Overrides(t *testing.T) {
+	// Given
+	var overrides SeverityRules
+	err := overrides.Decode(`[{"resourceType": "aws_lb", "attribute": "idle_timeout", "severity": "HIGH"}, {"attribute": "tags\\..*", "severity": "medium"}]`)
+	require.NoError(t, err)
+
+	// When
+	idleTimeout := classifySeverity(overrides, "aws_lb", "idle_timeout")
+	tags := classifySeverity(overrides, "aws_lb", "tags.Name")
+
+	// Then
+	assert.Equal(t, SeverityHigh, idleTimeout)
+	assert.Equal(t, SeverityMedium, tags)
+}
+
+func TestSeverityRulesDecode_Invalid(t *testing.T) {
+	var rules SeverityRules
+
+	assert.NotNil(t, rules.Decode(`[{"attribute": "tags", "severity": "urgent"}]`))
+	assert.NotNil(t, rules.Decode(`[{"attribute": "(", "severity": "low"}]`))
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	assert.True(t, SeverityCritical.AtLeast(SeverityHigh))
+	assert.True(t, SeverityHigh.AtLeast(SeverityHigh))
+	assert.False(t, SeverityLow.AtLeast(SeverityMedium))
+}
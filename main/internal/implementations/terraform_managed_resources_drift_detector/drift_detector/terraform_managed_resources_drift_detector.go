@@ -11,6 +11,14 @@ import (
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 )
 
+const (
+	// TerraformerEngine detects drift by comparing terraformer output against remote state files.
+	TerraformerEngine = "terraformer"
+
+	// PlanRefreshOnlyEngine detects drift by running `terraform plan -refresh-only` within each workspace.
+	PlanRefreshOnlyEngine = "plan-refresh-only"
+)
+
 // ManagedResourceDriftDetectorConfig is a type that contains configuration
 type ManagedResourceDriftDetectorConfig struct {
 	// ResourcesWhiteList represents the list of resource names that will be exclusively considered for inclusion in the import statement.
@@ -18,6 +26,15 @@ type ManagedResourceDriftDetectorConfig struct {
 
 	// ResourcesBlackList represents the list of resource names that will be excluded from consideration for inclusion in the import statement.
 	ResourcesBlackList terraformValueObjects.ResourceNameList
+
+	// DriftDetectionEngine is the engine used to detect drift, either TerraformerEngine or PlanRefreshOnlyEngine.
+	DriftDetectionEngine string
+
+	// SeverityOverrides are rules for classifying drift severity that take precedence over the built-in rules.
+	SeverityOverrides SeverityRules
+
+	// Runtime is the command line tool, Terraform or OpenTofu, used by the PlanRefreshOnlyEngine.
+	Runtime terraformValueObjects.Runtime
 }
 
 // ManagedResourcesDriftDetector is a type that identifies resources
@@ -40,7 +57,7 @@ func NewManagedResourcesDriftDetector(config ManagedResourceDriftDetectorConfig)
 func (m *ManagedResourcesDriftDetector) Execute(_ context.Context, workspaceToDirectory map[string]string) (bool, error) {
 	logrus.Debugf("[drift_detector] workspaceToDirectory: %v", workspaceToDirectory)
 
-	remoteStateResources, err := m.loadAllRemoteStateFiles(workspaceToDirectory)
+	remoteStateResources, resourceIDToClaims, err := m.loadAllRemoteStateFiles(workspaceToDirectory)
 	if err != nil {
 		return false, fmt.Errorf("[m.loadAllRemoteStateFiles]%w", err)
 	}
@@ -60,12 +77,22 @@ func (m *ManagedResourcesDriftDetector) Execute(_ context.Context, workspaceToDi
 		return false, fmt.Errorf("[m.identifyAndWriteResourcesDifferences]%w", err)
 	}
 
-	return wereDeleted || differencesFound, nil
+	codeDriftFound, err := m.identifyAndWriteCodeDrift(workspaceToDirectory)
+	if err != nil {
+		return false, fmt.Errorf("[m.identifyAndWriteCodeDrift]%w", err)
+	}
+
+	duplicatesFound, err := m.identifyAndWriteDuplicatedResources(resourceIDToClaims, workspaceToDirectory)
+	if err != nil {
+		return false, fmt.Errorf("[m.identifyAndWriteDuplicatedResources]%w", err)
+	}
+
+	return wereDeleted || differencesFound || codeDriftFound || duplicatesFound, nil
 }
 
 // identifyAndWriteResourcesDifferences found the resources differences and writes in the mapping file
 func (m *ManagedResourcesDriftDetector) identifyAndWriteResourcesDifferences(terraformerResources TerraformerResourceIDToData, terraformResources TerraformStateResourceIDToData) (bool, error) {
-	differences, err := m.identifyResourceDifferences(terraformerResources, terraformResources)
+	differences, driftedResourceAttributes, err := m.identifyResourceDifferences(terraformerResources, terraformResources)
 	if err != nil {
 		return false, fmt.Errorf("[m.identifyResourceDifferences]%w", err)
 	}
@@ -75,6 +102,11 @@ func (m *ManagedResourcesDriftDetector) identifyAndWriteResourcesDifferences(ter
 		return false, fmt.Errorf("[m.writeDifferences]%w", err)
 	}
 
+	err = m.writeDriftedResourceAttributes(driftedResourceAttributes)
+	if err != nil {
+		return false, fmt.Errorf("[m.writeDriftedResourceAttributes]%w", err)
+	}
+
 	return len(differences) > 0, nil
 }
 
@@ -105,6 +137,8 @@ func (m *ManagedResourcesDriftDetector) writeDeletedResources(deleted []DeletedR
 
 // writeDifferences writes within a json file the differences between all the drifted resources to render within the PR
 func (m *ManagedResourcesDriftDetector) writeDifferences(differences []AttributeDifference) error {
+	m.assignSeverities(differences)
+
 	differencesJSON, err := json.MarshalIndent(differences, "", "  ")
 	if err != nil {
 		return fmt.Errorf("[json.MarshalIndent]%w", err)
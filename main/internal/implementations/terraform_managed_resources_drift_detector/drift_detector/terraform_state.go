@@ -36,30 +36,35 @@ type Resource struct {
 
 // ResourceInstance represents a Terraform resource instance within a state file.
 type ResourceInstance struct {
+	IndexKey      interface{}            `json:"index_key"`
 	SchemaVersion int                    `json:"schema_version"`
 	Attributes    map[string]interface{} `json:"attributes"`
 }
 
-// loadAllRemoteStateFiles loads from memory the remote state files and aggregates data.
-func (m *ManagedResourcesDriftDetector) loadAllRemoteStateFiles(workspaceToDirectory map[string]string) (TerraformStateResourceIDToData, error) {
+// loadAllRemoteStateFiles loads from memory the remote state files and aggregates data. Since a resource id claimed by
+// more than one state file is overwritten within TerraformStateResourceIDToData, every claim is also returned.
+func (m *ManagedResourcesDriftDetector) loadAllRemoteStateFiles(workspaceToDirectory map[string]string) (TerraformStateResourceIDToData, ResourceIDToClaims, error) {
 	fileNames := make([]string, 0)
 	for workspaceName := range workspaceToDirectory {
 		fileNames = append(fileNames, workspaceName)
 	}
 
 	resources := TerraformStateResourceIDToData{}
+	resourceIDToClaims := ResourceIDToClaims{}
 
 	for _, remoteStateFile := range fileNames {
 		fileContent, err := os.ReadFile(fmt.Sprintf("state_files/%v.json", remoteStateFile))
 		if err != nil {
-			return nil, fmt.Errorf("failed to read state file %s: %v", remoteStateFile, err)
+			return nil, nil, fmt.Errorf("failed to read state file %s: %v", remoteStateFile, err)
 		}
 
 		file, err := m.parseRemoteStateFile(fileContent)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
+		m.addResourceClaims(resourceIDToClaims, remoteStateFile, file)
+
 		resourcesFromStateFile := m.terraformStateExtractUniqueResourceIDToData(remoteStateFile, file)
 
 		for resourceID, resourceData := range resourcesFromStateFile {
@@ -67,7 +72,7 @@ func (m *ManagedResourcesDriftDetector) loadAllRemoteStateFiles(workspaceToDirec
 		}
 	}
 
-	return resources, nil
+	return resources, resourceIDToClaims, nil
 }
 
 // terraformStateExtractUniqueResourceIDToData reformats resource data to pull out the attribute "id" as the unique
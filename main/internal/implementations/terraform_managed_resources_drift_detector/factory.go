@@ -24,5 +24,8 @@ func (f *Factory) Instantiate(ctx context.Context, environment string, config dr
 // bootstrappedDriftDetector creates a complete implementation of the interfaces.TerraformManagedResourcesDriftDetector interface with
 // configuration specified via environment variables.
 func (f *Factory) bootstrappedDriftDetector(_ context.Context, config driftDetector.ManagedResourceDriftDetectorConfig) (interfaces.TerraformManagedResourcesDriftDetector, error) {
+	if config.DriftDetectionEngine == driftDetector.PlanRefreshOnlyEngine {
+		return driftDetector.NewPlanRefreshOnlyDriftDetector(config), nil
+	}
 	return driftDetector.NewManagedResourcesDriftDetector(config), nil
 }
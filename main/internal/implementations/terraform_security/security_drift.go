@@ -0,0 +1,362 @@
+package terraformsecurity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/sirupsen/logrus"
+	"github.com/zclconf/go-cty/cty"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+// securityDriftScanningPath is the directory within which the Terraform and cloud representations of drifted
+// resources are written for scanning.
+const securityDriftScanningPath = "./security_drift"
+
+// SecurityDrift is a security finding that is present within the cloud representation of a drifted resource
+// but not within its Terraform representation, meaning the drift weakened the resource's security posture.
+type SecurityDrift struct {
+	InstanceID            string   `json:"InstanceID"`
+	StateFileName         string   `json:"StateFileName"`
+	ModuleName            string   `json:"ModuleName"`
+	ResourceType          string   `json:"ResourceType"`
+	ResourceName          string   `json:"ResourceName"`
+	RecentActor           string   `json:"RecentActor"`
+	RecentActionTimestamp string   `json:"RecentActionTimestamp"`
+	RuleID                string   `json:"RuleID"`
+	RuleDescription       string   `json:"RuleDescription"`
+	Severity              string   `json:"Severity"`
+	Resolution            string   `json:"Resolution"`
+	Links                 []string `json:"Links"`
+}
+
+// executeSecurityDriftScan scans the Terraform and cloud representations of each drifted resource, and writes
+// the security findings introduced by drift to outputs/security-drift.json.
+func (s *TFSec) executeSecurityDriftScan() error {
+	driftedResources, err := loadDriftedResourceAttributes()
+	if err != nil {
+		return fmt.Errorf("[loadDriftedResourceAttributes]%v", err)
+	}
+
+	securityDrift := make([]SecurityDrift, 0)
+	if len(driftedResources) > 0 {
+		terraformResults, err := s.scanDriftedResources("terraform", driftedResources, func(resource driftDetector.DriftedResourceAttributes) map[string]string {
+			return resource.TerraformAttributes
+		})
+		if err != nil {
+			return fmt.Errorf("[s.scanDriftedResources][terraform]%v", err)
+		}
+
+		cloudResults, err := s.scanDriftedResources("cloud", driftedResources, func(resource driftDetector.DriftedResourceAttributes) map[string]string {
+			return resource.CloudAttributes
+		})
+		if err != nil {
+			return fmt.Errorf("[s.scanDriftedResources][cloud]%v", err)
+		}
+
+		differences, err := loadAttributeDifferences()
+		if err != nil {
+			return fmt.Errorf("[loadAttributeDifferences]%v", err)
+		}
+
+		securityDrift = identifySecurityDrift(driftedResources, terraformResults, cloudResults, differences)
+	}
+	logrus.Debugf("[tfsec][security_drift] %v findings introduced by drift", len(securityDrift))
+
+	securityDriftJSON, err := json.MarshalIndent(securityDrift, "", "  ")
+	if err != nil {
+		return fmt.Errorf("[json.MarshalIndent]%v", err)
+	}
+
+	return os.WriteFile("outputs/security-drift.json", securityDriftJSON, 0o400)
+}
+
+// loadDriftedResourceAttributes loads outputs/drift-resources-attributes.json, returning no resources if the
+// file does not exist.
+func loadDriftedResourceAttributes() ([]driftDetector.DriftedResourceAttributes, error) {
+	fileContent, err := os.ReadFile("outputs/drift-resources-attributes.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile]%v", err)
+	}
+
+	var driftedResources []driftDetector.DriftedResourceAttributes
+	err = json.Unmarshal(fileContent, &driftedResources)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal]%v", err)
+	}
+
+	return driftedResources, nil
+}
+
+// loadAttributeDifferences loads outputs/drift-resources-differences.json, which contains the cloud actor
+// responsible for each drifted resource.
+func loadAttributeDifferences() ([]driftDetector.AttributeDifference, error) {
+	fileContent, err := os.ReadFile("outputs/drift-resources-differences.json")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile]%v", err)
+	}
+
+	var differences []driftDetector.AttributeDifference
+	err = json.Unmarshal(fileContent, &differences)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal]%v", err)
+	}
+
+	return differences, nil
+}
+
+// scanDriftedResources writes one representation of the drifted resources as HCL and runs tfsec against it,
+// returning the results for each drifted resource keyed by its index.
+func (s *TFSec) scanDriftedResources(
+	representation string,
+	driftedResources []driftDetector.DriftedResourceAttributes,
+	attributes func(driftDetector.DriftedResourceAttributes) map[string]string,
+) (map[int][]Result, error) {
+	directory := fmt.Sprintf("%v/%v", securityDriftScanningPath, representation)
+	err := os.MkdirAll(directory, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("[os.MkdirAll]%v", err)
+	}
+
+	f := hclwrite.NewEmptyFile()
+	for i, resource := range driftedResources {
+		resourceBlock := f.Body().AppendNewBlock("resource", []string{resource.ResourceType, securityDriftResourceName(i)})
+		writeNestedAttributes(resourceBlock.Body(), unflattenAttributes(attributes(resource)))
+	}
+
+	err = os.WriteFile(fmt.Sprintf("%v/main.tf", directory), f.Bytes(), 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("[os.WriteFile]%v", err)
+	}
+
+	contentResults, err := s.runTFSecOnDirectory(directory, fmt.Sprintf("%v/tfsec.json", directory))
+	if err != nil {
+		return nil, fmt.Errorf("[s.runTFSecOnDirectory]%v", err)
+	}
+
+	parsedContentResults, err := s.parseContentResults(contentResults)
+	if err != nil {
+		return nil, fmt.Errorf("[s.parseContentResults]%v", err)
+	}
+
+	return groupResultsByDriftedResource(parsedContentResults.Results), nil
+}
+
+// securityDriftResourceName returns the name of the i-th drifted resource within the scanned HCL.
+func securityDriftResourceName(i int) string {
+	return fmt.Sprintf("drifted_%d", i)
+}
+
+// groupResultsByDriftedResource groups tfsec results by the index of the drifted resource they were found within.
+func groupResultsByDriftedResource(results []Result) map[int][]Result {
+	output := map[int][]Result{}
+	for _, result := range results {
+		resourceParts := strings.Split(result.Resource, ".")
+		if len(resourceParts) < 2 {
+			continue
+		}
+
+		index, err := strconv.Atoi(strings.TrimPrefix(resourceParts[1], "drifted_"))
+		if err != nil {
+			continue
+		}
+		output[index] = append(output[index], result)
+	}
+	return output
+}
+
+// identifySecurityDrift returns the security findings present within the cloud representation of each drifted
+// resource that are not present within its Terraform representation.
+func identifySecurityDrift(
+	driftedResources []driftDetector.DriftedResourceAttributes,
+	terraformResults map[int][]Result,
+	cloudResults map[int][]Result,
+	differences []driftDetector.AttributeDifference,
+) []SecurityDrift {
+	securityDrift := make([]SecurityDrift, 0)
+
+	for i, resource := range driftedResources {
+		terraformRules := map[string]bool{}
+		for _, result := range terraformResults[i] {
+			terraformRules[result.LongID] = true
+		}
+
+		reportedRules := map[string]bool{}
+		for _, result := range cloudResults[i] {
+			if terraformRules[result.LongID] || reportedRules[result.LongID] {
+				continue
+			}
+			reportedRules[result.LongID] = true
+
+			actor, timestamp := recentCloudAction(resource, differences)
+			securityDrift = append(securityDrift, SecurityDrift{
+				InstanceID:            resource.InstanceID,
+				StateFileName:         string(resource.StateFileName),
+				ModuleName:            resource.ModuleName,
+				ResourceType:          resource.ResourceType,
+				ResourceName:          resource.ResourceName,
+				RecentActor:           actor,
+				RecentActionTimestamp: timestamp,
+				RuleID:                result.LongID,
+				RuleDescription:       result.RuleDescription,
+				Severity:              result.Severity,
+				Resolution:            result.Resolution,
+				Links:                 result.Links,
+			})
+		}
+	}
+
+	return securityDrift
+}
+
+// recentCloudAction returns the most recent cloud actor and action timestamp recorded for the drifted resource.
+func recentCloudAction(resource driftDetector.DriftedResourceAttributes, differences []driftDetector.AttributeDifference) (string, string) {
+	for _, difference := range differences {
+		if difference.AttributeDetail == resource.AttributeDetail && difference.InstanceID == resource.InstanceID && difference.RecentActor != "" {
+			return string(difference.RecentActor), string(difference.RecentActionTimestamp)
+		}
+	}
+	return "", ""
+}
+
+// unflattenAttributes converts flat attributes, as found within terraformer state files, into nested maps and
+// slices. Count entries ("#" and "%") are dropped, and maps whose keys are all indices are converted into slices.
+func unflattenAttributes(flatAttributes map[string]string) map[string]interface{} {
+	nested := map[string]interface{}{}
+
+	for key, value := range flatAttributes {
+		path := strings.Split(key, ".")
+		if last := path[len(path)-1]; last == "#" || last == "%" {
+			continue
+		}
+
+		current := nested
+		for _, segment := range path[:len(path)-1] {
+			next, ok := current[segment].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				current[segment] = next
+			}
+			current = next
+		}
+		current[path[len(path)-1]] = value
+	}
+
+	return convertIndexedMaps(nested).(map[string]interface{})
+}
+
+// convertIndexedMaps recursively converts maps whose keys are all indices into slices ordered by index.
+func convertIndexedMaps(value interface{}) interface{} {
+	currentMap, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	indices := make([]int, 0, len(currentMap))
+	for key, child := range currentMap {
+		currentMap[key] = convertIndexedMaps(child)
+		if index, err := strconv.Atoi(key); err == nil {
+			indices = append(indices, index)
+		}
+	}
+
+	if len(currentMap) == 0 || len(indices) != len(currentMap) {
+		return currentMap
+	}
+
+	sort.Ints(indices)
+	output := make([]interface{}, 0, len(indices))
+	for _, index := range indices {
+		output = append(output, currentMap[strconv.Itoa(index)])
+	}
+	return output
+}
+
+// writeNestedAttributes writes nested attributes into an HCL body. Objects containing nested values, and lists of
+// objects, are written as blocks so that they are recognized by tfsec in the same way as terraformer output.
+func writeNestedAttributes(body *hclwrite.Body, attributes map[string]interface{}) {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := attributes[key].(type) {
+		case map[string]interface{}:
+			if isPrimitiveMap(value) {
+				body.SetAttributeValue(key, primitiveMapValue(value))
+				continue
+			}
+			writeNestedAttributes(body.AppendNewBlock(key, nil).Body(), value)
+		case []interface{}:
+			if isObjectSlice(value) {
+				for _, element := range value {
+					writeNestedAttributes(body.AppendNewBlock(key, nil).Body(), element.(map[string]interface{}))
+				}
+				continue
+			}
+
+			elements := make([]cty.Value, 0, len(value))
+			for _, element := range value {
+				if elementString, ok := element.(string); ok {
+					elements = append(elements, primitiveValue(elementString))
+				}
+			}
+			body.SetAttributeValue(key, cty.TupleVal(elements))
+		case string:
+			body.SetAttributeValue(key, primitiveValue(value))
+		}
+	}
+}
+
+// isPrimitiveMap returns true if every value within the map is a primitive.
+func isPrimitiveMap(value map[string]interface{}) bool {
+	for _, child := range value {
+		if _, ok := child.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isObjectSlice returns true if every element within the slice is an object.
+func isObjectSlice(value []interface{}) bool {
+	for _, element := range value {
+		if _, ok := element.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// primitiveMapValue converts a map of primitives into an object value.
+func primitiveMapValue(value map[string]interface{}) cty.Value {
+	attributes := map[string]cty.Value{}
+	for key, child := range value {
+		attributes[key] = cty.StringVal(child.(string))
+	}
+	return cty.ObjectVal(attributes)
+}
+
+// primitiveValue converts a flat attribute value into a bool, number or string value.
+func primitiveValue(value string) cty.Value {
+	if value == "true" || value == "false" {
+		return cty.BoolVal(value == "true")
+	}
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(number, 10) == value {
+		return cty.NumberIntVal(number)
+	}
+	return cty.StringVal(value)
+}
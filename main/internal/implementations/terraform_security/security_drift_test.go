@@ -0,0 +1,103 @@
+package terraformsecurity
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/stretchr/testify/assert"
+
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
+)
+
+func TestUnflattenAttributesAndWriteHCL(t *testing.T) {
+	// Given
+	flatAttributes := map[string]string{
+		"name":                      "web",
+		"ingress.#":                 "1",
+		"ingress.0.from_port":       "22",
+		"ingress.0.to_port":         "22",
+		"ingress.0.cidr_blocks.#":   "1",
+		"ingress.0.cidr_blocks.0":   "0.0.0.0/0",
+		"ingress.0.self":            "false",
+		"tags.%":                    "1",
+		"tags.Name":                 "web",
+		"versioning.0.enabled":      "true",
+		"versioning.0.mfa_delete.0": "Disabled",
+	}
+
+	// When
+	f := hclwrite.NewEmptyFile()
+	resourceBlock := f.Body().AppendNewBlock("resource", []string{"aws_security_group", securityDriftResourceName(0)})
+	writeNestedAttributes(resourceBlock.Body(), unflattenAttributes(flatAttributes))
+
+	// Then
+	expected := `resource "aws_security_group" "drifted_0" {
+  ingress {
+    cidr_blocks = ["0.0.0.0/0"]
+    from_port   = 22
+    self        = false
+    to_port     = 22
+  }
+  name = "web"
+  tags = {
+    Name = "web"
+  }
+  versioning {
+    enabled    = true
+    mfa_delete = ["Disabled"]
+  }
+}
+`
+	assert.Equal(t, expected, string(f.Bytes()))
+}
+
+func TestIdentifySecurityDrift(t *testing.T) {
+	// Given
+	detail := driftDetector.AttributeDetail{
+		StateFileName: "workspace",
+		ResourceType:  "aws_security_group",
+		ResourceName:  "web",
+	}
+	driftedResources := []driftDetector.DriftedResourceAttributes{
+		{InstanceID: "sg-1", AttributeDetail: detail},
+	}
+
+	terraformResults := groupResultsByDriftedResource([]Result{
+		{LongID: "aws-ec2-add-description-to-security-group", Resource: "aws_security_group.drifted_0"},
+	})
+	cloudResults := groupResultsByDriftedResource([]Result{
+		{LongID: "aws-ec2-add-description-to-security-group", Resource: "aws_security_group.drifted_0"},
+		{
+			LongID:          "aws-ec2-no-public-ingress-sgr",
+			RuleDescription: "An ingress security group rule allows traffic from /0.",
+			Severity:        "CRITICAL",
+			Resolution:      "Set a more restrictive cidr range",
+			Resource:        "aws_security_group.drifted_0.ingress[0]",
+		},
+		{LongID: "aws-ec2-no-public-ingress-sgr", Resource: "aws_security_group.drifted_0.ingress[1]"},
+	})
+
+	differences := []driftDetector.AttributeDifference{
+		{InstanceID: "sg-1", AttributeDetail: detail, AttributeName: "ingress.0.cidr_blocks.0"},
+		{InstanceID: "sg-1", AttributeDetail: detail, RecentActor: "user@example.com", RecentActionTimestamp: "2023-06-01"},
+	}
+
+	// When
+	securityDrift := identifySecurityDrift(driftedResources, terraformResults, cloudResults, differences)
+
+	// Then
+	assert.Equal(t, []SecurityDrift{
+		{
+			InstanceID:            "sg-1",
+			StateFileName:         "workspace",
+			ResourceType:          "aws_security_group",
+			ResourceName:          "web",
+			RecentActor:           "user@example.com",
+			RecentActionTimestamp: "2023-06-01",
+			RuleID:                "aws-ec2-no-public-ingress-sgr",
+			RuleDescription:       "An ingress security group rule allows traffic from /0.",
+			Severity:              "CRITICAL",
+			Resolution:            "Set a more restrictive cidr range",
+		},
+	}, securityDrift)
+}
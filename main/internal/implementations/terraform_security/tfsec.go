@@ -64,7 +64,7 @@ func NewTFSec(provider terraformValueObjects.Provider) *TFSec {
 }
 
 // ExecuteScan is called from the main job flow to execute the tfsec command and save the output
-// to show to the user in the PR
+// to show to the user in the PR. Drifted resources are also scanned to identify drift that weakened security.
 func (s *TFSec) ExecuteScan(_ context.Context) error {
 	logrus.Debugf("[tfsec][execute_scan][provider: %s]", s.provider)
 	contentResults, err := s.runTFSec()
@@ -87,13 +87,21 @@ func (s *TFSec) ExecuteScan(_ context.Context) error {
 		return fmt.Errorf("[tfsec][execute_scan][error writing tfsec results][%v]", err)
 	}
 
+	err = s.executeSecurityDriftScan()
+	if err != nil {
+		return fmt.Errorf("[tfsec][execute_scan][error scanning drifted resources][%v]", err)
+	}
+
 	return nil
 }
 
 // runTFSec runs the tfsec command through the directories from the divisions configured by the user
 func (s *TFSec) runTFSec() ([]byte, error) {
-	tfsecScanningPath := "./current_cloud/"
-	outLocationFlag := "./current_cloud/tfsec.json"
+	return s.runTFSecOnDirectory("./current_cloud/", "./current_cloud/tfsec.json")
+}
+
+// runTFSecOnDirectory runs the tfsec command against the scanning path and returns the results written to outLocationFlag
+func (s *TFSec) runTFSecOnDirectory(tfsecScanningPath string, outLocationFlag string) ([]byte, error) {
 	outFlag := fmt.Sprintf("--out=%s", outLocationFlag)
 
 	cmd := exec.Command("tfsec", outFlag, "--format=json", "--soft-fail", tfsecScanningPath)
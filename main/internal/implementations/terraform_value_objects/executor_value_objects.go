@@ -18,6 +18,34 @@ type Division string
 // Provider is the name of a cloud computing resource provider.
 type Provider string
 
+// Runtime is the command line tool used to run Terraform configuration, either Terraform or OpenTofu.
+type Runtime string
+
+const (
+	// TerraformRuntime runs configuration with the `terraform` command line tool.
+	TerraformRuntime Runtime = "terraform"
+
+	// OpenTofuRuntime runs configuration with the `tofu` command line tool.
+	OpenTofuRuntime Runtime = "opentofu"
+)
+
+// Binary returns the name of the runtime's executable, defaulting to `terraform`.
+func (r Runtime) Binary() string {
+	if r == OpenTofuRuntime {
+		return "tofu"
+	}
+	return "terraform"
+}
+
+// RegistryHost returns the hostname of the registry from which the runtime installs providers without an explicit
+// hostname, defaulting to the Terraform registry.
+func (r Runtime) RegistryHost() string {
+	if r == OpenTofuRuntime {
+		return "registry.opentofu.org"
+	}
+	return "registry.terraform.io"
+}
+
 // Version is a Terraform module version string.
 type Version string
 
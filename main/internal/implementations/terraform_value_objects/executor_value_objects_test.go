@@ -52,3 +52,19 @@ func TestVersionDecoder(t *testing.T) {
 		})
 	}
 }
+
+func TestRuntime(t *testing.T) {
+	tests := []struct {
+		runtime      Runtime
+		binary       string
+		registryHost string
+	}{
+		{runtime: TerraformRuntime, binary: "terraform", registryHost: "registry.terraform.io"},
+		{runtime: OpenTofuRuntime, binary: "tofu", registryHost: "registry.opentofu.org"},
+		{runtime: Runtime(""), binary: "terraform", registryHost: "registry.terraform.io"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.binary, tt.runtime.Binary())
+		assert.Equal(t, tt.registryHost, tt.runtime.RegistryHost())
+	}
+}
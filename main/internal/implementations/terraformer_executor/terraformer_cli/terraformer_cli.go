@@ -41,6 +41,9 @@ type Config struct {
 
 	// ResourcesBlackList represents the list of resource names that will be excluded from consideration for inclusion in the import statement.
 	ResourcesBlackList terraformValueObjects.ResourceNameList
+
+	// Runtime is the command line tool, Terraform or OpenTofu, used to upgrade the terraformer generated state.
+	Runtime terraformValueObjects.Runtime
 }
 
 // terraformerCLI implements the TerraformerCLI interface.
@@ -108,7 +111,8 @@ func getActualImportProvider(provider string) string {
 }
 
 func (tfrCLI *terraformerCLI) UpdateState(provider string) error {
-	log.Debugf("Running terraform state replace-provider with provider: %s", provider)
+	binary := tfrCLI.config.Runtime.Binary()
+	log.Debugf("Running %s state replace-provider with provider: %s", binary, provider)
 
 	// Remove the provider.tf file that was generated by terraformer.
 	// Duplicate specified provider in the provider.tf file otherwise causes an error when running
@@ -118,16 +122,17 @@ func (tfrCLI *terraformerCLI) UpdateState(provider string) error {
 		return fmt.Errorf("[os.Remove]Error in removing 'provider.tf' file: %v", err)
 	}
 
-	// Specify the location of the state file, as well as the from and to provider plug in values.
+	// Specify the location of the state file, as well as the from and to provider plug in values. Terraformer
+	// always writes legacy Terraform registry addresses, while the upgraded address uses the runtime's registry.
 	stateFlag := "-state=./terraform.tfstate"
 	fromProvider := fmt.Sprintf("registry.terraform.io/-/%s", provider)
-	toProvider := fmt.Sprintf("hashicorp/%s", provider)
+	toProvider := fmt.Sprintf("%s/hashicorp/%s", tfrCLI.config.Runtime.RegistryHost(), provider)
 
 	args := []string{"state", "replace-provider", "-auto-approve", stateFlag, fromProvider, toProvider}
 
-	err = executeCommand("terraform", args...)
+	err = executeCommand(binary, args...)
 	if err != nil {
-		return fmt.Errorf("[UpdateState] Error in running 'terraform state replace-provider': %v", err)
+		return fmt.Errorf("[UpdateState] Error in running '%s state replace-provider': %v", binary, err)
 	}
 	return nil
 }
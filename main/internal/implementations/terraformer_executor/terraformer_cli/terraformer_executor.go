@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
@@ -23,14 +24,18 @@ type TerraformerExecutorConfig struct {
 	// Division is the name of a cloud division. In AWS this is an account, in GCP this is a project name, and in Azure this is a subscription.
 	Division terraformValueObjects.Division `required:"true"`
 
-	// Provider is a map between a cloud provider and the version for that provider.
+	// Provider is a map between a cloud provider and the version for that provider. The version is used only when
+	// the repository does not pin one.
 	Provider map[terraformValueObjects.Provider]string `required:"true"`
 
-	// TerraformVersion is the version of Terraform used.
-	TerraformVersion terraformValueObjects.Version `required:"true"`
+	// TerraformVersion is the version of Terraform used when the repository does not pin one.
+	TerraformVersion terraformValueObjects.Version
 
 	// CloudRegions represents the list of cloud regions that will be considered for inclusion in the import statement.
 	CloudRegions terraformValueObjects.CloudRegionsDecoder `required:"true"`
+
+	// Runtime is the command line tool, Terraform or OpenTofu, installed and used to initialize providers.
+	Runtime terraformValueObjects.Runtime
 }
 
 // TerraformerExecutor is a struct that implements interfaces.TerraformerExecutor
@@ -92,13 +97,18 @@ func getScanner(config TerraformerExecutorConfig, cliConfig Config, provider ter
 
 // Execute runs the workflow needed to capture the current state of an
 // external cloud environment via the terraformer package.
-func (e *TerraformerExecutor) Execute(_ context.Context) error {
-	err := e.makeProviderVersionFile()
+func (e *TerraformerExecutor) Execute(_ context.Context, workspaceToDirectory map[string]string) error {
+	versions, err := e.resolveRepositoryVersions(workspaceToDirectory)
+	if err != nil {
+		return fmt.Errorf("[terraformer_executor][set_up][error resolving repository versions]%w", err)
+	}
+
+	err = e.makeProviderVersionFile(versions)
 	if err != nil {
 		return fmt.Errorf("[terraformer_executor][set_up][error making provider version file]%w", err)
 	}
 
-	err = e.setTerraformVersion()
+	err = e.setTerraformVersion(versions.TerraformVersion)
 	if err != nil {
 		return fmt.Errorf("[terraformer_executor][set_up][error setting terraform version]%w", err)
 	}
@@ -116,30 +126,59 @@ func (e *TerraformerExecutor) Execute(_ context.Context) error {
 	return nil
 }
 
-// initializeTerraform initializes Terraform within the current working directory.
+// initializeTerraform initializes Terraform, or OpenTofu, within the current working directory.
 func (e *TerraformerExecutor) initializeTerraform() error {
 	err := os.Chdir("current_cloud/")
 	if err != nil {
 		return fmt.Errorf("[initialize_terraform][error changing working directory]%w", err)
 	}
 
-	cmd := exec.Command("terraform", "init")
+	cmd := exec.Command(e.config.Runtime.Binary(), "init")
 	var out bytes.Buffer
 	cmd.Stdout = &out
 
 	err = cmd.Run()
 	if err != nil {
-		return fmt.Errorf("[initialize_terraform][error in running 'terraform init': %s]%w", out.String(), err)
+		return fmt.Errorf("[initialize_terraform][error in running '%s init': %s]%w", e.config.Runtime.Binary(), out.String(), err)
 	}
 	fmt.Printf("%v", out.String())
 
 	return nil
 }
 
-// setTerraformVersion uses tfswitch to install the user-specified version of terraform
-func (e *TerraformerExecutor) setTerraformVersion() error {
-	tfVersion := string(e.config.TerraformVersion)
-	cmd := exec.Command("tfswitch", tfVersion)
+// resolveRepositoryVersions infers the Terraform and provider versions from the pins of each workspace, falling
+// back to the configured versions, and saves them for the report.
+func (e *TerraformerExecutor) resolveRepositoryVersions(workspaceToDirectory map[string]string) (hclcreate.RepositoryVersions, error) {
+	configuredProviders := make(map[string]string)
+	for provider, version := range e.config.Provider {
+		configuredProviders[string(provider)] = version
+	}
+
+	versions, err := hclcreate.ResolveRepositoryVersions(workspaceToDirectory, string(e.config.TerraformVersion), configuredProviders)
+	if err != nil {
+		return versions, fmt.Errorf("[resolve_repository_versions][error in hclcreate.ResolveRepositoryVersions]%w", err)
+	}
+
+	for _, mismatch := range versions.Mismatches {
+		log.Warnf("[resolve_repository_versions] %v: %v, using %v", mismatch.Name, mismatch.Reason, mismatch.Selected)
+	}
+
+	err = versions.Write()
+	if err != nil {
+		return versions, fmt.Errorf("[resolve_repository_versions][error saving repository versions]%w", err)
+	}
+
+	return versions, nil
+}
+
+// setTerraformVersion uses tfswitch to install the version of terraform, or OpenTofu, inferred from the repository
+func (e *TerraformerExecutor) setTerraformVersion(tfVersion string) error {
+	args := []string{tfVersion}
+	if e.config.Runtime == terraformValueObjects.OpenTofuRuntime {
+		args = []string{"--product=opentofu", tfVersion}
+	}
+
+	cmd := exec.Command("tfswitch", args...)
 	var out bytes.Buffer
 	cmd.Stdout = &out
 
@@ -147,7 +186,7 @@ func (e *TerraformerExecutor) setTerraformVersion() error {
 	if err != nil {
 		return fmt.Errorf(
 			"[set_terraform_version][error in running 'tfswitch %s' %s]%w",
-			e.config.TerraformVersion, out.String(), err,
+			strings.Join(args, " "), out.String(), err,
 		)
 	}
 	fmt.Printf("%v", out.String())
@@ -156,14 +195,8 @@ func (e *TerraformerExecutor) setTerraformVersion() error {
 }
 
 // makeProviderVersionFile writes an HCL file which defines all provider versions.
-func (e *TerraformerExecutor) makeProviderVersionFile() error {
-	genericProviders := make(map[string]string)
-
-	for provider, version := range e.config.Provider {
-		genericProviders[string(provider)] = string(version)
-	}
-
-	mainTF, err := e.hclCreate.CreateMainTF(genericProviders)
+func (e *TerraformerExecutor) makeProviderVersionFile(versions hclcreate.RepositoryVersions) error {
+	mainTF, err := e.hclCreate.CreateMainTF(versions.TerraformVersion, versions.Providers)
 	if err != nil {
 		return fmt.Errorf("[make_provider_version_file][error in creating main terraform file]%w", err)
 	}
@@ -0,0 +1,227 @@
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// codeOwnersLocations are the paths, relative to the repository root and in order of precedence, at which GitHub,
+// GitLab and Bitbucket look for a CODEOWNERS file.
+var codeOwnersLocations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+	".bitbucket/CODEOWNERS",
+}
+
+// codeOwnersSectionRegex matches a GitLab section header, such as `^[Networking][2] @org/network-team`. Patterns
+// beginning with a bracket class, such as `[Dd]ocs/`, only match when followed by whitespace.
+var codeOwnersSectionRegex = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?(\s.*)?$`)
+
+// CodeOwners is the set of rules within a CODEOWNERS file.
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+// codeOwnersRule is a single pattern within a CODEOWNERS file and its owners.
+type codeOwnersRule struct {
+	// section is the lower-cased GitLab section containing the rule, empty outside of any section.
+	section string
+
+	// pattern matches the slash separated paths, relative to the repository root, the rule applies to.
+	pattern *regexp.Regexp
+
+	// owners are the users, teams and email addresses owning matching paths.
+	owners []string
+}
+
+// ReadCodeOwners reads and parses the CODEOWNERS file within repoDirectory. Returns nil when the repository has no
+// CODEOWNERS file.
+func ReadCodeOwners(repoDirectory string) (*CodeOwners, error) {
+	for _, location := range codeOwnersLocations {
+		content, err := os.ReadFile(filepath.Join(repoDirectory, filepath.FromSlash(location)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("[os.ReadFile %v]%v", location, err)
+		}
+
+		codeOwners, err := ParseCodeOwners(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("[ParseCodeOwners %v]%v", location, err)
+		}
+		return codeOwners, nil
+	}
+
+	return nil, nil
+}
+
+// isCodeOwnersSection returns whether the text following a bracketed line start is a GitLab section's default
+// owners rather than the remainder of a path pattern and its owners, which is the case when it has no path field.
+func isCodeOwnersSection(remainder string) bool {
+	for _, field := range splitCodeOwnersLine(remainder) {
+		if !strings.Contains(field, "@") {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseCodeOwners parses the content of a CODEOWNERS file. GitHub syntax is supported along with GitLab sections,
+// including optional sections, required approval counts and section default owners, and Bitbucket `@@group`
+// owners. Bitbucket `CODEOWNERS.` settings and `Check(...)` approval rules are ignored.
+func ParseCodeOwners(content string) (*CodeOwners, error) {
+	codeOwners := &CodeOwners{}
+	section, sectionOwners := "", []string{}
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "CODEOWNERS.") || strings.HasPrefix(line, "Check(") {
+			continue
+		}
+
+		if match := codeOwnersSectionRegex.FindStringSubmatch(line); match != nil && isCodeOwnersSection(match[2]) {
+			section = strings.ToLower(strings.TrimSpace(match[1]))
+			sectionOwners = splitCodeOwnersLine(match[2])
+			continue
+		}
+
+		fields := splitCodeOwnersLine(line)
+		pattern, err := codeOwnersPatternRegex(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("[line %d][codeOwnersPatternRegex]%v", i+1, err)
+		}
+
+		owners := fields[1:]
+		if len(owners) == 0 {
+			owners = sectionOwners
+		}
+
+		codeOwners.rules = append(codeOwners.rules, codeOwnersRule{section: section, pattern: pattern, owners: owners})
+	}
+
+	return codeOwners, nil
+}
+
+// Owners returns the sorted owners of the given slash separated paths, relative to the repository root. As on
+// GitHub and Bitbucket, the last matching rule determines a path's owners. As on GitLab, each section contributes
+// the owners of its last matching rule.
+func (c *CodeOwners) Owners(paths []string) []string {
+	ownerSet := map[string]bool{}
+	for _, filePath := range paths {
+		filePath = strings.TrimPrefix(path.Clean("/"+filePath), "/")
+
+		sectionToOwners := map[string][]string{}
+		for _, rule := range c.rules {
+			if rule.pattern.MatchString(filePath) {
+				sectionToOwners[rule.section] = rule.owners
+			}
+		}
+
+		for _, owners := range sectionToOwners {
+			for _, owner := range owners {
+				ownerSet[owner] = true
+			}
+		}
+	}
+
+	owners := []string{}
+	for owner := range ownerSet {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// splitCodeOwnersLine splits a CODEOWNERS line into whitespace separated fields, dropping trailing comments and
+// honoring backslash escaped spaces and '#' characters.
+func splitCodeOwnersLine(line string) []string {
+	fields := []string{}
+	var field strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		character := line[i]
+		switch {
+		case character == '\\' && i+1 < len(line):
+			i++
+			field.WriteByte(line[i])
+		case character == '#':
+			i = len(line)
+		case character == ' ' || character == '\t':
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteByte(character)
+		}
+	}
+
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// codeOwnersPatternRegex converts a gitignore style CODEOWNERS pattern into a regular expression matching the
+// paths the pattern applies to, including every path within a matching directory.
+func codeOwnersPatternRegex(pattern string) (*regexp.Regexp, error) {
+	directoryOnly := strings.HasSuffix(pattern, "/")
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var expression strings.Builder
+	if anchored {
+		expression.WriteString("^")
+	} else {
+		expression.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expression.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expression.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expression.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expression.WriteString("[^/]")
+		case pattern[i] == '[' && strings.Index(pattern[i+1:], "]") > 0:
+			end := i + 1 + strings.Index(pattern[i+1:], "]")
+			class := pattern[i+1 : end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expression.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i = end
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+
+	if directoryOnly {
+		expression.WriteString("/.*$")
+	} else if strings.HasSuffix(pattern, "*") && !strings.HasSuffix(pattern, "**") {
+		// As on GitHub, `docs/*` matches the files directly within docs but not those within its subdirectories.
+		expression.WriteString("$")
+	} else {
+		expression.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(expression.String())
+}
@@ -0,0 +1,108 @@
+package vcs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwners_Owners_GitHub(t *testing.T) {
+	// Given
+	codeOwners, err := ParseCodeOwners(`# Default owners
+*                       @org/platform
+
+/dev/                   @org/dev-team @alice # dev workspace
+prod/**/cloud-concierge @org/prod-team
+docs/*                  docs@example.com
+/staging/ignored.tf
+`)
+	require.NoError(t, err)
+
+	// When, Then
+	assert.Equal(t, []string{"@alice", "@org/dev-team"}, codeOwners.Owners([]string{"dev/cloud-concierge/imports/abc_imports.tf"}))
+	assert.Equal(t, []string{"@org/prod-team"}, codeOwners.Owners([]string{"prod/eu/cloud-concierge/removed/abc.tf"}))
+	assert.Equal(t, []string{"@org/platform"}, codeOwners.Owners([]string{"prod/eu/new-resources.tf"}))
+	assert.Equal(t, []string{"docs@example.com"}, codeOwners.Owners([]string{"docs/index.md"}))
+	assert.Equal(t, []string{"@org/platform"}, codeOwners.Owners([]string{"docs/guides/index.md"}))
+	assert.Equal(t, []string{}, codeOwners.Owners([]string{"staging/ignored.tf"}))
+	assert.Equal(t, []string{"@alice", "@org/dev-team", "@org/platform"}, codeOwners.Owners([]string{"dev/new-resources.tf", "main.tf"}))
+}
+
+func TestCodeOwners_Owners_GitLabSections(t *testing.T) {
+	// Given
+	codeOwners, err := ParseCodeOwners(`[Terraform] @infra
+*.tf
+/dev/ @dev-lead
+
+^[Networking][2] @org/network
+dev/network/
+
+[terraform]
+/prod/new-resources.tf @prod-lead
+`)
+	require.NoError(t, err)
+
+	// When, Then
+	assert.Equal(t, []string{"@dev-lead", "@org/network"}, codeOwners.Owners([]string{"dev/network/new-resources.tf"}))
+	assert.Equal(t, []string{"@prod-lead"}, codeOwners.Owners([]string{"prod/new-resources.tf"}))
+	assert.Equal(t, []string{"@infra"}, codeOwners.Owners([]string{"staging/main.tf"}))
+}
+
+func TestCodeOwners_Owners_BracketPatterns(t *testing.T) {
+	// Given
+	codeOwners, err := ParseCodeOwners(`[Dd]ocs/ @docs-team
+[Tt]erraform/*.tf @infra
+
+[Networking] @org/network
+dev/network/
+`)
+	require.NoError(t, err)
+
+	// When, Then
+	assert.Equal(t, []string{"@docs-team"}, codeOwners.Owners([]string{"Docs/index.md"}))
+	assert.Equal(t, []string{"@infra"}, codeOwners.Owners([]string{"terraform/main.tf"}))
+	assert.Equal(t, []string{"@org/network"}, codeOwners.Owners([]string{"dev/network/new-resources.tf"}))
+}
+
+func TestCodeOwners_Owners_Bitbucket(t *testing.T) {
+	// Given
+	codeOwners, err := ParseCodeOwners(`CODEOWNERS.destination_branch_pattern main
+CODEOWNERS.toplevel.assignment_routing random 1
+
+**/cloud-concierge/** @@InfraTeam @bob
+path\ with\ spaces/  @carol
+Check(@@InfraTeam >= 1)
+`)
+	require.NoError(t, err)
+
+	// When, Then
+	assert.Equal(t, []string{"@@InfraTeam", "@bob"}, codeOwners.Owners([]string{"dev/cloud-concierge/imports/abc_imports.tf"}))
+	assert.Equal(t, []string{"@carol"}, codeOwners.Owners([]string{"path with spaces/main.tf"}))
+}
+
+func TestReadCodeOwners(t *testing.T) {
+	// Given
+	repoDirectory := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repoDirectory, ".github"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDirectory, ".github", "CODEOWNERS"), []byte("* @github-owner\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(repoDirectory, "CODEOWNERS"), []byte("* @root-owner\n"), 0o644))
+
+	// When
+	codeOwners, err := ReadCodeOwners(repoDirectory)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []string{"@github-owner"}, codeOwners.Owners([]string{"main.tf"}))
+}
+
+func TestReadCodeOwners_NoCodeOwners(t *testing.T) {
+	// When
+	codeOwners, err := ReadCodeOwners(t.TempDir())
+
+	// Then
+	require.NoError(t, err)
+	assert.Nil(t, codeOwners)
+}
@@ -0,0 +1,299 @@
+package vcs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const (
+	// defaultCommitAuthorName is the name commits are authored with when none is configured.
+	defaultCommitAuthorName = "dragondrop.cloud"
+
+	// defaultCommitAuthorEmail is the email commits are authored with when none is configured.
+	defaultCommitAuthorEmail = "cloud-concierge@dragondrop.cloud"
+
+	// defaultCommitMessageTemplate is the commit message used when no template is configured.
+	defaultCommitMessageTemplate = "build: cloud-concierge results"
+
+	// sshSignatureNamespace is the namespace git uses for SSH commit signatures.
+	sshSignatureNamespace = "git"
+)
+
+// commitSettings are the identities, message and signer with which changes are committed.
+type commitSettings struct {
+	// authorName and authorEmail identify the author of commits.
+	authorName, authorEmail string
+
+	// committerName and committerEmail identify the committer of commits.
+	committerName, committerEmail string
+
+	// messageTemplate renders commit messages from commitMessageData.
+	messageTemplate *template.Template
+
+	// runID identifies the job run within commit messages. The branch's unique ID is used when empty.
+	runID string
+
+	// signer signs commits, nil when commits are not signed.
+	signer commitSigner
+}
+
+// commitMessageData is the data commit message templates are rendered with.
+type commitMessageData struct {
+	// JobName is the name of the job, including the scope of the pull request when split.
+	JobName string
+
+	// NewResources is the number of resources outside of Terraform control within the report.
+	NewResources int
+
+	// DriftedResources is the number of drifted resource instances within the report.
+	DriftedResources int
+
+	// DeletedResources is the number of deleted resources within the report.
+	DeletedResources int
+
+	// RunID identifies the job run.
+	RunID string
+}
+
+// commitSigner signs the encoded content of a commit, returning an armored signature.
+type commitSigner interface {
+	Sign(message io.Reader) ([]byte, error)
+}
+
+// ParseCommitMessageTemplate parses a commit message template, returning the default message's template when text
+// is empty.
+func ParseCommitMessageTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = defaultCommitMessageTemplate
+	}
+
+	commitMessageTemplate, err := template.New("commit-message").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("[template.Parse]%v", err)
+	}
+
+	return commitMessageTemplate, nil
+}
+
+// newCommitSettings returns the commit settings within config, using the default identity and message when none
+// are configured. The committer defaults to the author.
+func newCommitSettings(config Config) (commitSettings, error) {
+	messageTemplate, err := ParseCommitMessageTemplate(config.CommitMessageTemplate)
+	if err != nil {
+		return commitSettings{}, fmt.Errorf("[ParseCommitMessageTemplate]%v", err)
+	}
+
+	settings := commitSettings{
+		authorName:      valueOrDefault(config.CommitAuthorName, defaultCommitAuthorName),
+		authorEmail:     valueOrDefault(config.CommitAuthorEmail, defaultCommitAuthorEmail),
+		messageTemplate: messageTemplate,
+		runID:           config.RunID,
+	}
+	settings.committerName = valueOrDefault(config.CommitterName, settings.authorName)
+	settings.committerEmail = valueOrDefault(config.CommitterEmail, settings.authorEmail)
+
+	if config.CommitSigningKey != "" {
+		settings.signer, err = newCommitSigner(config.CommitSigningKey, config.CommitSigningKeyPassphrase)
+		if err != nil {
+			return commitSettings{}, fmt.Errorf("[newCommitSigner]%v", err)
+		}
+	}
+
+	return settings, nil
+}
+
+// author returns the signature commits are authored with.
+func (s commitSettings) author(when time.Time) *object.Signature {
+	return &object.Signature{Name: s.authorName, Email: s.authorEmail, When: when}
+}
+
+// committer returns the signature commits are committed with.
+func (s commitSettings) committer(when time.Time) *object.Signature {
+	return &object.Signature{Name: s.committerName, Email: s.committerEmail, When: when}
+}
+
+// message renders the commit message for the job with the counts of resources within the report summary. A missing
+// summary renders zero counts.
+func (s commitSettings) message(jobName string, branchID string, reportSummaryPath string) (string, error) {
+	data := commitMessageData{JobName: jobName, RunID: valueOrDefault(s.runID, branchID)}
+
+	content, err := os.ReadFile(reportSummaryPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("[os.ReadFile %v]%v", reportSummaryPath, err)
+	}
+
+	if err == nil {
+		summary := struct {
+			NewResources     int `json:"newResources"`
+			DriftedResources int `json:"driftedResources"`
+			DeletedResources int `json:"deletedResources"`
+		}{}
+
+		err = json.Unmarshal(content, &summary)
+		if err != nil {
+			return "", fmt.Errorf("[json.Unmarshal %v]%v", reportSummaryPath, err)
+		}
+
+		data.NewResources = summary.NewResources
+		data.DriftedResources = summary.DriftedResources
+		data.DeletedResources = summary.DeletedResources
+	}
+
+	var message strings.Builder
+	err = s.messageTemplate.Execute(&message, data)
+	if err != nil {
+		return "", fmt.Errorf("[messageTemplate.Execute]%v", err)
+	}
+	return message.String(), nil
+}
+
+// newCommitSigner returns a signer for the armored PGP or OpenSSH private key, given either directly or as the path
+// of a file containing it.
+func newCommitSigner(signingKey string, passphrase string) (commitSigner, error) {
+	keyBytes, err := readPEM(signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("[readPEM]%v", err)
+	}
+
+	if bytes.Contains(keyBytes, []byte(openpgp.PrivateKeyType)) {
+		return newGPGSigner(keyBytes, passphrase)
+	}
+	return newSSHSigner(keyBytes, passphrase)
+}
+
+// gpgSigner signs commits with a PGP key.
+type gpgSigner struct {
+	entity *openpgp.Entity
+}
+
+// newGPGSigner returns a signer for the first key within the armored PGP key ring, decrypting it with passphrase
+// when it is encrypted.
+func newGPGSigner(keyBytes []byte, passphrase string) (*gpgSigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(keyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("[openpgp.ReadArmoredKeyRing]%v", err)
+	}
+
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, errors.New("PGP key ring contains no private key")
+	}
+
+	entity := entities[0]
+	if passphrase != "" {
+		err = entity.DecryptPrivateKeys([]byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("[entity.DecryptPrivateKeys]%v", err)
+		}
+	}
+
+	return &gpgSigner{entity: entity}, nil
+}
+
+// Sign returns an armored detached PGP signature of message.
+func (s *gpgSigner) Sign(message io.Reader) ([]byte, error) {
+	var signature bytes.Buffer
+	err := openpgp.ArmoredDetachSign(&signature, s.entity, message, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[openpgp.ArmoredDetachSign]%v", err)
+	}
+	return signature.Bytes(), nil
+}
+
+// sshSigner signs commits with an SSH key, producing the SSHSIG signatures git verifies with ssh-keygen.
+type sshSigner struct {
+	signer gossh.Signer
+}
+
+// newSSHSigner returns a signer for the OpenSSH or PEM encoded private key, decrypting it with passphrase when set.
+func newSSHSigner(keyBytes []byte, passphrase string) (*sshSigner, error) {
+	var signer gossh.Signer
+	var err error
+	if passphrase != "" {
+		signer, err = gossh.ParsePrivateKeyWithPassphrase(keyBytes, []byte(passphrase))
+	} else {
+		signer, err = gossh.ParsePrivateKey(keyBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[ssh.ParsePrivateKey]%v", err)
+	}
+
+	return &sshSigner{signer: signer}, nil
+}
+
+// Sign returns an armored SSHSIG signature of message within the git namespace, as described by OpenSSH's
+// PROTOCOL.sshsig.
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	hash := sha512.New()
+	_, err := io.Copy(hash, message)
+	if err != nil {
+		return nil, fmt.Errorf("[io.Copy]%v", err)
+	}
+
+	signedData := sshSignedData(hash.Sum(nil))
+
+	var signature *gossh.Signature
+	algorithmSigner, ok := s.signer.(gossh.AlgorithmSigner)
+	if ok && s.signer.PublicKey().Type() == gossh.KeyAlgoRSA {
+		// ssh-keygen rejects SHA-1 RSA signatures, so RSA keys sign with SHA-512.
+		signature, err = algorithmSigner.SignWithAlgorithm(rand.Reader, signedData, gossh.KeyAlgoRSASHA512)
+	} else {
+		signature, err = s.signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("[signer.Sign]%v", err)
+	}
+
+	envelope := gossh.Marshal(struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{
+		Version:       1,
+		PublicKey:     s.signer.PublicKey().Marshal(),
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     gossh.Marshal(signature),
+	})
+
+	return pem.EncodeToMemory(&pem.Block{Type: "SSH SIGNATURE", Bytes: append([]byte("SSHSIG"), envelope...)}), nil
+}
+
+// sshSignedData returns the data an SSHSIG signature signs for the SHA-512 hash of a message.
+func sshSignedData(messageHash []byte) []byte {
+	return append([]byte("SSHSIG"), gossh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{
+		Namespace:     sshSignatureNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          messageHash,
+	})...)
+}
+
+// valueOrDefault returns value, or defaultValue when value is empty.
+func valueOrDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,194 @@
+package vcs
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// newSigningRepository initializes a repository within a temporary directory, returning a gitRepository checked out
+// on its first commit and the repository's directory.
+func newSigningRepository(t *testing.T, settings commitSettings) (*gitRepository, string) {
+	repositoryDirectory := t.TempDir()
+	repository, err := git.PlainInit(repositoryDirectory, false)
+	require.NoError(t, err)
+
+	workTree, err := repository.Worktree()
+	require.NoError(t, err)
+
+	return &gitRepository{
+		commitSettings: settings,
+		jobName:        "Drift Job",
+		ID:             "2023-01-01-00-00",
+		repository:     repository,
+		workTree:       workTree,
+	}, repositoryDirectory
+}
+
+func commitFile(t *testing.T, g *gitRepository, repositoryDirectory string) {
+	err := os.WriteFile(filepath.Join(repositoryDirectory, "new-resources.tf"), []byte("# new\n"), 0o600)
+	require.NoError(t, err)
+	_, err = g.workTree.Add("new-resources.tf")
+	require.NoError(t, err)
+
+	require.NoError(t, g.Commit())
+}
+
+func TestCommitSettings_Message(t *testing.T) {
+	// Given
+	summaryPath := filepath.Join(t.TempDir(), "report-summary.json")
+	err := os.WriteFile(summaryPath, []byte(`{"newResources": 3, "driftedResources": 2, "deletedResources": 1}`), 0o400)
+	require.NoError(t, err)
+
+	settings, err := newCommitSettings(Config{
+		CommitMessageTemplate: "chore(drift): {{.JobName}} [{{.RunID}}]\n\n" +
+			"new: {{.NewResources}}, drifted: {{.DriftedResources}}, deleted: {{.DeletedResources}}",
+		RunID: "run-42",
+	})
+	require.NoError(t, err)
+
+	// When
+	message, err := settings.message("Drift Job", "2023-01-01-00-00", summaryPath)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "chore(drift): Drift Job [run-42]\n\nnew: 3, drifted: 2, deleted: 1", message)
+}
+
+func TestCommitSettings_Defaults(t *testing.T) {
+	// Given
+	settings, err := newCommitSettings(Config{CommitMessageTemplate: "{{.JobName}} {{.RunID}} {{.NewResources}}"})
+	require.NoError(t, err)
+
+	// When
+	message, err := settings.message("Drift Job", "2023-01-01-00-00", filepath.Join(t.TempDir(), "missing.json"))
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "Drift Job 2023-01-01-00-00 0", message)
+	assert.Equal(t, "dragondrop.cloud", settings.author(time.Time{}).Name)
+	assert.Equal(t, "cloud-concierge@dragondrop.cloud", settings.committer(time.Time{}).Email)
+}
+
+func TestCommitSettings_InvalidTemplate(t *testing.T) {
+	// When
+	_, err := newCommitSettings(Config{CommitMessageTemplate: "{{.JobName"})
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestGitRepository_CommitSignedWithGPG(t *testing.T) {
+	// Given
+	entity, err := openpgp.NewEntity("Infra Bot", "", "infra-bot@example.com", nil)
+	require.NoError(t, err)
+
+	var privateKey bytes.Buffer
+	privateKeyWriter, err := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(privateKeyWriter, nil))
+	require.NoError(t, privateKeyWriter.Close())
+
+	var publicKey bytes.Buffer
+	publicKeyWriter, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(publicKeyWriter))
+	require.NoError(t, publicKeyWriter.Close())
+
+	settings, err := newCommitSettings(Config{
+		CommitAuthorName:  "Infra Bot",
+		CommitAuthorEmail: "infra-bot@example.com",
+		CommitterName:     "Release Bot",
+		CommitterEmail:    "release-bot@example.com",
+		CommitSigningKey:  privateKey.String(),
+	})
+	require.NoError(t, err)
+	g, repositoryDirectory := newSigningRepository(t, settings)
+
+	// When
+	commitFile(t, g, repositoryDirectory)
+
+	// Then
+	head, err := g.repository.Head()
+	require.NoError(t, err)
+	commit, err := g.repository.CommitObject(head.Hash())
+	require.NoError(t, err)
+
+	_, err = commit.Verify(publicKey.String())
+	assert.Nil(t, err)
+	assert.Equal(t, "Infra Bot", commit.Author.Name)
+	assert.Equal(t, "release-bot@example.com", commit.Committer.Email)
+	assert.Equal(t, "build: cloud-concierge results", commit.Message)
+
+	_, err = commit.File("new-resources.tf")
+	assert.Nil(t, err)
+}
+
+func TestGitRepository_CommitSignedWithSSH(t *testing.T) {
+	// Given
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privateKeyBlock, err := gossh.MarshalPrivateKey(privateKey, "")
+	require.NoError(t, err)
+
+	settings, err := newCommitSettings(Config{CommitSigningKey: string(pem.EncodeToMemory(privateKeyBlock))})
+	require.NoError(t, err)
+	g, repositoryDirectory := newSigningRepository(t, settings)
+
+	// When
+	commitFile(t, g, repositoryDirectory)
+
+	// Then
+	head, err := g.repository.Head()
+	require.NoError(t, err)
+	commit, err := g.repository.CommitObject(head.Hash())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(commit.PGPSignature, "-----BEGIN SSH SIGNATURE-----"))
+
+	signatureBlock, _ := pem.Decode([]byte(commit.PGPSignature))
+	require.NotNil(t, signatureBlock)
+	require.True(t, bytes.HasPrefix(signatureBlock.Bytes, []byte("SSHSIG")))
+
+	envelope := struct {
+		Version       uint32
+		PublicKey     []byte
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Signature     []byte
+	}{}
+	require.NoError(t, gossh.Unmarshal(signatureBlock.Bytes[len("SSHSIG"):], &envelope))
+	assert.Equal(t, "git", envelope.Namespace)
+
+	signature := &gossh.Signature{}
+	require.NoError(t, gossh.Unmarshal(envelope.Signature, signature))
+
+	publicKey, err := gossh.ParsePublicKey(envelope.PublicKey)
+	require.NoError(t, err)
+
+	encoded := g.repository.Storer.NewEncodedObject()
+	require.NoError(t, commit.EncodeWithoutSignature(encoded))
+	reader, err := encoded.Reader()
+	require.NoError(t, err)
+
+	hash := sha512.New()
+	_, err = io.Copy(hash, reader)
+	require.NoError(t, err)
+
+	assert.Nil(t, publicKey.Verify(sshSignedData(hash.Sum(nil)), signature))
+}
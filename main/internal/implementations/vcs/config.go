@@ -5,11 +5,68 @@ package vcs
 type Config struct {
 	// VCSPat is the personal access token for the customer's VCS account. It must have
 	// the necessary permissions to open pull requests and push commits to the VCSRepo specified below.
-	VCSPat string `required:"true"`
+	// Not needed when authenticating as a GitHub App.
+	VCSPat string
 
 	// VCSRepo is the full path of the repo containing a customer's infrastructure specification.
 	VCSRepo string `required:"true"`
 
-	// PullReviewers is the name of the pull request reviewer who will be tagged on the opened pull request.
+	// VCSBaseURL is the API base URL of a GitHub Enterprise Server instance, such as
+	// https://github.example.com/api/v3/. When empty, the API of the VCSRepo's host is used.
+	VCSBaseURL string
+
+	// GitHubAppID is the ID of the GitHub App to authenticate as instead of using VCSPat.
+	GitHubAppID int64
+
+	// GitHubAppInstallationID is the ID of the GitHub App's installation. When zero, the installation on
+	// VCSRepo is used.
+	GitHubAppInstallationID int64
+
+	// GitHubAppPrivateKey is the GitHub App's PEM encoded private key, or the path of a file containing it.
+	GitHubAppPrivateKey string
+
+	// VCSSSHKey is the PEM encoded private key, or the path of a file containing it, used to clone and push to
+	// VCSRepo over SSH.
+	VCSSSHKey string
+
+	// VCSSSHKeyPassphrase is the passphrase of VCSSSHKey, if it is encrypted.
+	VCSSSHKeyPassphrase string
+
+	// VCSSSHKnownHosts are the known_hosts entries, or the path of a known_hosts file, used to verify the SSH host
+	// key of VCSRepo. When empty, the files within SSH_KNOWN_HOSTS or ~/.ssh/known_hosts are used.
+	VCSSSHKnownHosts string
+
+	// ReviewRequestWebhook is a URL to which a review request for the pushed branch is posted, when the repository
+	// is not hosted on a supported VCS.
+	ReviewRequestWebhook string
+
+	// ReviewRequestCommand is a shell command run to open a review request for the pushed branch, when the
+	// repository is not hosted on a supported VCS.
+	ReviewRequestCommand string
+
+	// CommitAuthorName and CommitAuthorEmail identify the author of commits, dragondrop.cloud by default.
+	CommitAuthorName  string
+	CommitAuthorEmail string
+
+	// CommitterName and CommitterEmail identify the committer of commits, the author by default.
+	CommitterName  string
+	CommitterEmail string
+
+	// CommitMessageTemplate is a Go template rendering commit messages. Fields are JobName, NewResources,
+	// DriftedResources, DeletedResources and RunID.
+	CommitMessageTemplate string
+
+	// CommitSigningKey is the armored PGP or OpenSSH private key, or the path of a file containing it, used to sign
+	// commits. Commits are unsigned when empty.
+	CommitSigningKey string
+
+	// CommitSigningKeyPassphrase is the passphrase of CommitSigningKey, if it is encrypted.
+	CommitSigningKeyPassphrase string
+
+	// RunID identifies the job run within commit messages. The branch's unique ID is used when empty.
+	RunID string
+
+	// PullReviewers is the name of the pull request reviewer who will be tagged on the opened pull request. Used
+	// when the repository has no CODEOWNERS file, or it names no owners of the files cloud-concierge writes.
 	PullReviewers []string `default:"NoReviewer"`
 }
@@ -28,9 +28,11 @@ func (f *Factory) Instantiate(_ context.Context, environment string, config Conf
 func (f *Factory) bootstrappedVCS(config Config, vcsSystem string) (interfaces.VCS, error) {
 	switch vcsSystem {
 	case "github":
-		return NewGitHub(config), nil
+		return NewGitHub(config)
+	case "git":
+		return NewGenericGit(config)
 	default:
-		log.Errorf("currently only GitHub is supported as a VCS option. %v was specified", vcsSystem)
-		return nil, fmt.Errorf("currently only GitHub is supported as a VCS option. %v was specified", vcsSystem)
+		log.Errorf("currently only GitHub and generic git over SSH are supported as VCS options. %v was specified", vcsSystem)
+		return nil, fmt.Errorf("currently only GitHub and generic git over SSH are supported as VCS options. %v was specified", vcsSystem)
 	}
 }
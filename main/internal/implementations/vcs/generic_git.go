@@ -0,0 +1,352 @@
+package vcs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
+)
+
+// reviewReportPath is the path, relative to the repository root, at which the report is committed to the pushed
+// branch, since a generic git server has no pull request to hold it.
+const reviewReportPath = "cloud-concierge/report.md"
+
+// GenericGit implements the VCS interface for a repository on any git server reachable over SSH. Branches are
+// pushed with the report committed alongside the changes, and a review request is optionally opened by a
+// configured webhook or command.
+type GenericGit struct {
+	gitRepository
+
+	// config contains the values that allow for authentication and the specific repo
+	// traits needed.
+	config Config
+}
+
+// reviewRequest describes the pushed branch to a review request webhook or command.
+type reviewRequest struct {
+	// Title is the title of the review request.
+	Title string `json:"title"`
+
+	// Repository is the URL of the repository.
+	Repository string `json:"repository"`
+
+	// Branch is the name of the pushed branch.
+	Branch string `json:"branch"`
+
+	// BaseBranch is the name of the branch the changes should be merged into.
+	BaseBranch string `json:"baseBranch"`
+
+	// Report is the markdown report of the changes.
+	Report string `json:"report"`
+
+	// Reviewers are the CODEOWNERS owners, configured reviewers and people responsible for the reported changes.
+	Reviewers []string `json:"reviewers"`
+}
+
+// reviewRequestResponse is the optional JSON response of a review request webhook.
+type reviewRequestResponse struct {
+	// URL is the URL of the opened review request.
+	URL string `json:"url"`
+}
+
+// NewGenericGit creates a new instance of the GenericGit struct, authenticating with the configured SSH key.
+func NewGenericGit(config Config) (interfaces.VCS, error) {
+	auth, err := newSSHAuth(config)
+	if err != nil {
+		return nil, fmt.Errorf("[newSSHAuth]%v", err)
+	}
+
+	settings, err := newCommitSettings(config)
+	if err != nil {
+		return nil, fmt.Errorf("[newCommitSettings]%v", err)
+	}
+
+	return &GenericGit{
+		gitRepository: gitRepository{auth: auth, commitSettings: settings, repoURL: config.VCSRepo},
+		config:        config,
+	}, nil
+}
+
+// AddChanges writes the report into the repository and adds all code changes to be included in the next commit.
+func (g *GenericGit) AddChanges() error {
+	reportContent, err := os.ReadFile("state_of_cloud/report.md")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("[vcs][add_changes][error in loading state of cloud report]%w", err)
+	}
+
+	if err == nil {
+		reportPath := filepath.Join("repo", filepath.FromSlash(reviewReportPath))
+		err = os.MkdirAll(filepath.Dir(reportPath), 0o755)
+		if err != nil {
+			return fmt.Errorf("[vcs][add_changes][error in os.MkdirAll]%w", err)
+		}
+
+		err = os.WriteFile(reportPath, reportContent, 0o644)
+		if err != nil {
+			return fmt.Errorf("[vcs][add_changes][error in writing %v]%w", reviewReportPath, err)
+		}
+	}
+
+	return g.gitRepository.AddChanges()
+}
+
+// OpenPullRequest requests a review of the pushed branch with the configured webhook and command. Returns the url of
+// the review request reported by the webhook or command, and otherwise the name of the pushed branch.
+func (g *GenericGit) OpenPullRequest(jobName string) (string, error) {
+	request, err := g.newReviewRequest(jobName)
+	if err != nil {
+		return "", fmt.Errorf("[g.newReviewRequest]%v", err)
+	}
+
+	reviewURL := ""
+	if g.config.ReviewRequestWebhook != "" {
+		reviewURL, err = g.postReviewRequest(request)
+		if err != nil {
+			return "", fmt.Errorf("[g.postReviewRequest]%v", err)
+		}
+	}
+
+	if g.config.ReviewRequestCommand != "" {
+		commandURL, err := g.runReviewRequestCommand(request)
+		if err != nil {
+			return "", fmt.Errorf("[g.runReviewRequestCommand]%v", err)
+		}
+
+		if commandURL != "" {
+			reviewURL = commandURL
+		}
+	}
+
+	if reviewURL == "" {
+		logrus.Infof("[GenericGit] Pushed branch %v without opening a review request", g.newBranchName)
+		return g.newBranchName, nil
+	}
+
+	logrus.Infof("[GenericGit] Review request opened with url %v", reviewURL)
+	return reviewURL, nil
+}
+
+// GetUsernameByEmail returns an empty string, as a generic git server has no directory of users.
+func (g *GenericGit) GetUsernameByEmail(email string) (string, error) {
+	logrus.Debugf("[GenericGit] Cannot look up the user with email %v on a generic git server", email)
+	return "", nil
+}
+
+// SetToken sets the SSH authentication used for git operations.
+func (g *GenericGit) SetToken() {
+	auth, err := newSSHAuth(g.config)
+	if err != nil {
+		logrus.Errorf("[GenericGit] Unable to create SSH authentication: %v", err)
+		return
+	}
+	g.auth = auth
+}
+
+// newReviewRequest describes the pushed branch, its report and its reviewers.
+func (g *GenericGit) newReviewRequest(jobName string) (reviewRequest, error) {
+	reportContent, err := os.ReadFile("state_of_cloud/report.md")
+	if err != nil {
+		return reviewRequest{}, fmt.Errorf("error in loading state of cloud report: %v", err)
+	}
+
+	reviewers, err := g.reviewers()
+	if err != nil {
+		return reviewRequest{}, fmt.Errorf("[g.reviewers]%v", err)
+	}
+
+	return reviewRequest{
+		Title:      fmt.Sprintf("%v - %v", jobName, g.ID),
+		Repository: g.config.VCSRepo,
+		Branch:     g.newBranchName,
+		BaseBranch: g.baseBranch,
+		Report:     string(reportContent),
+		Reviewers:  reviewers,
+	}, nil
+}
+
+// reviewers returns the CODEOWNERS owners of the files cloud-concierge wrote, falling back to the configured
+// PullReviewers, along with the people responsible for the reported changes when requested.
+func (g *GenericGit) reviewers() ([]string, error) {
+	reviewers, err := g.codeOwners()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reviewers) == 0 && len(g.config.PullReviewers) > 0 && g.config.PullReviewers[0] != "NoReviewer" {
+		reviewers = append(reviewers, g.config.PullReviewers...)
+	}
+
+	requestedReviewers, err := readRequestedReviewers("state_of_cloud/requested-reviewers.json")
+	if err != nil {
+		return nil, fmt.Errorf("[readRequestedReviewers]%v", err)
+	}
+
+	for _, reviewer := range requestedReviewers {
+		if !containsFold(reviewers, reviewer) {
+			reviewers = append(reviewers, reviewer)
+		}
+	}
+
+	return reviewers, nil
+}
+
+// postReviewRequest posts the review request as JSON to the configured webhook, returning the url within the
+// webhook's response when it has one.
+func (g *GenericGit) postReviewRequest(request reviewRequest) (string, error) {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("[json.Marshal]%v", err)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(context.Background(), "POST", g.config.ReviewRequestWebhook, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return "", fmt.Errorf("[http.NewRequestWithContext]%v", err)
+	}
+
+	httpRequest.Header = http.Header{
+		"Content-Type": {"application/json"},
+	}
+
+	response, err := http.DefaultClient.Do(httpRequest)
+	if err != nil {
+		return "", fmt.Errorf("[http.DefaultClient.Do]%v", err)
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("[io.ReadAll]%v", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return "", fmt.Errorf("review request webhook responded with status %v: %v", response.StatusCode, string(responseBody))
+	}
+
+	webhookResponse := reviewRequestResponse{}
+	if err := json.Unmarshal(responseBody, &webhookResponse); err != nil {
+		logrus.Debugf("[GenericGit] Review request webhook response is not JSON: %v", err)
+	}
+
+	return webhookResponse.URL, nil
+}
+
+// runReviewRequestCommand runs the configured shell command within the repository, with the review request as JSON on
+// its standard input and within CLOUDCONCIERGE_REVIEW_* environment variables. Returns the last line the command
+// outputs, which is expected to be the url of the review request when it outputs one.
+func (g *GenericGit) runReviewRequestCommand(request reviewRequest) (string, error) {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("[json.Marshal]%v", err)
+	}
+
+	reportPath, err := filepath.Abs("state_of_cloud/report.md")
+	if err != nil {
+		return "", fmt.Errorf("[filepath.Abs]%v", err)
+	}
+
+	cmd := exec.Command("sh", "-c", g.config.ReviewRequestCommand)
+	cmd.Dir = "repo"
+	cmd.Stdin = bytes.NewReader(requestBody)
+	cmd.Env = append(
+		os.Environ(),
+		"CLOUDCONCIERGE_REVIEW_TITLE="+request.Title,
+		"CLOUDCONCIERGE_REVIEW_REPOSITORY="+request.Repository,
+		"CLOUDCONCIERGE_REVIEW_BRANCH="+request.Branch,
+		"CLOUDCONCIERGE_REVIEW_BASEBRANCH="+request.BaseBranch,
+		"CLOUDCONCIERGE_REVIEW_REPORTPATH="+reportPath,
+		"CLOUDCONCIERGE_REVIEW_REVIEWERS="+strings.Join(request.Reviewers, ","),
+	)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("%v\n\n%v", err, stderr.String()+out.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// newSSHAuth returns SSH public key authentication for the configured repository, verifying the server's host key
+// against the configured known hosts.
+func newSSHAuth(config Config) (*ssh.PublicKeys, error) {
+	if config.VCSSSHKey == "" {
+		return nil, errors.New("an SSH key is required to access a generic git repository")
+	}
+
+	keyBytes, err := readPEM(config.VCSSSHKey)
+	if err != nil {
+		return nil, fmt.Errorf("[readPEM]%v", err)
+	}
+
+	endpoint, err := transport.NewEndpoint(config.VCSRepo)
+	if err != nil {
+		return nil, fmt.Errorf("[transport.NewEndpoint %v]%v", config.VCSRepo, err)
+	}
+
+	user := endpoint.User
+	if user == "" {
+		user = "git"
+	}
+
+	auth, err := ssh.NewPublicKeys(user, keyBytes, config.VCSSSHKeyPassphrase)
+	if err != nil {
+		return nil, fmt.Errorf("[ssh.NewPublicKeys]%v", err)
+	}
+
+	knownHostsFiles, err := sshKnownHostsFiles(config.VCSSSHKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("[sshKnownHostsFiles]%v", err)
+	}
+
+	auth.HostKeyCallback, err = ssh.NewKnownHostsCallback(knownHostsFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("[ssh.NewKnownHostsCallback]%v", err)
+	}
+
+	return auth, nil
+}
+
+// sshKnownHostsFiles returns the known_hosts files to verify host keys against. known_hosts entries, which always
+// contain a space, are written to a temporary file, while a path is used directly. Returns no files, so that the
+// default known_hosts files are used, when knownHosts is empty.
+func sshKnownHostsFiles(knownHosts string) ([]string, error) {
+	if knownHosts == "" {
+		return nil, nil
+	}
+
+	if !strings.ContainsAny(knownHosts, " \t") {
+		return []string{knownHosts}, nil
+	}
+
+	knownHostsFile, err := os.CreateTemp("", "known_hosts")
+	if err != nil {
+		return nil, fmt.Errorf("[os.CreateTemp]%v", err)
+	}
+	defer knownHostsFile.Close()
+
+	_, err = knownHostsFile.WriteString(strings.TrimSpace(knownHosts) + "\n")
+	if err != nil {
+		return nil, fmt.Errorf("[knownHostsFile.WriteString]%v", err)
+	}
+
+	return []string{knownHostsFile.Name()}, nil
+}
@@ -0,0 +1,184 @@
+package vcs
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func generateHostKey(t *testing.T) gossh.PublicKey {
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sshPublicKey, err := gossh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+	return sshPublicKey
+}
+
+// newBareRemote creates a bare repository with a single commit on main, returning its path.
+func newBareRemote(t *testing.T) string {
+	sourceDirectory := t.TempDir()
+	source, err := git.PlainInit(sourceDirectory, false)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(sourceDirectory, "main.tf"), []byte("# main\n"), 0o600)
+	require.NoError(t, err)
+
+	workTree, err := source.Worktree()
+	require.NoError(t, err)
+	_, err = workTree.Add("main.tf")
+	require.NoError(t, err)
+	_, err = workTree.Commit("initial", &git.CommitOptions{Author: &object.Signature{Name: "test", Email: "test@example.com"}})
+	require.NoError(t, err)
+
+	remoteDirectory := t.TempDir()
+	_, err = git.PlainClone(remoteDirectory, true, &git.CloneOptions{URL: sourceDirectory})
+	require.NoError(t, err)
+	return remoteDirectory
+}
+
+func TestNewSSHAuth_KnownHosts(t *testing.T) {
+	// Given
+	_, keyPEM := generateAppPrivateKey(t)
+	hostKey := generateHostKey(t)
+	knownHosts := "git.example.com " + string(gossh.MarshalAuthorizedKey(hostKey))
+	hostAddress := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 22}
+
+	// When
+	auth, err := newSSHAuth(Config{
+		VCSRepo:          "git@git.example.com:org/infrastructure.git",
+		VCSSSHKey:        keyPEM,
+		VCSSSHKnownHosts: knownHosts,
+	})
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "git", auth.User)
+	assert.Nil(t, auth.HostKeyCallback("git.example.com:22", hostAddress, hostKey))
+	assert.NotNil(t, auth.HostKeyCallback("git.example.com:22", hostAddress, generateHostKey(t)))
+	assert.NotNil(t, auth.HostKeyCallback("other.example.com:22", hostAddress, hostKey))
+}
+
+func TestNewSSHAuth_MissingKey(t *testing.T) {
+	// When
+	_, err := newSSHAuth(Config{VCSRepo: "ssh://git@git.example.com/org/infrastructure.git"})
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestGenericGit_PushAndRequestReview(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+	require.NoError(t, os.Chdir(t.TempDir()))
+
+	remote := newBareRemote(t)
+	_, keyPEM := generateAppPrivateKey(t)
+
+	var request reviewRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		fmt.Fprint(w, `{"url": "https://review.example.com/1"}`)
+	}))
+	defer server.Close()
+
+	vcs, err := NewGenericGit(Config{
+		VCSRepo:              remote,
+		VCSSSHKey:            keyPEM,
+		VCSSSHKnownHosts:     "git.example.com " + string(gossh.MarshalAuthorizedKey(generateHostKey(t))),
+		PullReviewers:        []string{"alice"},
+		ReviewRequestWebhook: server.URL,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll("state_of_cloud", 0o755))
+	require.NoError(t, os.WriteFile("state_of_cloud/report.md", []byte("# Report\n"), 0o600))
+
+	// When
+	require.NoError(t, vcs.Clone())
+	require.NoError(t, vcs.Checkout("Drift Job"))
+	require.NoError(t, os.WriteFile("repo/new-resources.tf", []byte("# new\n"), 0o600))
+	require.NoError(t, vcs.AddChanges())
+	require.NoError(t, vcs.Commit())
+	require.NoError(t, vcs.Push())
+	reviewURL, err := vcs.OpenPullRequest("Drift Job")
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, "https://review.example.com/1", reviewURL)
+
+	genericGit := vcs.(*GenericGit)
+	assert.Equal(t, genericGit.newBranchName, request.Branch)
+	assert.Equal(t, "master", request.BaseBranch)
+	assert.Equal(t, "# Report\n", request.Report)
+	assert.Equal(t, []string{"alice"}, request.Reviewers)
+
+	remoteRepository, err := git.PlainOpen(remote)
+	require.NoError(t, err)
+	branch, err := remoteRepository.Reference(plumbing.NewBranchReferenceName(genericGit.newBranchName), true)
+	require.NoError(t, err)
+	commit, err := remoteRepository.CommitObject(branch.Hash())
+	require.NoError(t, err)
+
+	report, err := commit.File(reviewReportPath)
+	require.NoError(t, err)
+	reportContent, err := report.Contents()
+	require.NoError(t, err)
+	assert.Equal(t, "# Report\n", reportContent)
+
+	_, err = commit.File("new-resources.tf")
+	assert.Nil(t, err)
+}
+
+func TestGenericGit_RunReviewRequestCommand(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	require.NoError(t, os.MkdirAll("repo", 0o755))
+
+	g := &GenericGit{config: Config{
+		ReviewRequestCommand: `echo "opening review"; echo "https://review.example.com/$CLOUDCONCIERGE_REVIEW_BRANCH"`,
+	}}
+
+	// When
+	reviewURL, err := g.runReviewRequestCommand(reviewRequest{Branch: "feature/drift"})
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "https://review.example.com/feature/drift", reviewURL)
+}
+
+func TestGenericGit_RunReviewRequestCommandFails(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+	require.NoError(t, os.Chdir(t.TempDir()))
+	require.NoError(t, os.MkdirAll("repo", 0o755))
+
+	g := &GenericGit{config: Config{ReviewRequestCommand: "exit 1"}}
+
+	// When
+	_, err = g.runReviewRequestCommand(reviewRequest{Branch: "feature/drift"})
+
+	// Then
+	assert.NotNil(t, err)
+}
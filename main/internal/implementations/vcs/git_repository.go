@@ -0,0 +1,324 @@
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/sirupsen/logrus"
+)
+
+// gitRepository performs the git operations shared by each VCS implementation on a local clone of the repository.
+type gitRepository struct {
+	// auth is the authentication information needed to perform generic git operations.
+	auth transport.AuthMethod
+
+	// baseBranch is the name of the branch the repository was cloned at.
+	baseBranch string
+
+	// baseCommit is the commit the repository was cloned at, from which each new branch is created.
+	baseCommit plumbing.Hash
+
+	// commitSettings are the identities, message and signer with which changes are committed.
+	commitSettings commitSettings
+
+	// ID is a string which is a random, 10 character unique identifier
+	// for a cloud-concierge built commit/pull request
+	ID string
+
+	// jobName is the name of the job the current branch was checked out for.
+	jobName string
+
+	// newBranchName is the name of the new branch name for the new pull request.
+	newBranchName string
+
+	// repoURL is the URL the repository is cloned from and pushed to.
+	repoURL string
+
+	// repository is a code repository object from the go-git package which represents the customer's
+	// code repository containing IaC.
+	repository *git.Repository
+
+	// workTree is the working tree object which references repository
+	workTree *git.Worktree
+}
+
+// GetID returns a string which is a random, 10 character unique identifier
+// for a cloud-concierge built commit/pull request
+func (g *gitRepository) GetID() (string, error) {
+	if strings.Trim(g.ID, "") == "" {
+		return "", errors.New("[vcs][get_id][id not generated]")
+	}
+
+	logrus.Debugf("[vcs] ID is %v", g.ID)
+	return g.ID, nil
+}
+
+// Clone pulls a remote repository's contents into local memory.
+func (g *gitRepository) Clone() error {
+	cloneOptions := &git.CloneOptions{
+		Auth:     g.auth,
+		URL:      g.repoURL,
+		Progress: os.Stdout,
+	}
+
+	// Cleaning out the existing repository folder. Cannot clone into an already existing directory.
+	err := os.RemoveAll("./repo/")
+	if err != nil {
+		return err
+	}
+
+	repo, err := git.PlainClone("./repo/", false, cloneOptions)
+	if err != nil {
+		return err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+
+	g.repository = repo
+	g.baseBranch = head.Name().Short()
+	g.baseCommit = head.Hash()
+
+	logrus.Debugf("[vcs] Cloned repo %v", g.repoURL)
+	return nil
+}
+
+// AddChanges adds all code changes to be included in the next commit.
+func (g *gitRepository) AddChanges() error {
+	logrus.Debugf("[vcs] Adding changes to repo %v", g.repoURL)
+	addOptions := &git.AddOptions{
+		All: true,
+	}
+
+	err := g.workTree.AddWithOptions(addOptions)
+	if err != nil {
+		return fmt.Errorf("[vcs][add_changed][error in worktree.AddWithOptions]%w", err)
+	}
+
+	return nil
+}
+
+// Checkout creates a new branch within the remote repository from the cloned commit, keeping local changes.
+func (g *gitRepository) Checkout(jobName string) error {
+	lowerJobName := strings.ToLower(jobName)
+	jobNameSplit := strings.Split(lowerJobName, " ")
+	cleanJobName := strings.Join(jobNameSplit, "_")
+
+	branchUniqueID := time.Now().Format("2006-01-02-15-04")
+
+	newBranchName := fmt.Sprintf(
+		"feature/cloud_concierge_%v_%v",
+		cleanJobName,
+		branchUniqueID,
+	)
+
+	g.newBranchName = newBranchName
+
+	branchName := plumbing.NewBranchReferenceName(newBranchName)
+
+	// Branches are created from the cloned commit while keeping local changes, so that each pull request opened
+	// within a job contains only the changes written for it.
+	checkoutOptions := &git.CheckoutOptions{
+		Hash:   g.baseCommit,
+		Branch: branchName,
+		Create: true,
+		Keep:   true,
+	}
+
+	workTree, err := g.repository.Worktree()
+	if err != nil {
+		return fmt.Errorf("[vcs][checkout][error in creating worktree]%w", err)
+	}
+
+	err = workTree.Checkout(checkoutOptions)
+	if err != nil {
+		return fmt.Errorf("[vcs][checkout][error in checking out a new branch for the suggested changes]%w", err)
+	}
+
+	g.workTree = workTree
+	g.ID = branchUniqueID
+	g.jobName = jobName
+
+	logrus.Debugf("[vcs] Checked out branch %v", g.newBranchName)
+	return nil
+}
+
+// Commit commits code changes to the current branch of the remote repository, with the configured identities and
+// templated message, signing the commit when a signing key is configured.
+func (g *gitRepository) Commit() error {
+	logrus.Debugf("[vcs] Committing changes to repo %v", g.repoURL)
+
+	message, err := g.commitSettings.message(g.jobName, g.ID, "state_of_cloud/report-summary.json")
+	if err != nil {
+		return fmt.Errorf("[vcs][commit][error in rendering the commit message]%w", err)
+	}
+
+	now := time.Now()
+	commitOptions := &git.CommitOptions{
+		All:       true,
+		Author:    g.commitSettings.author(now),
+		Committer: g.commitSettings.committer(now),
+	}
+
+	commitHash, err := g.workTree.Commit(message, commitOptions)
+	if err != nil {
+		return fmt.Errorf("[vcs][commit][error in worktree.AddWithOptions]%w", err)
+	}
+
+	if g.commitSettings.signer != nil {
+		commitHash, err = g.signHeadCommit(commitHash)
+		if err != nil {
+			return fmt.Errorf("[vcs][commit][error in signing the commit]%w", err)
+		}
+	}
+
+	fmt.Printf("Commit made with hash: %v\n", commitHash)
+
+	return nil
+}
+
+// signHeadCommit replaces the commit at the head of the current branch with a signed copy, returning the signed
+// commit's hash.
+func (g *gitRepository) signHeadCommit(commitHash plumbing.Hash) (plumbing.Hash, error) {
+	commit, err := g.repository.CommitObject(commitHash)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[g.repository.CommitObject %v]%v", commitHash, err)
+	}
+
+	unsigned := &plumbing.MemoryObject{}
+	err = commit.EncodeWithoutSignature(unsigned)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[commit.EncodeWithoutSignature]%v", err)
+	}
+
+	unsignedReader, err := unsigned.Reader()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[unsigned.Reader]%v", err)
+	}
+
+	signature, err := g.commitSettings.signer.Sign(unsignedReader)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[signer.Sign]%v", err)
+	}
+	commit.PGPSignature = string(signature)
+
+	signed := g.repository.Storer.NewEncodedObject()
+	err = commit.Encode(signed)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[commit.Encode]%v", err)
+	}
+
+	signedHash, err := g.repository.Storer.SetEncodedObject(signed)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[Storer.SetEncodedObject]%v", err)
+	}
+
+	head, err := g.repository.Head()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[g.repository.Head]%v", err)
+	}
+
+	err = g.repository.Storer.SetReference(plumbing.NewHashReference(head.Name(), signedHash))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("[Storer.SetReference %v]%v", head.Name(), err)
+	}
+
+	return signedHash, nil
+}
+
+// Push pushes current branch to remote repository.
+func (g *gitRepository) Push() error {
+	logrus.Debugf("[vcs] Pushing changes to repo %v", g.repoURL)
+
+	branchName := plumbing.NewBranchReferenceName(g.newBranchName)
+	pushOptions := &git.PushOptions{
+		Auth:     g.auth,
+		Progress: os.Stdout,
+		RefSpecs: []gitConfig.RefSpec{gitConfig.RefSpec(fmt.Sprintf("%v:%v", branchName, branchName))},
+	}
+
+	err := g.repository.Push(pushOptions)
+	if err != nil {
+		return fmt.Errorf("[vcs][push][error in repository.Push]%w", err)
+	}
+
+	return nil
+}
+
+// committedFiles returns the slash separated paths of the files changed between the cloned commit and the head of
+// the current branch.
+func (g *gitRepository) committedFiles() ([]string, error) {
+	head, err := g.repository.Head()
+	if err != nil {
+		return nil, fmt.Errorf("[g.repository.Head]%v", err)
+	}
+
+	headTree, err := g.commitTree(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	baseTree, err := g.commitTree(g.baseCommit)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(baseTree, headTree)
+	if err != nil {
+		return nil, fmt.Errorf("[object.DiffTree]%v", err)
+	}
+
+	files := []string{}
+	for _, change := range changes {
+		if change.To.Name != "" {
+			files = append(files, change.To.Name)
+		} else {
+			files = append(files, change.From.Name)
+		}
+	}
+	return files, nil
+}
+
+// commitTree returns the tree of the commit with the given hash.
+func (g *gitRepository) commitTree(hash plumbing.Hash) (*object.Tree, error) {
+	commit, err := g.repository.CommitObject(hash)
+	if err != nil {
+		return nil, fmt.Errorf("[g.repository.CommitObject %v]%v", hash, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("[commit.Tree %v]%v", hash, err)
+	}
+	return tree, nil
+}
+
+// codeOwners returns the owners of the files cloud-concierge committed according to the repository's CODEOWNERS
+// file. Returns no owners when the repository has no CODEOWNERS file.
+func (g *gitRepository) codeOwners() ([]string, error) {
+	codeOwners, err := ReadCodeOwners("./repo/")
+	if err != nil {
+		return nil, fmt.Errorf("[ReadCodeOwners]%v", err)
+	}
+
+	if codeOwners == nil {
+		return nil, nil
+	}
+
+	committedFiles, err := g.committedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("[g.committedFiles]%v", err)
+	}
+
+	return codeOwners.Owners(cloudConciergeFiles(committedFiles)), nil
+}
@@ -2,17 +2,12 @@ package vcs
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
-	"regexp"
 	"strings"
-	"time"
 
-	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/google/go-github/v45/github"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/oauth2"
@@ -22,8 +17,7 @@ import (
 
 // GitHub struct implements the VCS interface.
 type GitHub struct {
-	// authBasic is the authentication information needed to perform generic git operations via
-	authBasic *http.BasicAuth
+	gitRepository
 
 	// config contains the values that allow for authentication and the specific repo
 	// traits needed.
@@ -32,40 +26,49 @@ type GitHub struct {
 	// defaultBranch is the name of the default branch of the repository.
 	defaultBranch string
 
-	// ID is a string which is a random, 10 character unique identifier
-	// for a cloud-concierge built commit/pull request
-	ID string
-
-	// newBranchName is the name of the new branch name for the new pull request.
-	newBranchName string
+	// tokenSource provides the personal access token or, for a GitHub App, the current installation token.
+	tokenSource oauth2.TokenSource
 
 	// oauth2Client is an authenticated client that is able
 	// to access the customer's GitHub account. Primarily used for opening pull requests.
 	oauth2Client *github.Client
-
-	// repository is a code repository object from the go-git package which represents the customer's
-	// code repository containing IaC.
-	repository *git.Repository
-
-	// workTree is the working tree object which references repository
-	workTree *git.Worktree
 }
 
-// NewGitHub creates a new instance of the GitHub struct.
-func NewGitHub(config Config) interfaces.VCS {
+// NewGitHub creates a new instance of the GitHub struct, authenticating as a GitHub App when one is configured
+// and otherwise with the personal access token.
+func NewGitHub(config Config) (interfaces.VCS, error) {
+	settings, err := newCommitSettings(config)
+	if err != nil {
+		return nil, fmt.Errorf("[newCommitSettings]%v", err)
+	}
+
 	githubInstance := &GitHub{
-		config: config,
+		gitRepository: gitRepository{commitSettings: settings, repoURL: config.VCSRepo},
+		config:        config,
+		tokenSource:   oauth2.StaticTokenSource(&oauth2.Token{TokenType: "Bearer", AccessToken: config.VCSPat}),
+	}
+
+	if config.GitHubAppID != 0 {
+		tokenSource, err := newAppInstallationTokenSource(config)
+		if err != nil {
+			return nil, fmt.Errorf("[newAppInstallationTokenSource]%v", err)
+		}
+		githubInstance.tokenSource = tokenSource
 	}
 
 	githubInstance.SetToken()
-	return githubInstance
+	if githubInstance.oauth2Client == nil {
+		return nil, fmt.Errorf("[NewGitHub] unable to create a GitHub client for %v", config.VCSRepo)
+	}
+	return githubInstance, nil
 }
 
 // GetDefaultBranch returns the default branch of the repository.
 func (g *GitHub) GetDefaultBranch() error {
-	vcsCloneURLArray := strings.Split(g.config.VCSRepo, "/")
-	repoName := strings.Replace(vcsCloneURLArray[len(vcsCloneURLArray)-1], ".git", "", -1)
-	repoOwner := vcsCloneURLArray[len(vcsCloneURLArray)-2]
+	repoOwner, repoName, err := extractOrgAndRepoName(g.config.VCSRepo)
+	if err != nil {
+		return fmt.Errorf("[extractOrgAndRepoName]%v", err)
+	}
 
 	repoReference, _, err := g.oauth2Client.Repositories.Get(context.Background(), repoOwner, repoName)
 	if err != nil {
@@ -78,135 +81,32 @@ func (g *GitHub) GetDefaultBranch() error {
 	return nil
 }
 
-// GetID returns a string which is a random, 10 character unique identifier
-// for a cloud-concierge built commit/pull request
-func (g *GitHub) GetID() (string, error) {
-	if strings.Trim(g.ID, "") == "" {
-		return "", errors.New("[vcs][get_id][id not generated]")
-	}
-
-	logrus.Debugf("[Github] ID is %v", g.ID)
-	return g.ID, nil
-}
-
-// Clone pulls a remote repository's contents into local memory.
-func (g *GitHub) Clone() error {
-	cloneOptions := &git.CloneOptions{
-		Auth:     g.authBasic,
-		URL:      g.config.VCSRepo,
-		Progress: os.Stdout,
-	}
-
-	// Cleaning out the existing repository folder. Cannot clone into an already existing directory.
-	err := os.RemoveAll("./repo/")
+// GetUsernameByEmail returns the login of the GitHub user with the given public email address, or else of the author
+// of the most recent commit authored with that email address. Returns an empty string when no user is found.
+func (g *GitHub) GetUsernameByEmail(email string) (string, error) {
+	users, _, err := g.oauth2Client.Search.Users(context.Background(), fmt.Sprintf("%v in:email", email), nil)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("[g.oauth2Client.Search.Users]%v", err)
 	}
 
-	repo, err := git.PlainClone("./repo/", false, cloneOptions)
-	if err != nil {
-		return err
+	if len(users.Users) == 1 {
+		return users.Users[0].GetLogin(), nil
 	}
 
-	g.repository = repo
-
-	logrus.Debugf("[Github] Cloned repo %v", g.config.VCSRepo)
-	return nil
-}
-
-// AddChanges adds all code changes to be included in the next commit.
-func (g *GitHub) AddChanges() error {
-	logrus.Debugf("[Github] Adding changes to repo %v", g.config.VCSRepo)
-	addOptions := &git.AddOptions{
-		All: true,
-	}
-
-	err := g.workTree.AddWithOptions(addOptions)
-	if err != nil {
-		return fmt.Errorf("[vcs][add_changed][error in worktree.AddWithOptions]%w", err)
-	}
-
-	return nil
-}
-
-// Checkout creates a new branch within the remote repository.
-func (g *GitHub) Checkout(jobName string) error {
-	lowerJobName := strings.ToLower(jobName)
-	jobNameSplit := strings.Split(lowerJobName, " ")
-	cleanJobName := strings.Join(jobNameSplit, "_")
-
-	branchUniqueID := time.Now().Format("2006-01-02-15-04")
-
-	newBranchName := fmt.Sprintf(
-		"feature/cloud_concierge_%v_%v",
-		cleanJobName,
-		branchUniqueID,
+	commits, _, err := g.oauth2Client.Search.Commits(
+		context.Background(),
+		fmt.Sprintf("author-email:%v", email),
+		&github.SearchOptions{Sort: "author-date", Order: "desc", ListOptions: github.ListOptions{PerPage: 1}},
 	)
-
-	g.newBranchName = newBranchName
-
-	branchName := plumbing.NewBranchReferenceName(newBranchName)
-
-	checkoutOptions := &git.CheckoutOptions{
-		Branch: branchName,
-		Create: true,
-	}
-
-	workTree, err := g.repository.Worktree()
 	if err != nil {
-		return fmt.Errorf("[vcs][checkout][error in creating worktree]%w", err)
+		return "", fmt.Errorf("[g.oauth2Client.Search.Commits]%v", err)
 	}
 
-	err = workTree.Checkout(checkoutOptions)
-	if err != nil {
-		return fmt.Errorf("[vcs][checkout][error in checking out a new branch for the suggested changes]%w", err)
+	if len(commits.Commits) > 0 && commits.Commits[0].Author != nil {
+		return commits.Commits[0].Author.GetLogin(), nil
 	}
 
-	g.workTree = workTree
-	g.ID = branchUniqueID
-
-	logrus.Debugf("[Github] Checked out branch %v", g.newBranchName)
-	return nil
-}
-
-// Commit commits code changes to the current branch of the remote repository.
-func (g *GitHub) Commit() error {
-	logrus.Debugf("[Github] Committing changes to repo %v", g.config.VCSRepo)
-
-	commitOptions := &git.CommitOptions{
-		All: true,
-		Author: &object.Signature{
-			Name:  "dragondrop.cloud",
-			Email: "cloud-concierge@dragondrop.cloud",
-			When:  time.Now(),
-		},
-	}
-
-	commitHash, err := g.workTree.Commit("build: cloud-concierge results", commitOptions)
-	if err != nil {
-		return fmt.Errorf("[vcs][commit][error in worktree.AddWithOptions]%w", err)
-	}
-
-	fmt.Printf("Commit made with hash: %v\n", commitHash)
-
-	return nil
-}
-
-// Push pushes current branch to remote repository.
-func (g *GitHub) Push() error {
-	logrus.Debugf("[Github] Pushing changes to repo %v", g.config.VCSRepo)
-
-	pushOptions := &git.PushOptions{
-		Auth:     g.authBasic,
-		Progress: os.Stdout,
-	}
-
-	err := g.repository.Push(pushOptions)
-	if err != nil {
-		return fmt.Errorf("[vcs][push][error in repository.Push]%w", err)
-	}
-
-	return nil
+	return "", nil
 }
 
 // OpenPullRequest opens a new pull request of committed changes to the remote repository.
@@ -234,7 +134,7 @@ func (g *GitHub) OpenPullRequest(jobName string) (string, error) {
 		MaintainerCanModify: github.Bool(true),
 	}
 
-	orgName, repoName, err := g.extractOrgAndRepoName(g.config.VCSRepo)
+	orgName, repoName, err := extractOrgAndRepoName(g.config.VCSRepo)
 	if err != nil {
 		return "", fmt.Errorf("[extractOrgAndRepoName] %v", err)
 	}
@@ -249,59 +149,84 @@ func (g *GitHub) OpenPullRequest(jobName string) (string, error) {
 		return "", fmt.Errorf("error in github.PullRequests.Create(): %v", err)
 	}
 
-	if g.config.PullReviewers[0] != "NoReviewer" {
-		rr := github.ReviewersRequest{
-			Reviewers: g.config.PullReviewers,
-		}
-
-		_, _, err = g.oauth2Client.PullRequests.RequestReviewers(
-			context.Background(),
-			orgName,
-			repoName,
-			pr.GetNumber(),
-			rr,
-		)
-		if err != nil {
-			return "", fmt.Errorf("error in github.PullRequests.RequestReviewers(): %v", err)
-		}
+	// The pull request is already open, so failing to request reviewers is logged rather than failing the job.
+	rr, err := g.pullRequestReviewers()
+	if err != nil {
+		logrus.Warnf("[Github] Unable to determine reviewers for PR %v: %v", pr.GetNumber(), err)
+	} else {
+		g.requestReviewers(orgName, repoName, pr.GetNumber(), rr)
 	}
 
-	rawURL := pr.GetURL()
-	rawURL = strings.ReplaceAll(rawURL, "https://api.github.com/repos/", "https://github.com/")
-	cleanedURL := strings.ReplaceAll(rawURL, "pulls/", "pull/")
-	logrus.Infof("[Github] PR opened with url %v", cleanedURL)
-	return pr.GetURL(), nil
+	prURL := pr.GetHTMLURL()
+	logrus.Infof("[Github] PR opened with url %v", prURL)
+	return prURL, nil
 }
 
-// SetToken sets the GitHub token for the GitHub struct.
+// SetToken sets the GitHub token for the GitHub struct, using the API of a GitHub Enterprise Server instance when
+// one is configured or inferred from the repository URL.
 func (g *GitHub) SetToken() {
-	g.authBasic = &http.BasicAuth{
-		Username: "x-access-token",
-		Password: g.config.VCSPat,
+	g.auth = &tokenAuth{tokenSource: g.tokenSource}
+
+	apiBaseURL, err := githubAPIBaseURL(g.config)
+	if err != nil {
+		logrus.Errorf("[Github] Unable to determine the GitHub API url: %v", err)
+		return
 	}
 
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{
-			TokenType:   "Bearer",
-			AccessToken: g.config.VCSPat,
-		},
-	)
-	tc := oauth2.NewClient(context.Background(), ts)
-	authenticatedClient := github.NewClient(tc)
+	tc := oauth2.NewClient(context.Background(), g.tokenSource)
+	authenticatedClient, err := newGitHubClient(tc, apiBaseURL)
+	if err != nil {
+		logrus.Errorf("[Github] Unable to create the GitHub client: %v", err)
+		return
+	}
 	g.oauth2Client = authenticatedClient
 }
 
+// newGitHubClient returns a GitHub client for github.com when apiBaseURL is empty, and otherwise for the GitHub
+// Enterprise Server instance with that API base URL.
+func newGitHubClient(httpClient *http.Client, apiBaseURL string) (*github.Client, error) {
+	if apiBaseURL == "" {
+		return github.NewClient(httpClient), nil
+	}
+
+	uploadURL := strings.TrimSuffix(strings.TrimSuffix(apiBaseURL, "/"), "/api/v3") + "/api/uploads/"
+	return github.NewEnterpriseClient(apiBaseURL, uploadURL, httpClient)
+}
+
+// githubAPIBaseURL returns the configured VCSBaseURL or, for repositories not hosted on github.com, the GitHub
+// Enterprise Server API of the repository's host. Returns an empty string for github.com.
+func githubAPIBaseURL(config Config) (string, error) {
+	if config.VCSBaseURL != "" {
+		return config.VCSBaseURL, nil
+	}
+
+	repoURL, err := url.Parse(config.VCSRepo)
+	if err != nil {
+		return "", fmt.Errorf("[url.Parse %v]%v", config.VCSRepo, err)
+	}
+
+	if repoURL.Host == "" || repoURL.Hostname() == "github.com" {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%v://%v/api/v3/", repoURL.Scheme, repoURL.Host), nil
+}
+
 // extractOrgAndRepoName pulls out the organization and repository name from the
-// repositories full path.
-func (g *GitHub) extractOrgAndRepoName(repoFullPath string) (string, string, error) {
-	r, err := regexp.Compile(`github.com/(.*?)/(.*?).git$`)
+// repositories full path, hosted either on github.com or a GitHub Enterprise Server instance.
+func extractOrgAndRepoName(repoFullPath string) (string, string, error) {
+	repoURL, err := url.Parse(repoFullPath)
 	if err != nil {
-		return "", "", fmt.Errorf("[extract_org_and_repo_name][error in regexp.Compile]%w", err)
+		return "", "", fmt.Errorf("[extract_org_and_repo_name][error in url.Parse]%w", err)
 	}
 
-	org := r.FindStringSubmatch(repoFullPath)[1]
+	pathComponents := strings.Split(strings.Trim(repoURL.Path, "/"), "/")
+	if len(pathComponents) < 2 {
+		return "", "", fmt.Errorf("[extract_org_and_repo_name] %v is not a repository url", repoFullPath)
+	}
 
-	repo := r.FindStringSubmatch(repoFullPath)[2]
+	org := pathComponents[len(pathComponents)-2]
+	repo := strings.TrimSuffix(pathComponents[len(pathComponents)-1], ".git")
 
 	return org, repo, nil
 }
@@ -0,0 +1,226 @@
+package vcs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// appJWTLifetime is how long a GitHub App JWT is valid for. GitHub allows at most 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+
+	// appJWTClockDrift backdates a GitHub App JWT to allow for clock drift between the job and GitHub.
+	appJWTClockDrift = time.Minute
+
+	// installationTokenRefreshMargin is how long before expiry an installation token is refreshed, so that a token
+	// never expires mid-operation, such as during a push.
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+// appInstallationTokenSource is an oauth2.TokenSource minting GitHub App installation tokens.
+type appInstallationTokenSource struct {
+	// appID is the ID of the GitHub App.
+	appID int64
+
+	// installationID is the ID of the GitHub App's installation. Found from the repository when zero.
+	installationID int64
+
+	// privateKey is the GitHub App's private key, used to sign JWTs.
+	privateKey *rsa.PrivateKey
+
+	// apiBaseURL is the GitHub API base URL, empty for github.com.
+	apiBaseURL string
+
+	// owner and repo are the repository whose installation is used when no installation ID is configured.
+	owner, repo string
+
+	// now returns the current time.
+	now func() time.Time
+
+	// mutex guards installationID.
+	mutex sync.Mutex
+}
+
+// newAppInstallationTokenSource returns a token source minting installation tokens for the GitHub App configured
+// within config, refreshing each token shortly before it expires.
+func newAppInstallationTokenSource(config Config) (oauth2.TokenSource, error) {
+	privateKey, err := parseAppPrivateKey(config.GitHubAppPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("[parseAppPrivateKey]%v", err)
+	}
+
+	owner, repo, err := extractOrgAndRepoName(config.VCSRepo)
+	if err != nil {
+		return nil, fmt.Errorf("[extractOrgAndRepoName]%v", err)
+	}
+
+	apiBaseURL, err := githubAPIBaseURL(config)
+	if err != nil {
+		return nil, fmt.Errorf("[githubAPIBaseURL]%v", err)
+	}
+
+	source := &appInstallationTokenSource{
+		appID:          config.GitHubAppID,
+		installationID: config.GitHubAppInstallationID,
+		privateKey:     privateKey,
+		apiBaseURL:     apiBaseURL,
+		owner:          owner,
+		repo:           repo,
+		now:            time.Now,
+	}
+	return oauth2.ReuseTokenSource(nil, source), nil
+}
+
+// Token mints a new installation token, finding the installation on the repository when no installation ID is
+// configured.
+func (s *appInstallationTokenSource) Token() (*oauth2.Token, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	jwt, err := s.jwt()
+	if err != nil {
+		return nil, fmt.Errorf("[github_app][jwt]%v", err)
+	}
+
+	httpClient := oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{TokenType: "Bearer", AccessToken: jwt}))
+	appClient, err := newGitHubClient(httpClient, s.apiBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("[github_app][newGitHubClient]%v", err)
+	}
+
+	if s.installationID == 0 {
+		installation, _, err := appClient.Apps.FindRepositoryInstallation(context.Background(), s.owner, s.repo)
+		if err != nil {
+			return nil, fmt.Errorf("[github_app][Apps.FindRepositoryInstallation %v/%v]%v", s.owner, s.repo, err)
+		}
+		s.installationID = installation.GetID()
+	}
+
+	installationToken, _, err := appClient.Apps.CreateInstallationToken(context.Background(), s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("[github_app][Apps.CreateInstallationToken %v]%v", s.installationID, err)
+	}
+
+	expiry := installationToken.GetExpiresAt()
+	logrus.Debugf("[Github] Minted GitHub App installation token expiring at %v", expiry)
+
+	return &oauth2.Token{
+		TokenType:   "Bearer",
+		AccessToken: installationToken.GetToken(),
+		Expiry:      expiry.Add(-installationTokenRefreshMargin),
+	}, nil
+}
+
+// jwt returns an RS256 signed JWT authenticating as the GitHub App.
+func (s *appInstallationTokenSource) jwt() (string, error) {
+	now := s.now()
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", fmt.Errorf("[json.Marshal header]%v", err)
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockDrift).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": fmt.Sprintf("%d", s.appID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("[json.Marshal claims]%v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("[rsa.SignPKCS1v15]%v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseAppPrivateKey parses a PEM encoded PKCS #1 or PKCS #8 RSA private key, given either directly or as the path
+// of a file containing it.
+func parseAppPrivateKey(privateKey string) (*rsa.PrivateKey, error) {
+	keyBytes, err := readPEM(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("[readPEM]%v", err)
+	}
+
+	block, _ := pem.Decode(keyBytes)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("[x509.ParsePKCS8PrivateKey]%v", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// readPEM returns PEM encoded content given either directly or as the path of a file containing it.
+func readPEM(content string) ([]byte, error) {
+	if strings.Contains(content, "-----BEGIN") {
+		return []byte(content), nil
+	}
+
+	pemBytes, err := os.ReadFile(content)
+	if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile]%v", err)
+	}
+	return pemBytes, nil
+}
+
+// tokenAuth authenticates git operations over HTTP with the current token of a token source, so that refreshed
+// tokens are used by later operations.
+type tokenAuth struct {
+	tokenSource oauth2.TokenSource
+}
+
+// SetAuth sets the basic authentication header of a git request.
+func (a *tokenAuth) SetAuth(r *http.Request) {
+	token, err := a.tokenSource.Token()
+	if err != nil {
+		logrus.Errorf("[Github] Unable to get a token for git authentication: %v", err)
+		return
+	}
+	r.SetBasicAuth("x-access-token", token.AccessToken)
+}
+
+// Name returns the name of the authentication method.
+func (a *tokenAuth) Name() string {
+	return "http-token-auth"
+}
+
+// String returns a description of the authentication method without the token.
+func (a *tokenAuth) String() string {
+	return fmt.Sprintf("%s - %s:%s", a.Name(), "x-access-token", "*******")
+}
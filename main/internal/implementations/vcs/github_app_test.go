@@ -0,0 +1,199 @@
+package vcs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHubAppServer is a GitHub Enterprise Server API serving the endpoints used to mint installation tokens.
+type fakeGitHubAppServer struct {
+	*httptest.Server
+
+	// tokensMinted is the number of installation tokens minted.
+	tokensMinted int
+
+	// tokenLifetime is how long minted installation tokens are valid for.
+	tokenLifetime time.Duration
+}
+
+func newFakeGitHubAppServer(t *testing.T, publicKey *rsa.PublicKey) *fakeGitHubAppServer {
+	fake := &fakeGitHubAppServer{tokenLifetime: time.Hour}
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := verifyAppJWT(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), publicKey)
+		if err != nil || claims["iss"] != "1234" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/repos/test-org/test-repo/installation":
+			fmt.Fprint(w, `{"id": 42}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/app/installations/42/access_tokens":
+			fake.tokensMinted++
+			expiresAt := time.Now().Add(fake.tokenLifetime).UTC().Format(time.RFC3339)
+			fmt.Fprintf(w, `{"token": "ghs_token%d", "expires_at": "%v"}`, fake.tokensMinted, expiresAt)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+// verifyAppJWT verifies the RS256 signature of a GitHub App JWT and returns its claims.
+func verifyAppJWT(jwt string, publicKey *rsa.PublicKey) (map[string]interface{}, error) {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed jwt")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, err
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	err = json.Unmarshal(claimsJSON, &claims)
+	return claims, err
+}
+
+func generateAppPrivateKey(t *testing.T) (*rsa.PrivateKey, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	return privateKey, string(keyPEM)
+}
+
+func TestAppInstallationTokenSource_Token(t *testing.T) {
+	// Given
+	privateKey, keyPEM := generateAppPrivateKey(t)
+	server := newFakeGitHubAppServer(t, &privateKey.PublicKey)
+
+	tokenSource, err := newAppInstallationTokenSource(Config{
+		VCSRepo:             server.URL + "/test-org/test-repo.git",
+		VCSBaseURL:          server.URL + "/api/v3/",
+		GitHubAppID:         1234,
+		GitHubAppPrivateKey: keyPEM,
+	})
+	require.NoError(t, err)
+
+	// When
+	firstToken, firstErr := tokenSource.Token()
+	secondToken, secondErr := tokenSource.Token()
+
+	// Then
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Equal(t, "ghs_token1", firstToken.AccessToken)
+	assert.Equal(t, "ghs_token1", secondToken.AccessToken)
+	assert.Equal(t, 1, server.tokensMinted)
+}
+
+func TestAppInstallationTokenSource_TokenRefreshedBeforeExpiry(t *testing.T) {
+	// Given
+	privateKey, keyPEM := generateAppPrivateKey(t)
+	server := newFakeGitHubAppServer(t, &privateKey.PublicKey)
+	server.tokenLifetime = installationTokenRefreshMargin - time.Minute
+
+	tokenSource, err := newAppInstallationTokenSource(Config{
+		VCSRepo:             server.URL + "/test-org/test-repo.git",
+		VCSBaseURL:          server.URL + "/api/v3/",
+		GitHubAppID:         1234,
+		GitHubAppPrivateKey: keyPEM,
+	})
+	require.NoError(t, err)
+
+	// When
+	firstToken, firstErr := tokenSource.Token()
+	secondToken, secondErr := tokenSource.Token()
+
+	// Then
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Equal(t, "ghs_token1", firstToken.AccessToken)
+	assert.Equal(t, "ghs_token2", secondToken.AccessToken)
+	assert.Equal(t, 2, server.tokensMinted)
+}
+
+func TestAppInstallationTokenSource_InvalidKey(t *testing.T) {
+	// Given
+	privateKey, _ := generateAppPrivateKey(t)
+	_, otherKeyPEM := generateAppPrivateKey(t)
+	server := newFakeGitHubAppServer(t, &privateKey.PublicKey)
+
+	tokenSource, err := newAppInstallationTokenSource(Config{
+		VCSRepo:             server.URL + "/test-org/test-repo.git",
+		VCSBaseURL:          server.URL + "/api/v3/",
+		GitHubAppID:         1234,
+		GitHubAppPrivateKey: otherKeyPEM,
+	})
+	require.NoError(t, err)
+
+	// When
+	_, err = tokenSource.Token()
+
+	// Then
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, server.tokensMinted)
+}
+
+func TestParseAppPrivateKey_FromFile(t *testing.T) {
+	// Given
+	privateKey, _ := generateAppPrivateKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	keyPath := filepath.Join(t.TempDir(), "github-app.pem")
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), 0o400)
+	require.NoError(t, err)
+
+	// When
+	got, err := parseAppPrivateKey(keyPath)
+
+	// Then
+	assert.Nil(t, err)
+	assert.True(t, privateKey.Equal(got))
+}
+
+func TestParseAppPrivateKey_NotPEM(t *testing.T) {
+	// Given
+	keyPath := filepath.Join(t.TempDir(), "github-app.pem")
+	err := os.WriteFile(keyPath, []byte("not a key"), 0o400)
+	require.NoError(t, err)
+
+	// When
+	_, err = parseAppPrivateKey(keyPath)
+
+	// Then
+	assert.NotNil(t, err)
+}
@@ -8,14 +8,46 @@ import (
 
 func TestExtractOrgAndRepoName(t *testing.T) {
 	// Given
-	github := &GitHub{}
 	input := "https://github.com/dragondrop-cloud-org/dragondrop-cloud-repo1.git"
 
 	// When
-	org, repo, err := github.extractOrgAndRepoName(input)
+	org, repo, err := extractOrgAndRepoName(input)
 
 	// Then
 	assert.Nil(t, err)
 	assert.Equal(t, "dragondrop-cloud-org", org)
 	assert.Equal(t, "dragondrop-cloud-repo1", repo)
 }
+
+func TestExtractOrgAndRepoName_EnterpriseServer(t *testing.T) {
+	// Given
+	input := "https://github.example.com/dragondrop-cloud-org/dragondrop-cloud-repo1.git"
+
+	// When
+	org, repo, err := extractOrgAndRepoName(input)
+
+	// Then
+	assert.Nil(t, err)
+	assert.Equal(t, "dragondrop-cloud-org", org)
+	assert.Equal(t, "dragondrop-cloud-repo1", repo)
+}
+
+func TestGithubAPIBaseURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   string
+	}{
+		{name: "github.com", config: Config{VCSRepo: "https://github.com/org/repo.git"}, want: ""},
+		{name: "inferred enterprise server", config: Config{VCSRepo: "https://github.example.com/org/repo.git"}, want: "https://github.example.com/api/v3/"},
+		{name: "configured base url", config: Config{VCSRepo: "https://git.example.com/org/repo.git", VCSBaseURL: "https://api.example.com/"}, want: "https://api.example.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := githubAPIBaseURL(tt.config)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -55,6 +55,12 @@ func (v *IsolatedVCS) GetID() (string, error) {
 	return "", nil
 }
 
+// GetUsernameByEmail returns the username of the version control system user with the given email address,
+// or an empty string when no user is found.
+func (v *IsolatedVCS) GetUsernameByEmail(_ string) (string, error) {
+	return "", nil
+}
+
 // SetToken sets the token for the VCS
 func (v *IsolatedVCS) SetToken() {
 }
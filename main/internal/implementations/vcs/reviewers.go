@@ -0,0 +1,191 @@
+package vcs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/sirupsen/logrus"
+)
+
+// pullRequestReviewers returns the reviewers to request on the pull request: the CODEOWNERS owners of the files
+// cloud-concierge wrote, falling back to the configured PullReviewers when the repository has no CODEOWNERS file
+// or no owners can be requested, along with the people responsible for the reported changes when requested. The
+// author of the pull request, who GitHub does not allow to review it, is never requested.
+func (g *GitHub) pullRequestReviewers() (github.ReviewersRequest, error) {
+	author := g.authenticatedLogin()
+
+	rr, err := g.ownerReviewers(author)
+	if err != nil {
+		return github.ReviewersRequest{}, err
+	}
+
+	requestedReviewers, err := readRequestedReviewers("state_of_cloud/requested-reviewers.json")
+	if err != nil {
+		return github.ReviewersRequest{}, fmt.Errorf("[readRequestedReviewers]%v", err)
+	}
+
+	for _, reviewer := range requestedReviewers {
+		if !containsFold(rr.Reviewers, reviewer) && !strings.EqualFold(reviewer, author) {
+			rr.Reviewers = append(rr.Reviewers, reviewer)
+		}
+	}
+
+	return rr, nil
+}
+
+// requestReviewers requests reviews on the pull request from the reviewers who are collaborators on the repository,
+// as GitHub rejects requests for anyone else, and mentions the remaining reviewers within a comment instead. Failures
+// are logged rather than returned, as the pull request is already open.
+func (g *GitHub) requestReviewers(orgName string, repoName string, number int, rr github.ReviewersRequest) {
+	ctx := context.Background()
+
+	collaborators := []string{}
+	mentions := []string{}
+	for _, reviewer := range rr.Reviewers {
+		isCollaborator, _, err := g.oauth2Client.Repositories.IsCollaborator(ctx, orgName, repoName, reviewer)
+		if err != nil {
+			logrus.Warnf("[Github] Unable to check whether %v is a collaborator, mentioning them instead: %v", reviewer, err)
+		}
+
+		if isCollaborator {
+			collaborators = append(collaborators, reviewer)
+		} else {
+			mentions = append(mentions, reviewer)
+		}
+	}
+	rr.Reviewers = collaborators
+
+	if len(rr.Reviewers) > 0 || len(rr.TeamReviewers) > 0 {
+		_, _, err := g.oauth2Client.PullRequests.RequestReviewers(ctx, orgName, repoName, number, rr)
+		if err != nil {
+			logrus.Warnf("[Github] Unable to request reviewers %v and teams %v, mentioning them instead: %v", rr.Reviewers, rr.TeamReviewers, err)
+
+			mentions = append(mentions, rr.Reviewers...)
+			for _, team := range rr.TeamReviewers {
+				mentions = append(mentions, fmt.Sprintf("%v/%v", orgName, team))
+			}
+		}
+	}
+
+	if len(mentions) == 0 {
+		return
+	}
+
+	body := fmt.Sprintf("@%v: cloud-concierge could not request your review, please review the changes reported within this pull request.", strings.Join(mentions, ", @"))
+	_, _, err := g.oauth2Client.Issues.CreateComment(ctx, orgName, repoName, number, &github.IssueComment{Body: &body})
+	if err != nil {
+		logrus.Warnf("[Github] Unable to mention reviewers %v on PR %v: %v", mentions, number, err)
+	}
+}
+
+// ownerReviewers returns the CODEOWNERS owners of the files cloud-concierge wrote, falling back to the configured
+// PullReviewers, excluding the pull request's author.
+func (g *GitHub) ownerReviewers(author string) (github.ReviewersRequest, error) {
+	owners, err := g.codeOwners()
+	if err != nil {
+		return github.ReviewersRequest{}, err
+	}
+
+	rr := codeOwnersReviewers(owners, author)
+	if len(rr.Reviewers) > 0 || len(rr.TeamReviewers) > 0 {
+		logrus.Debugf("[Github] Requesting CODEOWNERS reviewers %v and teams %v", rr.Reviewers, rr.TeamReviewers)
+		return rr, nil
+	}
+
+	if len(g.config.PullReviewers) == 0 || g.config.PullReviewers[0] == "NoReviewer" {
+		return github.ReviewersRequest{}, nil
+	}
+
+	rr = github.ReviewersRequest{}
+	for _, reviewer := range g.config.PullReviewers {
+		if !strings.EqualFold(reviewer, author) {
+			rr.Reviewers = append(rr.Reviewers, reviewer)
+		}
+	}
+	return rr, nil
+}
+
+// authenticatedLogin returns the login of the user the pull request is opened as. Returns an empty string when it
+// cannot be determined, such as when authenticated as a GitHub App installation.
+func (g *GitHub) authenticatedLogin() string {
+	if g.oauth2Client == nil {
+		return ""
+	}
+
+	user, _, err := g.oauth2Client.Users.Get(context.Background(), "")
+	if err != nil {
+		logrus.Debugf("[Github] Unable to determine the authenticated user: %v", err)
+		return ""
+	}
+	return user.GetLogin()
+}
+
+// readRequestedReviewers reads the users requested to review the pull request because they created unmanaged
+// resources or caused drift within its report. Returns no users when the file does not exist.
+func readRequestedReviewers(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("[os.ReadFile %v]%v", path, err)
+	}
+
+	var reviewers []string
+	err = json.Unmarshal(content, &reviewers)
+	if err != nil {
+		return nil, fmt.Errorf("[json.Unmarshal %v]%v", path, err)
+	}
+	return reviewers, nil
+}
+
+// containsFold returns whether values contains value, ignoring case as GitHub logins do.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloudConciergeFiles returns the files written by cloud-concierge: those within a workspace's cloud-concierge
+// directory and new resource definitions.
+func cloudConciergeFiles(files []string) []string {
+	written := []string{}
+	for _, file := range files {
+		base := path.Base(file)
+		if base == "new-resources.tf" || base == "new-resources.tf.json" || strings.Contains("/"+file, "/cloud-concierge/") {
+			written = append(written, file)
+		}
+	}
+	return written
+}
+
+// codeOwnersReviewers converts CODEOWNERS owners into a GitHub reviewers request. `@org/team` and Bitbucket
+// `@@group` owners are requested as teams. Email addresses and the pull request's author cannot be requested and are
+// skipped.
+func codeOwnersReviewers(owners []string, author string) github.ReviewersRequest {
+	rr := github.ReviewersRequest{}
+	for _, owner := range owners {
+		switch {
+		case strings.HasPrefix(owner, "@@"):
+			rr.TeamReviewers = append(rr.TeamReviewers, strings.TrimPrefix(owner, "@@"))
+		case strings.HasPrefix(owner, "@") && strings.Contains(owner, "/"):
+			rr.TeamReviewers = append(rr.TeamReviewers, owner[strings.LastIndex(owner, "/")+1:])
+		case strings.HasPrefix(owner, "@") && strings.EqualFold(strings.TrimPrefix(owner, "@"), author):
+			logrus.Debugf("[Github] Skipping CODEOWNERS owner %v, who authors the pull request", owner)
+		case strings.HasPrefix(owner, "@"):
+			rr.Reviewers = append(rr.Reviewers, strings.TrimPrefix(owner, "@"))
+		default:
+			logrus.Debugf("[Github] Skipping CODEOWNERS owner %v, which cannot be requested as a reviewer", owner)
+		}
+	}
+	return rr
+}
@@ -0,0 +1,188 @@
+package vcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudConciergeFiles(t *testing.T) {
+	// Given
+	files := []string{
+		"dev/new-resources.tf",
+		"dev/main.tf",
+		"prod/new-resources.tf.json",
+		"prod/cloud-concierge/imports/abc_imports.tf",
+		"cloud-concierge/tfmigrate/.tfmigrate.hcl",
+	}
+
+	// When
+	written := cloudConciergeFiles(files)
+
+	// Then
+	assert.Equal(t, []string{
+		"dev/new-resources.tf",
+		"prod/new-resources.tf.json",
+		"prod/cloud-concierge/imports/abc_imports.tf",
+		"cloud-concierge/tfmigrate/.tfmigrate.hcl",
+	}, written)
+}
+
+func TestCodeOwnersReviewers(t *testing.T) {
+	// Given
+	owners := []string{"@@InfraTeam", "@alice", "@org/network", "@group/subgroup/team", "bob@example.com", "@Concierge-Bot"}
+
+	// When
+	rr := codeOwnersReviewers(owners, "concierge-bot")
+
+	// Then
+	assert.Equal(t, github.ReviewersRequest{
+		Reviewers:     []string{"alice"},
+		TeamReviewers: []string{"InfraTeam", "network", "team"},
+	}, rr)
+}
+
+func TestPullRequestReviewers_NoCodeOwners(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	g := &GitHub{config: Config{PullReviewers: []string{"reviewer1", "reviewer2"}}}
+
+	// When
+	rr, err := g.pullRequestReviewers()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, github.ReviewersRequest{Reviewers: []string{"reviewer1", "reviewer2"}}, rr)
+}
+
+func TestPullRequestReviewers_RequestedReviewers(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	require.NoError(t, os.MkdirAll("state_of_cloud", 0o755))
+	require.NoError(t, os.WriteFile("state_of_cloud/requested-reviewers.json", []byte(`["Reviewer1", "jane-gh"]`), 0o400))
+
+	g := &GitHub{config: Config{PullReviewers: []string{"reviewer1"}}}
+
+	// When
+	rr, err := g.pullRequestReviewers()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, github.ReviewersRequest{Reviewers: []string{"reviewer1", "jane-gh"}}, rr)
+}
+
+// fakeReviewersServer is a GitHub Enterprise Server API serving the endpoints used to request reviewers.
+type fakeReviewersServer struct {
+	*httptest.Server
+
+	// requestedReviewers is the reviewers request received, if any.
+	requestedReviewers *github.ReviewersRequest
+
+	// comments are the bodies of the comments created on the pull request.
+	comments []string
+}
+
+func newFakeReviewersServer(t *testing.T, collaborators map[string]bool, requestReviewersStatus int) *fakeReviewersServer {
+	fake := &fakeReviewersServer{}
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v3/user":
+			fmt.Fprint(w, `{"login": "concierge-bot"}`)
+		case r.Method == http.MethodGet && len(r.URL.Path) > len("/api/v3/repos/org/repo/collaborators/"):
+			if collaborators[r.URL.Path[len("/api/v3/repos/org/repo/collaborators/"):]] {
+				w.WriteHeader(http.StatusNoContent)
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/org/repo/pulls/7/requested_reviewers":
+			fake.requestedReviewers = &github.ReviewersRequest{}
+			require.NoError(t, json.Unmarshal(body, fake.requestedReviewers))
+			w.WriteHeader(requestReviewersStatus)
+			fmt.Fprint(w, `{"number": 7}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v3/repos/org/repo/issues/7/comments":
+			comment := github.IssueComment{}
+			require.NoError(t, json.Unmarshal(body, &comment))
+			fake.comments = append(fake.comments, comment.GetBody())
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": 1}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(fake.Close)
+
+	return fake
+}
+
+func newFakeReviewersGitHub(t *testing.T, fake *fakeReviewersServer) *GitHub {
+	client, err := newGitHubClient(fake.Client(), fake.URL+"/api/v3/")
+	require.NoError(t, err)
+	return &GitHub{oauth2Client: client}
+}
+
+func TestRequestReviewers_NonCollaborators(t *testing.T) {
+	// Given
+	fake := newFakeReviewersServer(t, map[string]bool{"alice": true}, http.StatusCreated)
+	g := newFakeReviewersGitHub(t, fake)
+
+	// When
+	g.requestReviewers("org", "repo", 7, github.ReviewersRequest{Reviewers: []string{"alice", "jane-gh"}, TeamReviewers: []string{"network"}})
+
+	// Then
+	require.NotNil(t, fake.requestedReviewers)
+	assert.Equal(t, github.ReviewersRequest{Reviewers: []string{"alice"}, TeamReviewers: []string{"network"}}, *fake.requestedReviewers)
+	assert.Equal(t, []string{"@jane-gh: cloud-concierge could not request your review, please review the changes reported within this pull request."}, fake.comments)
+}
+
+func TestRequestReviewers_RequestRejected(t *testing.T) {
+	// Given
+	fake := newFakeReviewersServer(t, map[string]bool{"alice": true}, http.StatusUnprocessableEntity)
+	g := newFakeReviewersGitHub(t, fake)
+
+	// When
+	g.requestReviewers("org", "repo", 7, github.ReviewersRequest{Reviewers: []string{"alice"}, TeamReviewers: []string{"network"}})
+
+	// Then
+	assert.Equal(t, []string{"@alice, @org/network: cloud-concierge could not request your review, please review the changes reported within this pull request."}, fake.comments)
+}
+
+func TestPullRequestReviewers_ExcludesAuthor(t *testing.T) {
+	// Given
+	workingDirectory, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { require.NoError(t, os.Chdir(workingDirectory)) }()
+
+	require.NoError(t, os.MkdirAll("state_of_cloud", 0o755))
+	require.NoError(t, os.WriteFile("state_of_cloud/requested-reviewers.json", []byte(`["concierge-bot", "jane-gh"]`), 0o400))
+
+	g := newFakeReviewersGitHub(t, newFakeReviewersServer(t, nil, http.StatusCreated))
+	g.config = Config{PullReviewers: []string{"Concierge-Bot", "reviewer1"}}
+
+	// When
+	rr, err := g.pullRequestReviewers()
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, github.ReviewersRequest{Reviewers: []string{"reviewer1", "jane-gh"}}, rr)
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// IdentityMapper is an interface for resolving the cloud actors responsible for changes outside of Terraform
+// control to version control system users.
+type IdentityMapper interface {
+	// Execute resolves each identified cloud actor to a version control system username, saving the mapping
+	// for use within the report and pull request.
+	Execute(ctx context.Context) error
+}
+
+// IdentityMapperMock implements the IdentityMapper interface for testing purposes.
+type IdentityMapperMock struct {
+	mock.Mock
+}
+
+// Execute resolves each identified cloud actor to a version control system username, saving the mapping
+// for use within the report and pull request.
+func (m *IdentityMapperMock) Execute(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
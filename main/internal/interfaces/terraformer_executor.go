@@ -11,7 +11,7 @@ import (
 type TerraformerExecutor interface {
 	// Execute runs the workflow needed to capture the current state of an
 	// external cloud environment via the terraformer package.
-	Execute(ctx context.Context) error
+	Execute(ctx context.Context, workspaceToDirectory map[string]string) error
 }
 
 // TerraformerExecutorMock is a struct that implements the TerraformerExecutor interface for
@@ -22,7 +22,7 @@ type TerraformerExecutorMock struct {
 
 // Execute runs the workflow needed to capture the current state of an
 // external cloud environment via the terraformer package.
-func (m *TerraformerExecutorMock) Execute(_ context.Context) error {
+func (m *TerraformerExecutorMock) Execute(_ context.Context, _ map[string]string) error {
 	args := m.Called()
 	return args.Error(0)
 }
@@ -28,6 +28,10 @@ type VCS interface {
 	// for a dragondrop built commit/pull request
 	GetID() (string, error)
 
+	// GetUsernameByEmail returns the username of the version control system user with the given email address,
+	// or an empty string when no user is found.
+	GetUsernameByEmail(email string) (string, error)
+
 	SetToken()
 }
 
@@ -80,6 +84,13 @@ func (m *VCSMock) GetID() (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+// GetUsernameByEmail returns the username of the version control system user with the given email address,
+// or an empty string when no user is found.
+func (m *VCSMock) GetUsernameByEmail(email string) (string, error) {
+	args := m.Called(email)
+	return args.String(0), args.Error(1)
+}
+
 // SetToken sets the token for the VCS
 func (m *VCSMock) SetToken() {
 	m.Called()
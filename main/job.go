@@ -11,10 +11,12 @@ import (
 
 	costEstimation "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/cost_estimation"
 	identifyCloudActors "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors"
+	identityMapping "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identity_mapping"
 	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
 	resourcesWriter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_writer"
 	terraformImportMigrationGenerator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_import_migration_generator"
 	terraformManagedResourcesDriftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformSecurity "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_security"
 	terraformWorkspace "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_workspace"
 	terraformerExecutor "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraformer_executor"
@@ -22,6 +24,9 @@ import (
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
 
+// ErrDriftSeverityThresholdExceeded is returned when drift at or above the configured FailOnDriftSeverity is identified.
+var ErrDriftSeverityThresholdExceeded = errors.New("drift severity threshold exceeded")
+
 // Job is an instance of a runnable dragondrop job.
 type Job struct {
 	// vcs is the implementation of interfaces.VCS for interacting with a remote version control system
@@ -55,6 +60,10 @@ type Job struct {
 	// which cloud actor made resource changes outside of Terraform control.
 	identifyCloudActors interfaces.IdentifyCloudActors
 
+	// identityMapper is the implementation of interfaces.IdentityMapper for resolving cloud actors to
+	// version control system users who are notified on the pull request.
+	identityMapper interfaces.IdentityMapper
+
 	// costEstimator is the implementation of interfaces.CostEstimation for calculating monthly cost
 	// estimates of identified cloud resources.
 	costEstimator interfaces.CostEstimation
@@ -94,7 +103,7 @@ func (j *Job) Run(ctx context.Context) error {
 		return fmt.Errorf("[run_job][error downloading workspace state][%w]", err)
 	}
 
-	err = j.terraformerExecutor.Execute(ctx)
+	err = j.terraformerExecutor.Execute(ctx, workspaceToDirectory)
 	if err != nil {
 		return fmt.Errorf("[run_job][error setting up terraformer executor][%w]", err)
 	}
@@ -129,6 +138,11 @@ func (j *Job) Run(ctx context.Context) error {
 		return fmt.Errorf("[run_job][error identifying cloud actors]%w", err)
 	}
 
+	err = j.identityMapper.Execute(ctx)
+	if err != nil {
+		return fmt.Errorf("[run_job][error mapping cloud actors to vcs users]%w", err)
+	}
+
 	err = j.costEstimator.Execute()
 	if err != nil {
 		return fmt.Errorf("[run_job][error estimating cost for identified resources]%w", err)
@@ -145,6 +159,23 @@ func (j *Job) Run(ctx context.Context) error {
 		return fmt.Errorf("[run_job][error writing resources on vcs][%w]", err)
 	}
 
+	return j.checkDriftSeverityThreshold()
+}
+
+// checkDriftSeverityThreshold returns an error if drift at or above the configured FailOnDriftSeverity was identified.
+func (j *Job) checkDriftSeverityThreshold() error {
+	if j.config.FailOnDriftSeverity == "" {
+		return nil
+	}
+
+	count, err := driftDetector.CountDriftAtOrAboveSeverity(j.config.FailOnDriftSeverity)
+	if err != nil {
+		return fmt.Errorf("[run_job][error counting drift by severity]%w", err)
+	}
+
+	if count > 0 {
+		return fmt.Errorf("[run_job]%w: %v drifted attributes at or above '%v' severity", ErrDriftSeverityThresholdExceeded, count, j.config.FailOnDriftSeverity)
+	}
 	return nil
 }
 
@@ -204,7 +235,11 @@ func InitializeJobDependencies(ctx context.Context, env string) (*Job, error) {
 	if err != nil {
 		return nil, err
 	}
-	writer, err := (&resourcesWriter.Factory{}).Instantiate(env, vcsInstance, inferredData.Provider, jobConfig.getHCLCreateConfig(), jobConfig.JobName)
+	identityMapper, err := (&identityMapping.Factory{}).Instantiate(ctx, env, vcsInstance, jobConfig.getIdentityMappingConfig())
+	if err != nil {
+		return nil, err
+	}
+	writer, err := (&resourcesWriter.Factory{}).Instantiate(env, vcsInstance, inferredData.Provider, jobConfig.getHCLCreateConfig(), jobConfig.getResourcesWriterConfig())
 	if err != nil {
 		return nil, err
 	}
@@ -227,6 +262,7 @@ func InitializeJobDependencies(ctx context.Context, env string) (*Job, error) {
 		nlpEngine:                         nlpEngineRequestor,
 		costEstimator:                     costEstimator,
 		identifyCloudActors:               identifier,
+		identityMapper:                    identityMapper,
 		driftDetector:                     driftDetector,
 		config:                            jobConfig,
 		terraformSecurity:                 tfSec,
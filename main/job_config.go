@@ -10,6 +10,8 @@ import (
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	costEstimation "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/cost_estimation"
 	identifyCloudActors "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors"
+	identityMapping "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identity_mapping"
+	resourcesWriter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_writer"
 	terraformImportMigrationGenerator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_import_migration_generator"
 	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
@@ -23,6 +25,18 @@ type JobConfig struct {
 	// IsManagedDriftOnly represents the option for the user to only scan drifted resources and not new resources
 	IsManagedDriftOnly bool `default:"false"`
 
+	// DriftDetectionEngine is the engine used to detect drift in managed resources. Either "terraformer", which compares
+	// terraformer output against remote state, or "plan-refresh-only", which runs `terraform plan -refresh-only` within each workspace.
+	DriftDetectionEngine string `default:"terraformer"`
+
+	// DriftSeverityOverrides is a JSON list of rules, each with a "resourceType" and "attribute" regular expression and a
+	// "severity", that take precedence over the built-in drift severity rules.
+	DriftSeverityOverrides driftDetector.SeverityRules
+
+	// FailOnDriftSeverity causes the job to exit with an error, after the pull request is opened, when drift at or above
+	// this severity is identified. One of "critical", "high", "medium" or "low". Empty disables the check.
+	FailOnDriftSeverity driftDetector.Severity
+
 	// CloudCredential is a cloud credential that is used to authenticate with a cloud provider. Credential should
 	// only require read-only access.
 	CloudCredential terraformValueObjects.Credential `required:"false"`
@@ -47,8 +61,54 @@ type JobConfig struct {
 	// to the right state files.
 	NLPEndpoint string `default:"https://us-east4-dragondrop-prod.cloudfunctions.net/nlpengine-endpoint-prod"`
 
-	// TerraformVersion is the version of Terraform used.
-	TerraformVersion string `required:"true"`
+	// TerraformVersion is the Terraform version, or version constraint, generated code targets. Each workspace uses it
+	// when it satisfies the workspace's required_version, and otherwise the lowest version required_version allows.
+	// When empty, the version inferred from the workspaces' required_version pins takes its place.
+	TerraformVersion string
+
+	// ResourceNameTemplate is a Go template used to name resources imported into Terraform control, for example
+	// `{{.ShortType}}_{{index .Tags "Name"}}`. Available fields are Type, ShortType, Name, Tags, Region, Division
+	// and CloudID. Rendered names are converted to snake case. Empty keeps the terraformer generated names.
+	ResourceNameTemplate string
+
+	// ModulePatterns is a JSON list of patterns, each grouping a "primaryResourceType" with related "members", used to
+	// write new resources as calls to an existing module "source", or to a new local module when "source" is empty.
+	ModulePatterns hclcreate.ModulePatterns
+
+	// ParameterizeResources extracts literals repeated across new resources, such as the project, region, account id
+	// and common tags, into variables and locals, reusing those already defined within the workspace.
+	ParameterizeResources bool `default:"false"`
+
+	// DriftRemediation is how drift in managed resources is handled. Either "report", which only reports drift, or
+	// "accept-cloud-state", which also rewrites drifted attributes within the workspace's configuration to the cloud value.
+	DriftRemediation string `default:"report"`
+
+	// OrphanedResourceBlocks is how the resource blocks of deleted cloud resources, which are removed from state, are
+	// handled within the workspace's configuration. One of "keep", "comment" or "delete".
+	OrphanedResourceBlocks string `default:"comment"`
+
+	// CodeGeneration is how the resource blocks of new resources are generated. Either "terraformer", which uses
+	// terraformer's HCL, or "generate-config-out", which uses `terraform plan -generate-config-out` within workspaces
+	// using Terraform 1.5.0 or higher.
+	CodeGeneration string `default:"terraformer"`
+
+	// ValidateGeneratedCode is how generated code is validated within a sandboxed copy of each touched workspace before
+	// the pull request is opened. One of "none", "validate", which runs `terraform validate`, or "plan", which also
+	// runs `terraform plan` against a copy of the workspace's state. New resources failing either are quarantined,
+	// while planned changes to other resources are only reported.
+	ValidateGeneratedCode string `default:"none"`
+
+	// Runtime is the command line tool used to run Terraform commands and whose registry provider addresses are
+	// used. Either "terraform" or "opentofu", which runs `tofu` instead.
+	Runtime string `default:"terraform"`
+
+	// OutputFormat is the syntax in which new resources, import blocks and removed blocks are written. Either "hcl",
+	// Terraform's native syntax, or "json", which writes .tf.json files.
+	OutputFormat string `default:"hcl"`
+
+	// PullRequestStrategy is how changes are split into pull requests. One of "single", a single pull request, or
+	// "workspace" or "category", a pull request per workspace or per resource category such as networking or storage.
+	PullRequestStrategy string `default:"single"`
 
 	// StateBackend is the name of the backend used for storing State.
 	StateBackend string `required:"true"`
@@ -62,19 +122,87 @@ type JobConfig struct {
 	// WorkspaceDirectories is a slice of directories that contains terraform workspaces within the user repo.
 	WorkspaceDirectories terraformWorkspace.WorkspaceDirectoriesDecoder `required:"true"`
 
-	// Provider is a map between a cloud provider and the version for that provider.
+	// Provider is a map between a cloud provider and the version for that provider. The version is used only when
+	// no workspace pins one within required_providers or .terraform.lock.hcl.
 	Provider map[terraformValueObjects.Provider]string `required:"true"`
 
 	// VCSRepo is the full path of the repo containing a customer's infrastructure specification.
-	// At the moment, must be a valid GitHub repository URL.
+	// Must be a valid GitHub or GitHub Enterprise Server repository URL, or an SSH URL of a repository on any git server.
 	VCSRepo string `required:"true"`
 
-	// VCSPat is the personal access token for the VCS where a Pull Request should be output.
-	VCSPat string `required:"true"`
+	// VCSPat is the personal access token for the VCS where a Pull Request should be output. Required unless
+	// authenticating as a GitHub App or with an SSH key.
+	VCSPat string
+
+	// VCSBaseURL is the API base URL of a GitHub Enterprise Server instance, such as https://github.example.com/api/v3/.
+	// When empty, it is inferred from the VCSRepo's host.
+	VCSBaseURL string
+
+	// GitHubAppID is the ID of the GitHub App to authenticate as, minting installation tokens instead of using VCSPat.
+	GitHubAppID int64
+
+	// GitHubAppInstallationID is the ID of the GitHub App's installation. When empty, the installation on VCSRepo is used.
+	GitHubAppInstallationID int64
+
+	// GitHubAppPrivateKey is the GitHub App's PEM encoded private key, or the path of a file containing it.
+	GitHubAppPrivateKey string
 
-	// PullReviewers is the name of the pull request reviewer who will be tagged on the opened pull request.
+	// VCSSSHKey is the PEM encoded private key, or the path of a file containing it, used to access a VCSRepo given
+	// as an SSH URL.
+	VCSSSHKey string
+
+	// VCSSSHKeyPassphrase is the passphrase of VCSSSHKey, if it is encrypted.
+	VCSSSHKeyPassphrase string
+
+	// VCSSSHKnownHosts are the known_hosts entries, or the path of a known_hosts file, used to verify the SSH host key
+	// of VCSRepo. When empty, the files within SSH_KNOWN_HOSTS or ~/.ssh/known_hosts are used.
+	VCSSSHKnownHosts string
+
+	// ReviewRequestWebhook is a URL to which a review request for the pushed branch is posted as JSON, when VCSRepo
+	// is an SSH URL.
+	ReviewRequestWebhook string
+
+	// ReviewRequestCommand is a shell command run within the repository to open a review request for the pushed
+	// branch, when VCSRepo is an SSH URL.
+	ReviewRequestCommand string
+
+	// CommitAuthorName and CommitAuthorEmail identify the author of commits, dragondrop.cloud by default.
+	CommitAuthorName  string
+	CommitAuthorEmail string
+
+	// CommitterName and CommitterEmail identify the committer of commits, the author by default.
+	CommitterName  string
+	CommitterEmail string
+
+	// CommitMessageTemplate is a Go template rendering commit messages, for example
+	// "chore(drift): {{.JobName}} ({{.NewResources}} new, {{.DriftedResources}} drifted, {{.DeletedResources}} deleted)".
+	// Fields are JobName, NewResources, DriftedResources, DeletedResources and RunID.
+	CommitMessageTemplate string
+
+	// CommitSigningKey is the armored PGP or OpenSSH private key, or the path of a file containing it, used to sign
+	// commits. Commits are unsigned when empty.
+	CommitSigningKey string
+
+	// CommitSigningKeyPassphrase is the passphrase of CommitSigningKey, if it is encrypted.
+	CommitSigningKeyPassphrase string
+
+	// PullReviewers is the name of the pull request reviewer who will be tagged on the opened pull request. Used
+	// when the repository has no CODEOWNERS file, or it names no owners of the files cloud-concierge writes.
 	PullReviewers []string `default:"NoReviewer"`
 
+	// CloudActorNotification is how the people who created resources outside of Terraform control or caused drift
+	// are notified. One of "none", "mention", which @-mentions them within the pull request, or "review", which
+	// requests their review of the pull request.
+	CloudActorNotification string `default:"none"`
+
+	// IdentityMappingFile is the path, relative to the repository root, of a JSON object mapping cloud principals or
+	// email addresses to VCS usernames.
+	IdentityMappingFile string
+
+	// EmailIdentityMatching resolves cloud principals containing an email address, such as an assumed role's session
+	// name, to the VCS user with that email address when not within the IdentityMappingFile.
+	EmailIdentityMatching bool `default:"true"`
+
 	// ResourcesWhiteList represents the list of resource names that will be exclusively considered for inclusion in the import statement.
 	ResourcesWhiteList terraformValueObjects.ResourceNameList
 
@@ -97,15 +225,116 @@ func validateJobConfig(config JobConfig) error {
 			return fmt.Errorf("[terraform cloud token is required when using terraform cloud as state backend]")
 		}
 	}
+
+	if isSSHRepoURL(config.VCSRepo) {
+		if config.VCSSSHKey == "" {
+			return fmt.Errorf("[vcs ssh key is required for the ssh repository url %v]", config.VCSRepo)
+		}
+	} else if config.GitHubAppID != 0 {
+		if config.GitHubAppPrivateKey == "" {
+			return fmt.Errorf("[github app private key is required when authenticating as a github app]")
+		}
+	} else if config.VCSPat == "" {
+		return fmt.Errorf("[vcs pat is required unless authenticating as a github app]")
+	}
+
+	switch config.DriftDetectionEngine {
+	case driftDetector.TerraformerEngine, driftDetector.PlanRefreshOnlyEngine:
+	default:
+		return fmt.Errorf("[drift detection engine must be one of '%v' or '%v', got '%v']", driftDetector.TerraformerEngine, driftDetector.PlanRefreshOnlyEngine, config.DriftDetectionEngine)
+	}
+
+	switch config.DriftRemediation {
+	case hclcreate.ReportDriftRemediation, hclcreate.AcceptCloudStateRemediation:
+	default:
+		return fmt.Errorf("[drift remediation must be one of '%v' or '%v', got '%v']", hclcreate.ReportDriftRemediation, hclcreate.AcceptCloudStateRemediation, config.DriftRemediation)
+	}
+
+	switch config.OrphanedResourceBlocks {
+	case hclcreate.KeepOrphanedResourceBlocks, hclcreate.CommentOrphanedResourceBlocks, hclcreate.DeleteOrphanedResourceBlocks:
+	default:
+		return fmt.Errorf("[orphaned resource blocks must be one of '%v', '%v' or '%v', got '%v']", hclcreate.KeepOrphanedResourceBlocks, hclcreate.CommentOrphanedResourceBlocks, hclcreate.DeleteOrphanedResourceBlocks, config.OrphanedResourceBlocks)
+	}
+
+	switch config.CodeGeneration {
+	case hclcreate.TerraformerCodeGeneration, hclcreate.GenerateConfigOutCodeGeneration:
+	default:
+		return fmt.Errorf("[code generation must be one of '%v' or '%v', got '%v']", hclcreate.TerraformerCodeGeneration, hclcreate.GenerateConfigOutCodeGeneration, config.CodeGeneration)
+	}
+
+	switch config.ValidateGeneratedCode {
+	case hclcreate.NoCodeValidation, hclcreate.ValidateCodeValidation, hclcreate.PlanCodeValidation:
+	default:
+		return fmt.Errorf("[validate generated code must be one of '%v', '%v' or '%v', got '%v']", hclcreate.NoCodeValidation, hclcreate.ValidateCodeValidation, hclcreate.PlanCodeValidation, config.ValidateGeneratedCode)
+	}
+
+	switch terraformValueObjects.Runtime(config.Runtime) {
+	case terraformValueObjects.TerraformRuntime, terraformValueObjects.OpenTofuRuntime:
+	default:
+		return fmt.Errorf("[runtime must be one of '%v' or '%v', got '%v']", terraformValueObjects.TerraformRuntime, terraformValueObjects.OpenTofuRuntime, config.Runtime)
+	}
+
+	switch config.OutputFormat {
+	case hclcreate.HCLOutputFormat, hclcreate.JSONOutputFormat:
+	default:
+		return fmt.Errorf("[output format must be one of '%v' or '%v', got '%v']", hclcreate.HCLOutputFormat, hclcreate.JSONOutputFormat, config.OutputFormat)
+	}
+
+	switch config.CloudActorNotification {
+	case identityMapping.NoNotification, identityMapping.MentionNotification, identityMapping.ReviewNotification:
+	default:
+		return fmt.Errorf("[cloud actor notification must be one of '%v', '%v' or '%v', got '%v']", identityMapping.NoNotification, identityMapping.MentionNotification, identityMapping.ReviewNotification, config.CloudActorNotification)
+	}
+
+	switch config.PullRequestStrategy {
+	case resourcesWriter.SinglePullRequestStrategy, resourcesWriter.WorkspacePullRequestStrategy, resourcesWriter.CategoryPullRequestStrategy:
+	default:
+		return fmt.Errorf("[pull request strategy must be one of '%v', '%v' or '%v', got '%v']", resourcesWriter.SinglePullRequestStrategy, resourcesWriter.WorkspacePullRequestStrategy, resourcesWriter.CategoryPullRequestStrategy, config.PullRequestStrategy)
+	}
+
+	_, err := hclcreate.ParseResourceNameTemplate(config.ResourceNameTemplate)
+	if err != nil {
+		return fmt.Errorf("[resource name template is invalid: %v]", err)
+	}
+
+	_, err = vcs.ParseCommitMessageTemplate(config.CommitMessageTemplate)
+	if err != nil {
+		return fmt.Errorf("[commit message template is invalid: %v]", err)
+	}
 	return nil
 }
 
 func (c JobConfig) getVCSConfig() vcs.Config {
 	return vcs.Config{
-		VCSRepo:       c.VCSRepo,
-		VCSPat:        c.VCSPat,
-		PullReviewers: c.PullReviewers,
+		VCSRepo:                    c.VCSRepo,
+		VCSPat:                     c.VCSPat,
+		VCSBaseURL:                 c.VCSBaseURL,
+		GitHubAppID:                c.GitHubAppID,
+		GitHubAppInstallationID:    c.GitHubAppInstallationID,
+		GitHubAppPrivateKey:        c.GitHubAppPrivateKey,
+		VCSSSHKey:                  c.VCSSSHKey,
+		VCSSSHKeyPassphrase:        c.VCSSSHKeyPassphrase,
+		VCSSSHKnownHosts:           c.VCSSSHKnownHosts,
+		ReviewRequestWebhook:       c.ReviewRequestWebhook,
+		ReviewRequestCommand:       c.ReviewRequestCommand,
+		CommitAuthorName:           c.CommitAuthorName,
+		CommitAuthorEmail:          c.CommitAuthorEmail,
+		CommitterName:              c.CommitterName,
+		CommitterEmail:             c.CommitterEmail,
+		CommitMessageTemplate:      c.CommitMessageTemplate,
+		CommitSigningKey:           c.CommitSigningKey,
+		CommitSigningKeyPassphrase: c.CommitSigningKeyPassphrase,
+		RunID:                      runID(c.JobID),
+		PullReviewers:              c.PullReviewers,
+	}
+}
+
+// runID returns the job's ID, identifying the run within commit messages, or an empty string when no job ID is set.
+func runID(jobID string) string {
+	if jobID == "empty" {
+		return ""
 	}
+	return jobID
 }
 
 func (c JobConfig) getTerraformWorkspaceConfig() terraformWorkspace.TfStackConfig {
@@ -123,6 +352,31 @@ func (c JobConfig) getHCLCreateConfig() hclcreate.Config {
 	return hclcreate.Config{
 		MigrationHistoryStorage: c.MigrationHistoryStorage,
 		TerraformVersion:        c.TerraformVersion,
+		ResourceNameTemplate:    c.ResourceNameTemplate,
+		Division:                string(c.Division),
+		ModulePatterns:          c.ModulePatterns,
+		ParameterizeResources:   c.ParameterizeResources,
+		DriftRemediation:        c.DriftRemediation,
+		OrphanedResourceBlocks:  c.OrphanedResourceBlocks,
+		CodeGeneration:          c.CodeGeneration,
+		ValidateGeneratedCode:   c.ValidateGeneratedCode,
+		Runtime:                 terraformValueObjects.Runtime(c.Runtime),
+		OutputFormat:            c.OutputFormat,
+	}
+}
+
+func (c JobConfig) getIdentityMappingConfig() identityMapping.Config {
+	return identityMapping.Config{
+		IdentityMappingFile: c.IdentityMappingFile,
+		EmailMatching:       c.EmailIdentityMatching,
+		Notification:        c.CloudActorNotification,
+	}
+}
+
+func (c JobConfig) getResourcesWriterConfig() resourcesWriter.Config {
+	return resourcesWriter.Config{
+		JobName:             c.JobName,
+		PullRequestStrategy: c.PullRequestStrategy,
 	}
 }
 
@@ -133,6 +387,7 @@ func (c JobConfig) getTerraformerConfig() terraformerCli.TerraformerExecutorConf
 		Provider:         c.Provider,
 		TerraformVersion: terraformValueObjects.Version(c.TerraformVersion),
 		CloudRegions:     c.CloudRegions,
+		Runtime:          terraformValueObjects.Runtime(c.Runtime),
 	}
 }
 
@@ -140,6 +395,7 @@ func (c JobConfig) getTerraformerCLIConfig() terraformerCli.Config {
 	return terraformerCli.Config{
 		ResourcesWhiteList: c.ResourcesWhiteList,
 		ResourcesBlackList: c.ResourcesBlackList,
+		Runtime:            terraformValueObjects.Runtime(c.Runtime),
 	}
 }
 
@@ -166,8 +422,11 @@ func (c JobConfig) getIdentifyCloudActorsConfig() identifyCloudActors.Config {
 
 func (c JobConfig) getManagedResourceDriftDetectorConfig() driftDetector.ManagedResourceDriftDetectorConfig {
 	return driftDetector.ManagedResourceDriftDetectorConfig{
-		ResourcesWhiteList: c.ResourcesWhiteList,
-		ResourcesBlackList: c.ResourcesBlackList,
+		ResourcesWhiteList:   c.ResourcesWhiteList,
+		ResourcesBlackList:   c.ResourcesBlackList,
+		DriftDetectionEngine: c.DriftDetectionEngine,
+		SeverityOverrides:    c.DriftSeverityOverrides,
+		Runtime:              terraformValueObjects.Runtime(c.Runtime),
 	}
 }
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"strings"
@@ -35,7 +36,7 @@ func getInferredData(config JobConfig) (InferredData, error) {
 		return InferredData{}, fmt.Errorf("[error getting the provider value from provider version]%w", err)
 	}
 
-	vcsSystem, err := getVCSSystemFromRepoURL(config.VCSRepo)
+	vcsSystem, err := getVCSSystemFromRepoURL(config.VCSRepo, config.VCSBaseURL)
 	if err != nil {
 		return InferredData{}, fmt.Errorf("[error getting vcs system from repo url]%w", err)
 	}
@@ -211,10 +212,42 @@ func getProviderFromProviderVersion(provider map[terraformValueObjects.Provider]
 	return "", fmt.Errorf("no provider found in map")
 }
 
-// getVCSSystemFromRepoURL determines the VCS system from the input repo URL
-func getVCSSystemFromRepoURL(repoURL string) (string, error) {
+// getVCSSystemFromRepoURL determines the VCS system from the input repo URL. Repositories given as an SSH URL are
+// accessed as a generic git server. Repositories on github.com, on the host of a configured GitHub Enterprise Server
+// API, or on a host named like "github.example.com" use GitHub.
+func getVCSSystemFromRepoURL(repoURL string, vcsBaseURL string) (string, error) {
+	if isSSHRepoURL(repoURL) {
+		return "git", nil
+	}
+
 	if strings.Contains(repoURL, "github.com/") {
 		return "github", nil
 	}
+
+	repoHost := urlHost(repoURL)
+	if repoHost != "" && vcsBaseURL != "" && repoHost == urlHost(vcsBaseURL) {
+		return "github", nil
+	}
+
+	if strings.HasPrefix(repoHost, "github.") {
+		return "github", nil
+	}
 	return "", fmt.Errorf("VCS system inferred from %v repo is not supported", repoURL)
 }
+
+// sshRepoURLRegex matches scp-like SSH repository URLs, such as git@git.example.com:org/repo.git.
+var sshRepoURLRegex = regexp.MustCompile(`^[^/@:]+@[^/:]+:`)
+
+// isSSHRepoURL returns whether the repository URL is an SSH URL, either ssh:// or scp-like.
+func isSSHRepoURL(repoURL string) bool {
+	return strings.HasPrefix(repoURL, "ssh://") || sshRepoURLRegex.MatchString(repoURL)
+}
+
+// urlHost returns the lower-cased host name of a URL, or an empty string when the URL cannot be parsed.
+func urlHost(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsedURL.Hostname())
+}
@@ -106,6 +106,35 @@ func Test_getInferredData(t *testing.T) {
 	}
 }
 
+func Test_getVCSSystemFromRepoURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		repoURL    string
+		vcsBaseURL string
+		want       string
+		wantErr    bool
+	}{
+		{name: "github.com", repoURL: "https://github.com/test-org/test-repo.git", want: "github"},
+		{name: "configured enterprise host", repoURL: "https://git.example.com/test-org/test-repo.git", vcsBaseURL: "https://git.example.com/api/v3/", want: "github"},
+		{name: "inferred enterprise host", repoURL: "https://github.example.com/test-org/test-repo.git", want: "github"},
+		{name: "scp-like ssh url", repoURL: "git@gitea.example.com:test-org/test-repo.git", want: "git"},
+		{name: "ssh url", repoURL: "ssh://git@github.com/test-org/test-repo.git", want: "git"},
+		{name: "unsupported host", repoURL: "https://gitlab.com/test-org/test-repo.git", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getVCSSystemFromRepoURL(tt.repoURL, tt.vcsBaseURL)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equalf(t, tt.want, got, "getVCSSystemFromRepoURL(%v, %v)", tt.repoURL, tt.vcsBaseURL)
+		})
+	}
+}
+
 func Test_parseAWSCredentialValues(t *testing.T) {
 	// Given
 	inputCredentialBytes := []byte(
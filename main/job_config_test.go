@@ -8,7 +8,10 @@ import (
 	"github.com/dragondrop-cloud/cloud-concierge/main/internal/hclcreate"
 	costEstimation "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/cost_estimation"
 	identifyCloudActors "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identify_cloud_actors"
+	identityMapping "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/identity_mapping"
+	resourcesWriter "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_writer"
 	terraformImportMigrationGenerator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_import_migration_generator"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	terraformValueObjects "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_value_objects"
 	terraformWorkspace "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_workspace"
 	terraformerCli "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraformer_executor/terraformer_cli"
@@ -17,16 +20,34 @@ import (
 
 func validJobConfig() *JobConfig {
 	return &JobConfig{
-		IsManagedDriftOnly: false,
-		CloudRegions:       terraformValueObjects.CloudRegionsDecoder{"us-east1"},
-		CloudCredential:    "{}",
-		JobID:              "JobID",
+		IsManagedDriftOnly:   false,
+		DriftDetectionEngine: "plan-refresh-only",
+		DriftSeverityOverrides: driftDetector.SeverityRules{
+			{ResourceType: "aws_lb", Attribute: "idle_timeout", Severity: driftDetector.SeverityHigh},
+		},
+		CloudRegions:    terraformValueObjects.CloudRegionsDecoder{"us-east1"},
+		CloudCredential: "{}",
+		JobID:           "JobID",
 		MigrationHistoryStorage: hclcreate.MigrationHistory{
 			StorageType: "S3",
 			Bucket:      "Bucket",
 			Region:      "Region",
 		},
-		TerraformVersion:           "TerraformVersion",
+		TerraformVersion:       "TerraformVersion",
+		ResourceNameTemplate:   "{{.ShortType}}_{{index .Tags \"Name\"}}",
+		DriftRemediation:       "accept-cloud-state",
+		OrphanedResourceBlocks: "delete",
+		CodeGeneration:         "generate-config-out",
+		ValidateGeneratedCode:  "plan",
+		Runtime:                "opentofu",
+		OutputFormat:           "json",
+		PullRequestStrategy:    "category",
+		CloudActorNotification: "review",
+		IdentityMappingFile:    ".github/identities.json",
+		EmailIdentityMatching:  true,
+		ModulePatterns: hclcreate.ModulePatterns{
+			{Name: "s3-bucket", PrimaryResourceType: "aws_s3_bucket"},
+		},
 		StateBackend:               "StateBackend",
 		TerraformCloudOrganization: "TerraformCloudOrganization",
 		TerraformCloudToken:        "TerraformCloudToken",
@@ -34,12 +55,19 @@ func validJobConfig() *JobConfig {
 		Provider: map[terraformValueObjects.Provider]string{
 			"aws": "~>4.57.0",
 		},
-		VCSRepo:            "VCSRepo",
-		VCSPat:             "xyz",
-		InfracostToken:     "ico-mytoken",
-		PullReviewers:      []string{"PullReviewer1", "PullReviewer2"},
-		ResourcesWhiteList: terraformValueObjects.ResourceNameList{"Resource1", "Resource2"},
-		ResourcesBlackList: terraformValueObjects.ResourceNameList{"Resource3", "Resource4"},
+		VCSRepo:               "VCSRepo",
+		VCSPat:                "xyz",
+		VCSBaseURL:            "https://github.example.com/api/v3/",
+		VCSSSHKnownHosts:      "/etc/ssh/ssh_known_hosts",
+		ReviewRequestCommand:  "./open-review.sh",
+		CommitAuthorName:      "Infra Bot",
+		CommitAuthorEmail:     "infra-bot@example.com",
+		CommitMessageTemplate: "chore(drift): {{.JobName}} ({{.RunID}})",
+		CommitSigningKey:      "/secrets/signing-key",
+		InfracostToken:        "ico-mytoken",
+		PullReviewers:         []string{"PullReviewer1", "PullReviewer2"},
+		ResourcesWhiteList:    terraformValueObjects.ResourceNameList{"Resource1", "Resource2"},
+		ResourcesBlackList:    terraformValueObjects.ResourceNameList{"Resource3", "Resource4"},
 	}
 }
 
@@ -52,9 +80,26 @@ func TestGetVCSConfig(t *testing.T) {
 
 	// Then
 	want := vcs.Config{
-		VCSRepo:       jobConfig.VCSRepo,
-		VCSPat:        jobConfig.VCSPat,
-		PullReviewers: jobConfig.PullReviewers,
+		VCSRepo:                    jobConfig.VCSRepo,
+		VCSPat:                     jobConfig.VCSPat,
+		VCSBaseURL:                 jobConfig.VCSBaseURL,
+		GitHubAppID:                jobConfig.GitHubAppID,
+		GitHubAppInstallationID:    jobConfig.GitHubAppInstallationID,
+		GitHubAppPrivateKey:        jobConfig.GitHubAppPrivateKey,
+		VCSSSHKey:                  jobConfig.VCSSSHKey,
+		VCSSSHKeyPassphrase:        jobConfig.VCSSSHKeyPassphrase,
+		VCSSSHKnownHosts:           jobConfig.VCSSSHKnownHosts,
+		ReviewRequestWebhook:       jobConfig.ReviewRequestWebhook,
+		ReviewRequestCommand:       jobConfig.ReviewRequestCommand,
+		CommitAuthorName:           jobConfig.CommitAuthorName,
+		CommitAuthorEmail:          jobConfig.CommitAuthorEmail,
+		CommitterName:              jobConfig.CommitterName,
+		CommitterEmail:             jobConfig.CommitterEmail,
+		CommitMessageTemplate:      jobConfig.CommitMessageTemplate,
+		CommitSigningKey:           jobConfig.CommitSigningKey,
+		CommitSigningKeyPassphrase: jobConfig.CommitSigningKeyPassphrase,
+		RunID:                      jobConfig.JobID,
+		PullReviewers:              jobConfig.PullReviewers,
 	}
 
 	assert.Equal(t, want, got, "VCS Config should be equal")
@@ -91,11 +136,54 @@ func TestGetHCLCreateConfig(t *testing.T) {
 	want := hclcreate.Config{
 		MigrationHistoryStorage: jobConfig.MigrationHistoryStorage,
 		TerraformVersion:        jobConfig.TerraformVersion,
+		ResourceNameTemplate:    jobConfig.ResourceNameTemplate,
+		Division:                string(jobConfig.Division),
+		ModulePatterns:          jobConfig.ModulePatterns,
+		ParameterizeResources:   jobConfig.ParameterizeResources,
+		DriftRemediation:        jobConfig.DriftRemediation,
+		OrphanedResourceBlocks:  jobConfig.OrphanedResourceBlocks,
+		CodeGeneration:          jobConfig.CodeGeneration,
+		ValidateGeneratedCode:   jobConfig.ValidateGeneratedCode,
+		Runtime:                 terraformValueObjects.OpenTofuRuntime,
+		OutputFormat:            jobConfig.OutputFormat,
 	}
 
 	assert.Equal(t, want, got, "HCLCreateConfig should be equal")
 }
 
+func TestGetIdentityMappingConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getIdentityMappingConfig()
+
+	// Then
+	want := identityMapping.Config{
+		IdentityMappingFile: ".github/identities.json",
+		EmailMatching:       true,
+		Notification:        identityMapping.ReviewNotification,
+	}
+
+	assert.Equal(t, want, got, "IdentityMappingConfig should be equal")
+}
+
+func TestGetResourcesWriterConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getResourcesWriterConfig()
+
+	// Then
+	want := resourcesWriter.Config{
+		JobName:             jobConfig.JobName,
+		PullRequestStrategy: resourcesWriter.CategoryPullRequestStrategy,
+	}
+
+	assert.Equal(t, want, got, "ResourcesWriterConfig should be equal")
+}
+
 func TestGetTerraformerConfig(t *testing.T) {
 	// Given
 	jobConfig := validJobConfig()
@@ -109,6 +197,7 @@ func TestGetTerraformerConfig(t *testing.T) {
 		Provider:         jobConfig.Provider,
 		TerraformVersion: terraformValueObjects.Version(jobConfig.TerraformVersion),
 		CloudRegions:     jobConfig.CloudRegions,
+		Runtime:          terraformValueObjects.OpenTofuRuntime,
 	}
 
 	assert.Equal(t, want, got, "TerraformerExecutorConfig should be equal")
@@ -125,6 +214,7 @@ func TestGetTerraformerCLIConfig(t *testing.T) {
 	want := terraformerCli.Config{
 		ResourcesWhiteList: jobConfig.ResourcesWhiteList,
 		ResourcesBlackList: jobConfig.ResourcesBlackList,
+		Runtime:            terraformValueObjects.OpenTofuRuntime,
 	}
 
 	assert.Equal(t, want, got, "TerraformerCLIConfig should be equal")
@@ -175,3 +265,221 @@ func TestGetIdentifyCloudActorsConfig(t *testing.T) {
 
 	assert.Equal(t, want, got, "IdentifyCloudActorsConfig should be equal")
 }
+
+func TestGetManagedResourceDriftDetectorConfig(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+
+	// When
+	got := jobConfig.getManagedResourceDriftDetectorConfig()
+
+	// Then
+	want := driftDetector.ManagedResourceDriftDetectorConfig{
+		ResourcesWhiteList:   terraformValueObjects.ResourceNameList{"Resource1", "Resource2"},
+		ResourcesBlackList:   terraformValueObjects.ResourceNameList{"Resource3", "Resource4"},
+		DriftDetectionEngine: "plan-refresh-only",
+		SeverityOverrides: driftDetector.SeverityRules{
+			{ResourceType: "aws_lb", Attribute: "idle_timeout", Severity: driftDetector.SeverityHigh},
+		},
+		Runtime: terraformValueObjects.OpenTofuRuntime,
+	}
+
+	assert.Equal(t, want, got, "ManagedResourceDriftDetectorConfig should be equal")
+}
+
+func TestValidateJobConfig_InvalidDriftDetectionEngine(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DriftDetectionEngine = "unknown"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidDriftRemediation(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.DriftRemediation = "accept-terraform-state"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidOrphanedResourceBlocks(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.OrphanedResourceBlocks = "archive"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidCodeGeneration(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.CodeGeneration = "cdktf"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidValidateGeneratedCode(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.ValidateGeneratedCode = "apply"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidRuntime(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.Runtime = "pulumi"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidOutputFormat(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.OutputFormat = "yaml"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidCloudActorNotification(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.CloudActorNotification = "email"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidPullRequestStrategy(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.PullRequestStrategy = "resource"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_InvalidResourceNameTemplate(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.ResourceNameTemplate = "{{.Tags"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_MissingVCSCredentials(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.VCSPat = ""
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_GitHubAppWithoutPrivateKey(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.VCSPat = ""
+	jobConfig.GitHubAppID = 1234
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_GitHubApp(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.VCSPat = ""
+	jobConfig.GitHubAppID = 1234
+	jobConfig.GitHubAppPrivateKey = "/secrets/github-app.pem"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestValidateJobConfig_SSHRepoWithoutKey(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.VCSRepo = "git@gitea.example.com:org/infrastructure.git"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
+
+func TestValidateJobConfig_SSHRepo(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.VCSRepo = "git@gitea.example.com:org/infrastructure.git"
+	jobConfig.VCSPat = ""
+	jobConfig.VCSSSHKey = "/secrets/deploy-key"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.Nil(t, err)
+}
+
+func TestValidateJobConfig_InvalidCommitMessageTemplate(t *testing.T) {
+	// Given
+	jobConfig := validJobConfig()
+	jobConfig.CommitMessageTemplate = "{{.JobName"
+
+	// When
+	err := validateJobConfig(*jobConfig)
+
+	// Then
+	assert.NotNil(t, err)
+}
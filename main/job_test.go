@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	resourcesCalculator "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/resources_calculator"
+	driftDetector "github.com/dragondrop-cloud/cloud-concierge/main/internal/implementations/terraform_managed_resources_drift_detector/drift_detector"
 	. "github.com/dragondrop-cloud/cloud-concierge/main/internal/interfaces"
 )
 
@@ -20,6 +22,7 @@ type JobDependenciesMock struct {
 	resourcesCalculator               *ResourcesCalculatorMock
 	resourcesWriter                   *ResourcesWriterMock
 	identifyCloudActors               *IdentifyCloudActorsMock
+	identityMapper                    *IdentityMapperMock
 	costEstimator                     *CostEstimationMock
 	driftDetector                     *TerraformManagedResourcesDriftDetectorMock
 	terraformSecurity                 *TerraformSecurityMock
@@ -33,6 +36,7 @@ func createValidJob(_ *testing.T) (*JobDependenciesMock, *Job) {
 	resourcesCalculator := new(ResourcesCalculatorMock)
 	resourcesWriter := new(ResourcesWriterMock)
 	identifyCloudActors := new(IdentifyCloudActorsMock)
+	identityMapper := new(IdentityMapperMock)
 	costEstimator := new(CostEstimationMock)
 	driftDetector := new(TerraformManagedResourcesDriftDetectorMock)
 	tfSec := new(TerraformSecurityMock)
@@ -49,6 +53,7 @@ func createValidJob(_ *testing.T) (*JobDependenciesMock, *Job) {
 		terraformWorkspace:                terraformWorkspace,
 		vcs:                               vcs,
 		identifyCloudActors:               identifyCloudActors,
+		identityMapper:                    identityMapper,
 		driftDetector:                     driftDetector,
 		terraformSecurity:                 tfSec,
 	}
@@ -62,6 +67,7 @@ func createValidJob(_ *testing.T) (*JobDependenciesMock, *Job) {
 		resourcesCalculator:               resourcesCalculator,
 		resourcesWriter:                   resourcesWriter,
 		identifyCloudActors:               identifyCloudActors,
+		identityMapper:                    identityMapper,
 		driftDetector:                     driftDetector,
 		terraformSecurity:                 tfSec,
 	}, job
@@ -80,6 +86,7 @@ func TestRunJob_Success(t *testing.T) {
 	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", nil)
 	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
@@ -95,6 +102,7 @@ func TestRunJob_Success(t *testing.T) {
 	mocks.terraformImportMigrationGenerator.AssertNumberOfCalls(t, "Execute", 1)
 	mocks.resourcesCalculator.AssertNumberOfCalls(t, "Execute", 1)
 	mocks.identifyCloudActors.AssertNumberOfCalls(t, "Execute", 1)
+	mocks.identityMapper.AssertNumberOfCalls(t, "Execute", 1)
 	mocks.costEstimator.AssertNumberOfCalls(t, "Execute", 1)
 	mocks.resourcesWriter.AssertNumberOfCalls(t, "Execute", 1)
 	mocks.terraformSecurity.AssertNumberOfCalls(t, "ExecuteScan", 1)
@@ -114,6 +122,7 @@ func TestRunJob_CannotCloneRepo(t *testing.T) {
 	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", nil)
 
@@ -150,6 +159,7 @@ func TestRunJob_CannotDownloadWorkspaceState(t *testing.T) {
 	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", nil)
 	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
@@ -187,6 +197,7 @@ func TestRunJob_CannotExecuteTerraformerExecutor(t *testing.T) {
 	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", nil)
 
@@ -223,6 +234,7 @@ func TestRunJob_CannotExecuteTerraformImportMigrationGenerator(t *testing.T) {
 	mocks.terraformImportMigrationGenerator.On("Execute").Return(terraformImportMigrationGeneratorErr)
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", nil)
 
@@ -259,6 +271,7 @@ func TestRunJob_CannotCalculateResources(t *testing.T) {
 	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
 	mocks.resourcesCalculator.On("Execute").Return(calculateResourcesErr)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", nil)
 
@@ -297,6 +310,7 @@ func TestRunJob_CannotDriftDetect(t *testing.T) {
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(false, managedDriftDetectErr)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute", ctx).Return("", nil)
 
@@ -356,6 +370,40 @@ func TestRunJob_CannotIdentifyCloudActors(t *testing.T) {
 	mocks.terraformSecurity.AssertNumberOfCalls(t, "ExecuteScan", 0)
 }
 
+func TestRunJob_CannotMapIdentities(t *testing.T) {
+	// Given
+	mocks, job := createValidJob(t)
+	ctx := context.Background()
+	divisionToProvider := make(map[string]string)
+
+	identityMapperErr := errors.New("cannot map identities")
+
+	// When
+	mocks.vcs.On("Clone").Return(nil)
+	mocks.terraformWorkspace.On("FindTerraformWorkspaces", ctx).Return(divisionToProvider, nil)
+	mocks.terraformWorkspace.On("DownloadWorkspaceState").Return(nil)
+	mocks.terraformerExecutor.On("Execute").Return(nil)
+	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
+	mocks.resourcesCalculator.On("Execute").Return(nil)
+	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
+	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(identityMapperErr)
+	mocks.costEstimator.On("Execute").Return(nil)
+	mocks.resourcesWriter.On("Execute", ctx).Return("", nil)
+
+	err := job.Run(ctx)
+
+	// Then
+	assert.NotNil(t, err)
+	assert.ErrorIs(t, identityMapperErr, errors.Unwrap(err))
+
+	mocks.identifyCloudActors.AssertNumberOfCalls(t, "Execute", 1)
+	mocks.identityMapper.AssertNumberOfCalls(t, "Execute", 1)
+	mocks.costEstimator.AssertNumberOfCalls(t, "Execute", 0)
+	mocks.resourcesWriter.AssertNumberOfCalls(t, "Execute", 0)
+	mocks.terraformSecurity.AssertNumberOfCalls(t, "ExecuteScan", 0)
+}
+
 func TestRunJob_CannotCostEstimate(t *testing.T) {
 	// Given
 	mocks, job := createValidJob(t)
@@ -373,6 +421,7 @@ func TestRunJob_CannotCostEstimate(t *testing.T) {
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(costEstimationErr)
 	mocks.resourcesWriter.On("Execute", ctx).Return("", nil)
 
@@ -411,6 +460,7 @@ func TestRunJob_CannotSecurityScan(t *testing.T) {
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.terraformSecurity.On("ExecuteScan", ctx).Return(securityScanErr)
 	mocks.resourcesWriter.On("Execute", ctx).Return("", nil)
@@ -450,6 +500,7 @@ func TestRunJob_CannotWriteResourcesOnVCS(t *testing.T) {
 	mocks.resourcesCalculator.On("Execute").Return(nil)
 	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", writeResourcesErr)
 	mocks.driftDetector.On("Execute", ctx).Return(true, nil)
@@ -490,6 +541,7 @@ func TestRunJob_NotFoundNewResources_ButFoundManagedDriftedResources(t *testing.
 	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
 	mocks.resourcesCalculator.On("Execute").Return(calculateResourcesErr)
 	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
 	mocks.costEstimator.On("Execute").Return(nil)
 	mocks.resourcesWriter.On("Execute").Return("", nil)
 	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
@@ -510,3 +562,39 @@ func TestRunJob_NotFoundNewResources_ButFoundManagedDriftedResources(t *testing.
 	mocks.resourcesWriter.AssertNumberOfCalls(t, "Execute", 1)
 	mocks.terraformSecurity.AssertNumberOfCalls(t, "ExecuteScan", 1)
 }
+
+func TestRunJob_DriftSeverityThresholdExceeded(t *testing.T) {
+	// Given
+	mocks, job := createValidJob(t)
+	job.config.FailOnDriftSeverity = driftDetector.SeverityHigh
+	ctx := context.Background()
+	divisionToProvider := make(map[string]string)
+
+	err := os.Mkdir("outputs", 0o755)
+	assert.Nil(t, err)
+	defer os.RemoveAll("outputs")
+
+	differences := `[{"AttributeName": "tags.Name", "Severity": "low"}, {"AttributeName": "policy", "Severity": "critical"}]`
+	err = os.WriteFile("outputs/drift-resources-differences.json", []byte(differences), 0o600)
+	assert.Nil(t, err)
+
+	mocks.vcs.On("Clone").Return(nil)
+	mocks.terraformWorkspace.On("FindTerraformWorkspaces", ctx).Return(divisionToProvider, nil)
+	mocks.terraformWorkspace.On("DownloadWorkspaceState").Return(nil)
+	mocks.terraformerExecutor.On("Execute").Return(nil)
+	mocks.terraformImportMigrationGenerator.On("Execute").Return(nil)
+	mocks.resourcesCalculator.On("Execute").Return(nil)
+	mocks.identifyCloudActors.On("Execute", ctx).Return(nil)
+	mocks.identityMapper.On("Execute", ctx).Return(nil)
+	mocks.costEstimator.On("Execute").Return(nil)
+	mocks.resourcesWriter.On("Execute").Return("", nil)
+	mocks.driftDetector.On("Execute", ctx, divisionToProvider).Return(true, nil)
+	mocks.terraformSecurity.On("ExecuteScan", ctx).Return(nil)
+
+	// When
+	err = job.Run(ctx)
+
+	// Then
+	assert.ErrorIs(t, err, ErrDriftSeverityThresholdExceeded)
+	mocks.resourcesWriter.AssertNumberOfCalls(t, "Execute", 1)
+}